@@ -1 +1,416 @@
 package bplus_tree_index
+
+import (
+	"bytes"
+
+	"gobase/table_heap"
+)
+
+func (t *BPlusTree) Get(key []byte) (*table_heap.RID, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	_, leaf, err := t.findLeaf(key)
+	if err != nil {
+		return nil, err
+	}
+
+	index := leaf.lowerBound(key)
+	if index >= len(leaf.keys) || !bytes.Equal(leaf.keys[index], key) {
+		return nil, ErrKeyNotFound
+	}
+
+	rid := leaf.rids[index]
+	return &rid, nil
+}
+
+func (t *BPlusTree) Update(key []byte, rid table_heap.RID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pageID, leaf, err := t.findLeaf(key)
+	if err != nil {
+		return err
+	}
+
+	index := leaf.lowerBound(key)
+	if index >= len(leaf.keys) || !bytes.Equal(leaf.keys[index], key) {
+		return ErrKeyNotFound
+	}
+
+	leaf.rids[index] = rid
+	return t.writeNode(pageID, leaf)
+}
+
+func (t *BPlusTree) Insert(key []byte, rid table_heap.RID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := validateKey(key)
+	if err != nil {
+		return err
+	}
+
+	separator, newPageID, err := t.insert(t.rootPageID, key, rid)
+	if err != nil {
+		return err
+	}
+
+	if separator == nil {
+		return nil
+	}
+
+	newRoot := newInternalNode()
+	newRoot.keys = [][]byte{separator}
+	newRoot.children = []uint32{t.rootPageID, newPageID}
+
+	newRootPageID, err := t.allocateNode(newRoot)
+	if err != nil {
+		return err
+	}
+
+	return t.setRootPageID(newRootPageID)
+}
+
+func (t *BPlusTree) insert(pageID uint32, key []byte, rid table_heap.RID) ([]byte, uint32, error) {
+	n, err := t.readNode(pageID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if n.isLeaf {
+		index := n.lowerBound(key)
+		if index < len(n.keys) && bytes.Equal(n.keys[index], key) {
+			return nil, 0, ErrDuplicateKey
+		}
+
+		n.keys = insertAt(n.keys, index, bytes.Clone(key))
+		n.rids = insertAt(n.rids, index, rid)
+	} else {
+		childIndex := n.childIndex(key)
+
+		separator, newChildPageID, err := t.insert(n.children[childIndex], key, rid)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if separator == nil {
+			return nil, 0, nil
+		}
+
+		n.keys = insertAt(n.keys, childIndex, separator)
+		n.children = insertAt(n.children, childIndex+1, newChildPageID)
+	}
+
+	if !n.isOverflowing(t.bpm.GetPageSize()) {
+		return nil, 0, t.writeNode(pageID, n)
+	}
+
+	return t.split(pageID, n)
+}
+
+func (t *BPlusTree) split(pageID uint32, n *node) ([]byte, uint32, error) {
+	index := n.splitIndex()
+
+	var separator []byte
+	var sibling *node
+
+	if n.isLeaf {
+		sibling = newLeafNode()
+		sibling.keys = append(sibling.keys, n.keys[index:]...)
+		sibling.rids = append(sibling.rids, n.rids[index:]...)
+		sibling.nextPageID = n.nextPageID
+
+		n.keys = n.keys[:index]
+		n.rids = n.rids[:index]
+
+		separator = bytes.Clone(sibling.keys[0])
+	} else {
+		sibling = newInternalNode()
+		sibling.keys = append(sibling.keys, n.keys[index+1:]...)
+		sibling.children = append(sibling.children, n.children[index+1:]...)
+
+		separator = n.keys[index]
+
+		n.keys = n.keys[:index]
+		n.children = n.children[:index+1]
+	}
+
+	siblingPageID, err := t.allocateNode(sibling)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if n.isLeaf {
+		n.nextPageID = siblingPageID
+	}
+
+	err = t.writeNode(pageID, n)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return separator, siblingPageID, nil
+}
+
+func (t *BPlusTree) Delete(key []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, err := t.delete(t.rootPageID, key)
+	if err != nil {
+		return err
+	}
+
+	root, err := t.readNode(t.rootPageID)
+	if err != nil {
+		return err
+	}
+
+	if !root.isLeaf && len(root.keys) == 0 {
+		oldRootPageID := t.rootPageID
+
+		err = t.setRootPageID(root.children[0])
+		if err != nil {
+			return err
+		}
+
+		return t.bpm.DeletePage(oldRootPageID)
+	}
+
+	return nil
+}
+
+func (t *BPlusTree) delete(pageID uint32, key []byte) (bool, error) {
+	n, err := t.readNode(pageID)
+	if err != nil {
+		return false, err
+	}
+
+	if n.isLeaf {
+		index := n.lowerBound(key)
+		if index >= len(n.keys) || !bytes.Equal(n.keys[index], key) {
+			return false, ErrKeyNotFound
+		}
+
+		n.keys = removeAt(n.keys, index)
+		n.rids = removeAt(n.rids, index)
+
+		return n.isUnderflowing(t.bpm.GetPageSize()), t.writeNode(pageID, n)
+	}
+
+	childIndex := n.childIndex(key)
+
+	underflow, err := t.delete(n.children[childIndex], key)
+	if err != nil {
+		return false, err
+	}
+
+	if !underflow {
+		return false, nil
+	}
+
+	changed, mergedPageID, err := t.rebalance(n, childIndex)
+	if err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	err = t.writeNode(pageID, n)
+	if err != nil {
+		return false, err
+	}
+
+	// The merged node is freed only once its parent no longer points at it.
+	if mergedPageID != INVALID_PAGE_ID {
+		err = t.bpm.DeletePage(mergedPageID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return n.isUnderflowing(t.bpm.GetPageSize()), nil
+}
+
+// rebalance also returns the page of a node merged into its left sibling, or
+// INVALID_PAGE_ID when no node was merged.
+func (t *BPlusTree) rebalance(parent *node, childIndex int) (bool, uint32, error) {
+	separatorIndex := childIndex
+	if childIndex > 0 {
+		separatorIndex = childIndex - 1
+	}
+
+	leftPageID := parent.children[separatorIndex]
+	rightPageID := parent.children[separatorIndex+1]
+
+	left, err := t.readNode(leftPageID)
+	if err != nil {
+		return false, INVALID_PAGE_ID, err
+	}
+
+	right, err := t.readNode(rightPageID)
+	if err != nil {
+		return false, INVALID_PAGE_ID, err
+	}
+
+	separator := parent.keys[separatorIndex]
+
+	if canMerge(left, right, separator, t.bpm.GetPageSize()) {
+		merge(left, right, separator)
+
+		parent.keys = removeAt(parent.keys, separatorIndex)
+		parent.children = removeAt(parent.children, separatorIndex+1)
+
+		return true, rightPageID, t.writeNode(leftPageID, left)
+	}
+
+	newSeparator, ok := redistribute(left, right, separator, childIndex == separatorIndex, t.bpm.GetPageSize())
+	if !ok {
+		return false, INVALID_PAGE_ID, nil
+	}
+
+	parent.keys[separatorIndex] = newSeparator
+	if parent.isOverflowing(t.bpm.GetPageSize()) {
+		parent.keys[separatorIndex] = separator
+		return false, INVALID_PAGE_ID, nil
+	}
+
+	err = t.writeNode(leftPageID, left)
+	if err != nil {
+		return false, INVALID_PAGE_ID, err
+	}
+
+	err = t.writeNode(rightPageID, right)
+	if err != nil {
+		return false, INVALID_PAGE_ID, err
+	}
+
+	return true, INVALID_PAGE_ID, nil
+}
+
+func canMerge(left *node, right *node, separator []byte, pageSize uint32) bool {
+	size := left.size() + right.size() - NODE_HEADER_SIZE
+	if !left.isLeaf {
+		size += KEY_LENGTH_SIZE + uint32(len(separator))
+	}
+
+	return size <= pageSize
+}
+
+func merge(left *node, right *node, separator []byte) {
+	if left.isLeaf {
+		left.keys = append(left.keys, right.keys...)
+		left.rids = append(left.rids, right.rids...)
+		left.nextPageID = right.nextPageID
+		return
+	}
+
+	left.keys = append(left.keys, separator)
+	left.keys = append(left.keys, right.keys...)
+	left.children = append(left.children, right.children...)
+}
+
+func redistribute(left *node, right *node, separator []byte, fillLeft bool, pageSize uint32) ([]byte, bool) {
+	moved := false
+
+	for {
+		if fillLeft {
+			if !left.isUnderflowing(pageSize) || len(right.keys) <= 1 {
+				break
+			}
+			separator = moveFirstToLeft(left, right, separator)
+		} else {
+			if !right.isUnderflowing(pageSize) || len(left.keys) <= 1 {
+				break
+			}
+			separator = moveLastToRight(left, right, separator)
+		}
+
+		moved = true
+
+		if fillLeft && right.isUnderflowing(pageSize) || !fillLeft && left.isUnderflowing(pageSize) {
+			break
+		}
+	}
+
+	return separator, moved
+}
+
+func moveFirstToLeft(left *node, right *node, separator []byte) []byte {
+	if left.isLeaf {
+		left.keys = append(left.keys, right.keys[0])
+		left.rids = append(left.rids, right.rids[0])
+		right.keys = removeAt(right.keys, 0)
+		right.rids = removeAt(right.rids, 0)
+		return bytes.Clone(right.keys[0])
+	}
+
+	left.keys = append(left.keys, separator)
+	left.children = append(left.children, right.children[0])
+	newSeparator := right.keys[0]
+	right.keys = removeAt(right.keys, 0)
+	right.children = removeAt(right.children, 0)
+	return newSeparator
+}
+
+func moveLastToRight(left *node, right *node, separator []byte) []byte {
+	last := len(left.keys) - 1
+
+	if left.isLeaf {
+		right.keys = insertAt(right.keys, 0, left.keys[last])
+		right.rids = insertAt(right.rids, 0, left.rids[last])
+		left.keys = left.keys[:last]
+		left.rids = left.rids[:last]
+		return bytes.Clone(right.keys[0])
+	}
+
+	right.keys = insertAt(right.keys, 0, separator)
+	right.children = insertAt(right.children, 0, left.children[last+1])
+	newSeparator := left.keys[last]
+	left.keys = left.keys[:last]
+	left.children = left.children[:last+1]
+	return newSeparator
+}
+
+func (t *BPlusTree) Scan(start []byte, end []byte) *IndexIterator {
+	return &IndexIterator{
+		tree:   t,
+		start:  start,
+		end:    end,
+		pageID: INVALID_PAGE_ID,
+	}
+}
+
+func (t *BPlusTree) Drop() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.dropNode(t.rootPageID)
+	if err != nil {
+		return err
+	}
+
+	t.rootPageID = INVALID_PAGE_ID
+	return t.bpm.DeletePage(t.headerPageID)
+}
+
+func (t *BPlusTree) dropNode(pageID uint32) error {
+	n, err := t.readNode(pageID)
+	if err != nil {
+		return err
+	}
+
+	if !n.isLeaf {
+		for _, child := range n.children {
+			err = t.dropNode(child)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return t.bpm.DeletePage(pageID)
+}
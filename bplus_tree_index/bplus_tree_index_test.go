@@ -0,0 +1,326 @@
+package bplus_tree_index
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"gobase/disk_manager"
+	"gobase/shared"
+	"gobase/table_heap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(i int) []byte {
+	return []byte(fmt.Sprintf("key-%06d", i))
+}
+
+func testRID(i int) table_heap.RID {
+	return *table_heap.NewRID(uint32(i/100), uint16(i%100))
+}
+
+func TestInsertAndGet(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	require.NoError(t, tree.Insert([]byte("alice"), testRID(1)))
+	require.NoError(t, tree.Insert([]byte("bob"), testRID(2)))
+
+	rid, err := tree.Get([]byte("bob"))
+	require.NoError(t, err)
+	assert.Equal(t, testRID(2), *rid)
+
+	_, err = tree.Get([]byte("charlie"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestInsert_DuplicateKey(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	require.NoError(t, tree.Insert([]byte("alice"), testRID(1)))
+
+	err := tree.Insert([]byte("alice"), testRID(2))
+	require.ErrorIs(t, err, ErrDuplicateKey)
+}
+
+func TestUpdate(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	require.NoError(t, tree.Insert([]byte("alice"), testRID(1)))
+	require.NoError(t, tree.Update([]byte("alice"), testRID(2)))
+
+	rid, err := tree.Get([]byte("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, testRID(2), *rid)
+
+	err = tree.Update([]byte("bob"), testRID(3))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestInsert_InvalidKey(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	err := tree.Insert(nil, testRID(1))
+	require.ErrorIs(t, err, ErrEmptyKey)
+
+	err = tree.Insert(make([]byte, MAX_KEY_SIZE+1), testRID(1))
+	require.ErrorIs(t, err, ErrKeyTooLarge)
+}
+
+func TestInsert_SplitsRoot(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	initialRoot := tree.GetRootPageID()
+
+	for i := 0; i < 5000; i++ {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	assert.NotEqual(t, initialRoot, tree.GetRootPageID())
+
+	for i := 0; i < 5000; i++ {
+		rid, err := tree.Get(testKey(i))
+		require.NoError(t, err)
+		assert.Equal(t, testRID(i), *rid)
+	}
+}
+
+func TestScan_Ordered(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	for _, i := range rand.New(rand.NewSource(1)).Perm(2000) {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	iter := tree.Scan(nil, nil)
+	count := 0
+	for {
+		key, rid, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, testKey(count), key)
+		assert.Equal(t, testRID(count), *rid)
+		count++
+	}
+
+	assert.Equal(t, 2000, count)
+	assert.NoError(t, iter.Err())
+}
+
+func TestScan_CorruptedLeaf(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	for i := 0; i < 2000; i++ {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	_, firstLeaf, err := tree.findFirstLeaf()
+	require.NoError(t, err)
+	require.NoError(t, tree.bpm.FlushAllPages())
+
+	dm := tree.bpm.GetDiskManager()
+	_, err = dm.File.WriteAt([]byte("bit rot"), int64(firstLeaf.nextPageID)*int64(dm.PageSize)+int64(shared.PAGE_HEADER_SIZE))
+	require.NoError(t, err)
+
+	iter := tree.Scan(nil, nil)
+	count := 0
+	for {
+		_, _, ok := iter.Next()
+		if !ok {
+			break
+		}
+		count++
+	}
+
+	assert.Equal(t, len(firstLeaf.keys), count)
+	assert.ErrorIs(t, iter.Err(), disk_manager.ErrPageCorrupted)
+}
+
+func TestScan_Range(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	for i := 0; i < 2000; i++ {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	iter := tree.Scan(testKey(500), testKey(1500))
+	expected := 500
+	for {
+		key, _, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, testKey(expected), key)
+		expected++
+	}
+
+	assert.Equal(t, 1500, expected)
+}
+
+func TestDelete(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	require.NoError(t, tree.Insert([]byte("alice"), testRID(1)))
+	require.NoError(t, tree.Delete([]byte("alice")))
+
+	_, err := tree.Get([]byte("alice"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	err = tree.Delete([]byte("alice"))
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestDelete_MergesAndCollapsesRoot(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	for i := 0; i < 5000; i++ {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	for _, i := range rand.New(rand.NewSource(2)).Perm(5000) {
+		if i == 42 {
+			continue
+		}
+		require.NoError(t, tree.Delete(testKey(i)))
+	}
+
+	root, err := tree.readNode(tree.GetRootPageID())
+	require.NoError(t, err)
+	assert.True(t, root.isLeaf)
+	assert.Equal(t, [][]byte{testKey(42)}, root.keys)
+}
+
+func TestDelete_ReusesFreedPages(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	dm := tree.bpm.GetDiskManager()
+	numPages := uint32(0)
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 5000; i++ {
+			require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+		}
+
+		if round == 0 {
+			numPages = dm.NumPages
+		}
+		assert.Equal(t, numPages, dm.NumPages, "round %d grew the file", round)
+
+		for _, i := range rand.New(rand.NewSource(int64(round))).Perm(5000) {
+			require.NoError(t, tree.Delete(testKey(i)))
+		}
+
+		root, err := tree.readNode(tree.GetRootPageID())
+		require.NoError(t, err)
+		assert.True(t, root.isLeaf)
+		assert.Empty(t, root.keys)
+	}
+}
+
+func TestDelete_KeepsRemainingKeysOrdered(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	for i := 0; i < 3000; i++ {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	for i := 0; i < 3000; i += 3 {
+		require.NoError(t, tree.Delete(testKey(i)))
+	}
+
+	iter := tree.Scan(nil, nil)
+	expected := 1
+	for {
+		key, _, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, testKey(expected), key)
+		expected++
+		if expected%3 == 0 {
+			expected++
+		}
+	}
+
+	assert.Equal(t, 3001, expected)
+}
+
+func TestOpenBPlusTree(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 10)
+	defer cleanup()
+
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, tree.Insert(testKey(i), testRID(i)))
+	}
+
+	reopened, err := OpenBPlusTree(tree.bpm, tree.GetHeaderPageID())
+	require.NoError(t, err)
+	assert.Equal(t, tree.GetRootPageID(), reopened.GetRootPageID())
+
+	rid, err := reopened.Get(testKey(999))
+	require.NoError(t, err)
+	assert.Equal(t, testRID(999), *rid)
+}
+
+func TestConcurrentInsertAndGet(t *testing.T) {
+	tree, cleanup := newTestBPlusTree(t, 32)
+	defer cleanup()
+
+	const workers = 8
+	const keysPerWorker = 250
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := w * keysPerWorker; i < (w+1)*keysPerWorker; i++ {
+				err := tree.Insert(testKey(i), testRID(i))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				rid, err := tree.Get(testKey(i))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if *rid != testRID(i) {
+					t.Errorf("key %d mapped to wrong rid", i)
+					return
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	count := 0
+	iter := tree.Scan(nil, nil)
+	for {
+		_, _, ok := iter.Next()
+		if !ok {
+			break
+		}
+		count++
+	}
+
+	assert.Equal(t, workers*keysPerWorker, count)
+}
@@ -0,0 +1,21 @@
+package bplus_tree_index
+
+const (
+	INVALID_PAGE_ID = uint32(0xFFFFFFFF)
+
+	ROOT_PAGE_ID_OFFSET uint32 = 12
+
+	NODE_TYPE_OFFSET    uint32 = 12
+	NUM_KEYS_OFFSET     uint32 = 14
+	NEXT_PAGE_ID_OFFSET uint32 = 16
+	NODE_HEADER_SIZE    uint32 = 20
+
+	NODE_TYPE_LEAF     uint8 = 1
+	NODE_TYPE_INTERNAL uint8 = 2
+
+	KEY_LENGTH_SIZE uint32 = 2
+	CHILD_SIZE      uint32 = 4
+	RID_SIZE        uint32 = 6
+
+	MAX_KEY_SIZE uint32 = 512
+)
@@ -0,0 +1,11 @@
+package bplus_tree_index
+
+import "errors"
+
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrDuplicateKey    = errors.New("duplicate key")
+	ErrKeyTooLarge     = errors.New("key too large")
+	ErrEmptyKey        = errors.New("key is empty")
+	ErrInvalidNodeType = errors.New("invalid node type")
+)
@@ -0,0 +1,307 @@
+package bplus_tree_index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"gobase/log_manager"
+	"gobase/shared"
+	"gobase/table_heap"
+)
+
+func (t *BPlusTree) GetHeaderPageID() uint32 {
+	return t.headerPageID
+}
+
+func (t *BPlusTree) GetRootPageID() uint32 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.rootPageID
+}
+
+func (t *BPlusTree) setRootPageID(rootPageID uint32) error {
+	frame, err := t.bpm.FetchPage(t.headerPageID)
+	if err != nil {
+		return err
+	}
+
+	frame.WLatch()
+	binary.LittleEndian.PutUint32(frame.Data[ROOT_PAGE_ID_OFFSET:], rootPageID)
+	err = t.logPageImage(t.headerPageID, frame.Data)
+	frame.WUnlatch()
+	t.bpm.UnpinPage(t.headerPageID, true)
+	if err != nil {
+		return err
+	}
+
+	t.rootPageID = rootPageID
+	return nil
+}
+
+func (t *BPlusTree) readNode(pageID uint32) (*node, error) {
+	frame, err := t.bpm.FetchPage(pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	frame.RLatch()
+	n, err := decodeNode(frame.Data)
+	frame.RUnlatch()
+	t.bpm.UnpinPage(pageID, false)
+
+	return n, err
+}
+
+func (t *BPlusTree) writeNode(pageID uint32, n *node) error {
+	frame, err := t.bpm.FetchPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	frame.WLatch()
+	encodeNode(n, frame.Data)
+	err = t.logPageImage(pageID, frame.Data)
+	frame.WUnlatch()
+	t.bpm.UnpinPage(pageID, true)
+
+	return err
+}
+
+func (t *BPlusTree) allocateNode(n *node) (uint32, error) {
+	pageID, frame, err := t.bpm.NewPage()
+	if err != nil {
+		return 0, err
+	}
+
+	frame.WLatch()
+	encodeNode(n, frame.Data)
+	err = t.logPageImage(pageID, frame.Data)
+	frame.WUnlatch()
+	t.bpm.UnpinPage(pageID, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return pageID, nil
+}
+
+func (t *BPlusTree) logPageImage(pageID uint32, data []byte) error {
+	logManager := t.bpm.GetLogManager()
+	if logManager == nil {
+		return nil
+	}
+
+	lsn, err := logManager.AppendRecord(&log_manager.LogRecord{
+		Type:   log_manager.RecordPageImage,
+		PageID: pageID,
+		Data:   data,
+	})
+	if err != nil {
+		return err
+	}
+
+	shared.SetPageLSN(data, lsn)
+	return nil
+}
+
+func (t *BPlusTree) findLeaf(key []byte) (uint32, *node, error) {
+	pageID := t.rootPageID
+
+	for {
+		n, err := t.readNode(pageID)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if n.isLeaf {
+			return pageID, n, nil
+		}
+
+		pageID = n.children[n.childIndex(key)]
+	}
+}
+
+func (t *BPlusTree) findFirstLeaf() (uint32, *node, error) {
+	pageID := t.rootPageID
+
+	for {
+		n, err := t.readNode(pageID)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if n.isLeaf {
+			return pageID, n, nil
+		}
+
+		pageID = n.children[0]
+	}
+}
+
+func validateKey(key []byte) error {
+	if len(key) == 0 {
+		return ErrEmptyKey
+	}
+
+	if uint32(len(key)) > MAX_KEY_SIZE {
+		return ErrKeyTooLarge
+	}
+
+	return nil
+}
+
+func (n *node) childIndex(key []byte) int {
+	return sort.Search(len(n.keys), func(i int) bool {
+		return bytes.Compare(n.keys[i], key) > 0
+	})
+}
+
+func (n *node) lowerBound(key []byte) int {
+	return sort.Search(len(n.keys), func(i int) bool {
+		return bytes.Compare(n.keys[i], key) >= 0
+	})
+}
+
+func (n *node) size() uint32 {
+	size := NODE_HEADER_SIZE
+
+	for _, key := range n.keys {
+		size += KEY_LENGTH_SIZE + uint32(len(key))
+	}
+
+	if n.isLeaf {
+		size += uint32(len(n.rids)) * RID_SIZE
+	} else {
+		size += uint32(len(n.children)) * CHILD_SIZE
+	}
+
+	return size
+}
+
+func (n *node) isOverflowing(pageSize uint32) bool {
+	return n.size() > pageSize
+}
+
+func (n *node) isUnderflowing(pageSize uint32) bool {
+	return n.size() < pageSize/2
+}
+
+func (n *node) splitIndex() int {
+	half := n.size() / 2
+	size := NODE_HEADER_SIZE
+
+	for i, key := range n.keys {
+		size += KEY_LENGTH_SIZE + uint32(len(key))
+		if n.isLeaf {
+			size += RID_SIZE
+		} else {
+			size += CHILD_SIZE
+		}
+
+		if size >= half {
+			if i == 0 {
+				return 1
+			}
+			return i
+		}
+	}
+
+	return len(n.keys) / 2
+}
+
+func insertAt[T any](items []T, index int, item T) []T {
+	items = append(items, item)
+	copy(items[index+1:], items[index:])
+	items[index] = item
+	return items
+}
+
+func removeAt[T any](items []T, index int) []T {
+	return append(items[:index], items[index+1:]...)
+}
+
+func encodeNode(n *node, data []byte) {
+	clear(data[NODE_TYPE_OFFSET:])
+
+	if n.isLeaf {
+		data[NODE_TYPE_OFFSET] = NODE_TYPE_LEAF
+	} else {
+		data[NODE_TYPE_OFFSET] = NODE_TYPE_INTERNAL
+	}
+	binary.LittleEndian.PutUint16(data[NUM_KEYS_OFFSET:], uint16(len(n.keys)))
+	binary.LittleEndian.PutUint32(data[NEXT_PAGE_ID_OFFSET:], n.nextPageID)
+
+	offset := NODE_HEADER_SIZE
+
+	if !n.isLeaf {
+		binary.LittleEndian.PutUint32(data[offset:], n.children[0])
+		offset += CHILD_SIZE
+	}
+
+	for i, key := range n.keys {
+		binary.LittleEndian.PutUint16(data[offset:], uint16(len(key)))
+		offset += KEY_LENGTH_SIZE
+		copy(data[offset:], key)
+		offset += uint32(len(key))
+
+		if n.isLeaf {
+			binary.LittleEndian.PutUint32(data[offset:], n.rids[i].GetPageID())
+			binary.LittleEndian.PutUint16(data[offset+4:], n.rids[i].GetSlotID())
+			offset += RID_SIZE
+		} else {
+			binary.LittleEndian.PutUint32(data[offset:], n.children[i+1])
+			offset += CHILD_SIZE
+		}
+	}
+}
+
+func decodeNode(data []byte) (*node, error) {
+	var n *node
+
+	switch data[NODE_TYPE_OFFSET] {
+	case NODE_TYPE_LEAF:
+		n = newLeafNode()
+	case NODE_TYPE_INTERNAL:
+		n = newInternalNode()
+	default:
+		return nil, ErrInvalidNodeType
+	}
+
+	numKeys := int(binary.LittleEndian.Uint16(data[NUM_KEYS_OFFSET:]))
+	n.nextPageID = binary.LittleEndian.Uint32(data[NEXT_PAGE_ID_OFFSET:])
+	n.keys = make([][]byte, 0, numKeys)
+
+	offset := NODE_HEADER_SIZE
+
+	if !n.isLeaf {
+		n.children = make([]uint32, 0, numKeys+1)
+		n.children = append(n.children, binary.LittleEndian.Uint32(data[offset:]))
+		offset += CHILD_SIZE
+	} else {
+		n.rids = make([]table_heap.RID, 0, numKeys)
+	}
+
+	for i := 0; i < numKeys; i++ {
+		keyLength := uint32(binary.LittleEndian.Uint16(data[offset:]))
+		offset += KEY_LENGTH_SIZE
+
+		key := make([]byte, keyLength)
+		copy(key, data[offset:offset+keyLength])
+		n.keys = append(n.keys, key)
+		offset += keyLength
+
+		if n.isLeaf {
+			pageID := binary.LittleEndian.Uint32(data[offset:])
+			slotID := binary.LittleEndian.Uint16(data[offset+4:])
+			n.rids = append(n.rids, *table_heap.NewRID(pageID, slotID))
+			offset += RID_SIZE
+		} else {
+			n.children = append(n.children, binary.LittleEndian.Uint32(data[offset:]))
+			offset += CHILD_SIZE
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,76 @@
+package bplus_tree_index
+
+import (
+	"bytes"
+
+	"gobase/table_heap"
+)
+
+func (it *IndexIterator) Next() ([]byte, *table_heap.RID, bool) {
+	it.tree.mu.RLock()
+	defer it.tree.mu.RUnlock()
+
+	if !it.started {
+		it.started = true
+
+		var err error
+		if it.start == nil {
+			it.pageID, it.leaf, err = it.tree.findFirstLeaf()
+		} else {
+			it.pageID, it.leaf, err = it.tree.findLeaf(it.start)
+		}
+		if err != nil {
+			it.leaf = nil
+			it.err = err
+			return nil, nil, false
+		}
+
+		if it.start != nil {
+			it.position = it.leaf.lowerBound(it.start)
+		}
+	}
+
+	for {
+		if it.leaf == nil {
+			return nil, nil, false
+		}
+
+		if it.position >= len(it.leaf.keys) {
+			if it.leaf.nextPageID == INVALID_PAGE_ID {
+				it.leaf = nil
+				return nil, nil, false
+			}
+
+			nextLeaf, err := it.tree.readNode(it.leaf.nextPageID)
+			if err != nil {
+				it.leaf = nil
+				it.err = err
+				return nil, nil, false
+			}
+
+			it.pageID = it.leaf.nextPageID
+			it.leaf = nextLeaf
+			it.position = 0
+			continue
+		}
+
+		key := it.leaf.keys[it.position]
+		if it.end != nil && bytes.Compare(key, it.end) >= 0 {
+			it.leaf = nil
+			return nil, nil, false
+		}
+
+		rid := it.leaf.rids[it.position]
+		it.position++
+
+		return key, &rid, true
+	}
+}
+
+// Err returns the error that ended the scan, if any.
+func (it *IndexIterator) Err() error {
+	it.tree.mu.RLock()
+	defer it.tree.mu.RUnlock()
+
+	return it.err
+}
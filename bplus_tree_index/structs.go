@@ -1,3 +1,99 @@
 package bplus_tree_index
 
-type InternalNode struct{}
+import (
+	"encoding/binary"
+	"sync"
+
+	"gobase/buffer_pool_manager"
+	"gobase/table_heap"
+)
+
+type BPlusTree struct {
+	mu           sync.RWMutex
+	bpm          *buffer_pool_manager.BufferPoolManager
+	headerPageID uint32
+	rootPageID   uint32
+}
+
+type node struct {
+	isLeaf     bool
+	keys       [][]byte
+	rids       []table_heap.RID
+	children   []uint32
+	nextPageID uint32
+}
+
+type IndexIterator struct {
+	tree     *BPlusTree
+	start    []byte
+	end      []byte
+	started  bool
+	pageID   uint32
+	leaf     *node
+	position int
+	err      error
+}
+
+func newLeafNode() *node {
+	return &node{
+		isLeaf:     true,
+		nextPageID: INVALID_PAGE_ID,
+	}
+}
+
+func newInternalNode() *node {
+	return &node{
+		isLeaf:     false,
+		nextPageID: INVALID_PAGE_ID,
+	}
+}
+
+func NewBPlusTree(bpm *buffer_pool_manager.BufferPoolManager) (*BPlusTree, error) {
+	headerPageID, headerFrame, err := bpm.NewPage()
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &BPlusTree{
+		bpm:          bpm,
+		headerPageID: headerPageID,
+		rootPageID:   INVALID_PAGE_ID,
+	}
+
+	rootPageID, err := tree.allocateNode(newLeafNode())
+	if err != nil {
+		bpm.UnpinPage(headerPageID, false)
+		return nil, err
+	}
+
+	headerFrame.WLatch()
+	binary.LittleEndian.PutUint32(headerFrame.Data[ROOT_PAGE_ID_OFFSET:], rootPageID)
+	err = tree.logPageImage(headerPageID, headerFrame.Data)
+	headerFrame.WUnlatch()
+	bpm.UnpinPage(headerPageID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	tree.rootPageID = rootPageID
+
+	return tree, nil
+}
+
+func OpenBPlusTree(bpm *buffer_pool_manager.BufferPoolManager, headerPageID uint32) (*BPlusTree, error) {
+	headerFrame, err := bpm.FetchPage(headerPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	headerFrame.RLatch()
+	rootPageID := binary.LittleEndian.Uint32(headerFrame.Data[ROOT_PAGE_ID_OFFSET:])
+	headerFrame.RUnlatch()
+	bpm.UnpinPage(headerPageID, false)
+
+	return &BPlusTree{
+		bpm:          bpm,
+		headerPageID: headerPageID,
+		rootPageID:   rootPageID,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package bplus_tree_index
+
+import (
+	"os"
+	"testing"
+
+	"gobase/buffer_pool_manager"
+	"gobase/disk_manager"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBPlusTree(t *testing.T, poolSize int) (*BPlusTree, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "bplus_tree_test")
+	require.NoError(t, err)
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, poolSize)
+
+	tree, err := NewBPlusTree(bpm)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return tree, cleanup
+}
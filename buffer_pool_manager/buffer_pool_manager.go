@@ -1,8 +1,12 @@
 package buffer_pool_manager
 
 func (bpm *BufferPoolManager) FetchPage(pageID uint32) (*Frame, error) {
+	bpm.mu.Lock()
+	defer bpm.mu.Unlock()
+
 	if index, exists := bpm.pageTable[pageID]; exists {
-		bpm.frames[index].PinCount++
+		bpm.pinFrame(index)
+		bpm.recordAccess(index)
 		return bpm.frames[index], nil
 	}
 
@@ -18,6 +22,7 @@ func (bpm *BufferPoolManager) FetchPage(pageID uint32) (*Frame, error) {
 
 	data, err := bpm.dm.ReadPage(pageID)
 	if err != nil {
+		bpm.releaseFrame(frameIndex)
 		return nil, err
 	}
 
@@ -25,16 +30,20 @@ func (bpm *BufferPoolManager) FetchPage(pageID uint32) (*Frame, error) {
 
 	bpm.frames[frameIndex] = newFrame
 	bpm.pageTable[pageID] = frameIndex
+	bpm.recordAccess(frameIndex)
 
 	return newFrame, nil
 }
 
 func (bpm *BufferPoolManager) UnpinPage(pageID uint32, isDirty bool) error {
+	bpm.mu.Lock()
+	defer bpm.mu.Unlock()
+
 	if index, exists := bpm.pageTable[pageID]; exists {
 		frame := bpm.frames[index]
 
 		if frame.PinCount > 0 {
-			frame.PinCount--
+			bpm.unpinFrame(index)
 		}
 		if isDirty {
 			frame.Dirty = true
@@ -47,38 +56,98 @@ func (bpm *BufferPoolManager) UnpinPage(pageID uint32, isDirty bool) error {
 }
 
 func (bpm *BufferPoolManager) FlushPage(pageID uint32) error {
-	if index, exists := bpm.pageTable[pageID]; exists {
-		err := bpm.dm.WritePage(bpm.frames[index].PageID, bpm.frames[index].Data)
-		if err != nil {
-			return err
+	bpm.mu.Lock()
+
+	index, exists := bpm.pageTable[pageID]
+	if !exists {
+		bpm.mu.Unlock()
+		return ErrPageNotFound
+	}
+
+	frame := bpm.frames[index]
+	bpm.pinFrame(index)
+	frame.Dirty = false
+
+	bpm.mu.Unlock()
+
+	return bpm.flushPinnedFrame(frame)
+}
+
+func (bpm *BufferPoolManager) FlushAllPages() error {
+	bpm.mu.Lock()
+
+	frames := make([]*Frame, 0, len(bpm.pageTable))
+	for _, index := range bpm.pageTable {
+		frame := bpm.frames[index]
+		if frame.Dirty {
+			bpm.pinFrame(index)
+			frame.Dirty = false
+			frames = append(frames, frame)
 		}
+	}
 
-		bpm.frames[index].Dirty = false
-		return nil
+	bpm.mu.Unlock()
+
+	var firstErr error
+	for _, frame := range frames {
+		err := bpm.flushPinnedFrame(frame)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
 
-	return ErrPageNotFound
+	return firstErr
 }
 
 func (bpm *BufferPoolManager) NewPage() (newPageID uint32, newFrame *Frame, err error) {
-	newPageID, err = bpm.dm.AllocatePage()
+	bpm.mu.Lock()
+	defer bpm.mu.Unlock()
+
+	frameIndex, err := bpm.findFreeFrame()
 	if err != nil {
 		return 0, nil, err
 	}
 
-	frameIndex, err := bpm.findFreeFrame()
+	err = bpm.evictFrame(frameIndex)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	err = bpm.evictFrame(frameIndex)
+	newPageID, err = bpm.dm.AllocatePage()
+	if err != nil {
+		bpm.releaseFrame(frameIndex)
+		return 0, nil, err
+	}
+
+	// A reused page carries the LSN it was freed at; keeping it stops
+	// recovery from replaying records of its previous owner.
+	data, err := bpm.dm.ReadPage(newPageID)
 	if err != nil {
+		bpm.releaseFrame(frameIndex)
 		return 0, nil, err
 	}
 
-	newFrame = NewFrame(newPageID, make([]byte, bpm.dm.PageSize))
+	newFrame = NewFrame(newPageID, data)
 	bpm.pageTable[newFrame.PageID] = frameIndex
 	bpm.frames[frameIndex] = newFrame
+	bpm.recordAccess(frameIndex)
 
 	return newPageID, newFrame, nil
 }
+
+func (bpm *BufferPoolManager) DeletePage(pageID uint32) error {
+	bpm.mu.Lock()
+	defer bpm.mu.Unlock()
+
+	if index, exists := bpm.pageTable[pageID]; exists {
+		if bpm.frames[index].PinCount > 0 {
+			return ErrPagePinned
+		}
+
+		delete(bpm.pageTable, pageID)
+		bpm.replacer.Remove(index)
+		bpm.releaseFrame(index)
+	}
+
+	return bpm.dm.DeallocatePage(pageID)
+}
@@ -1,6 +1,10 @@
 package buffer_pool_manager
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"gobase/disk_manager"
@@ -16,11 +20,12 @@ func TestFetchPage_PageAlreadyInBuffer(t *testing.T) {
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
-		Data:     make([]byte, shared.PAGE_SIZE),
+		Data:     make([]byte, shared.DEFAULT_PAGE_SIZE),
 		Dirty:    false,
 		PinCount: 0,
   	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	frame, err := bpm.FetchPage(0)
 	require.NoError(t, err)
@@ -52,11 +57,12 @@ func TestFetchPage_EvictCleanFrame(t *testing.T) {
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
-		Data:     make([]byte, shared.PAGE_SIZE),
+		Data:     make([]byte, shared.DEFAULT_PAGE_SIZE),
 		Dirty:    false,
 		PinCount: 0,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	frame, err := bpm.FetchPage(1)
 	require.NoError(t, err)
@@ -75,8 +81,8 @@ func TestFetchPage_EvictDirtyFrame(t *testing.T) {
 	_, err = bpm.dm.AllocatePage()
 	require.NoError(t, err)
 
-	dirtyData := make([]byte, shared.PAGE_SIZE)
-	copy(dirtyData, []byte("dirty data"))
+	dirtyData := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(dirtyData[shared.PAGE_HEADER_SIZE:], []byte("dirty data"))
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
@@ -85,6 +91,7 @@ func TestFetchPage_EvictDirtyFrame(t *testing.T) {
 		PinCount: 0,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	frame, err := bpm.FetchPage(1)
 	require.NoError(t, err)
@@ -93,7 +100,7 @@ func TestFetchPage_EvictDirtyFrame(t *testing.T) {
 
 	dataOnDisk, err := bpm.dm.ReadPage(0)
 	require.NoError(t, err)
-	assert.Equal(t, dirtyData, dataOnDisk)
+	assert.Equal(t, dirtyData[shared.PAGE_HEADER_SIZE:], dataOnDisk[shared.PAGE_HEADER_SIZE:])
 }
 
 func TestFetchPage_BufferPoolFull(t *testing.T) {
@@ -107,11 +114,12 @@ func TestFetchPage_BufferPoolFull(t *testing.T) {
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
-		Data:     make([]byte, shared.PAGE_SIZE),
+		Data:     make([]byte, shared.DEFAULT_PAGE_SIZE),
 		Dirty:    false,
 		PinCount: 1,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	_, err = bpm.FetchPage(1)
 	require.ErrorIs(t, err, ErrBufferPoolFull)
@@ -121,7 +129,7 @@ func TestFetchPage_PageDoesNotExist(t *testing.T) {
 	bpm, cleanup := newTestBufferPoolManager(t, 1)
 	defer cleanup()
 
-	_, err := bpm.FetchPage(0)
+	_, err := bpm.FetchPage(1)
 	require.ErrorIs(t, err, disk_manager.ErrPageDoesNotExist)
 }
 
@@ -134,11 +142,12 @@ func TestUnpinPage(t *testing.T) {
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
-		Data:     make([]byte, shared.PAGE_SIZE),
+		Data:     make([]byte, shared.DEFAULT_PAGE_SIZE),
 		Dirty:    false,
 		PinCount: 1,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	err = bpm.UnpinPage(0, false)
 	require.NoError(t, err)
@@ -154,11 +163,12 @@ func TestUnpinPage_IsDirty(t *testing.T) {
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
-		Data:     make([]byte, shared.PAGE_SIZE),
+		Data:     make([]byte, shared.DEFAULT_PAGE_SIZE),
 		Dirty:    false,
 		PinCount: 1,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	err = bpm.UnpinPage(0, true)
 	require.NoError(t, err)
@@ -181,8 +191,8 @@ func TestFlushPage(t *testing.T) {
 	_, err := bpm.dm.AllocatePage()
 	require.NoError(t, err)
 
-	data := make([]byte, shared.PAGE_SIZE)
-	copy(data, []byte("flushed data"))
+	data := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(data[shared.PAGE_HEADER_SIZE:], []byte("flushed data"))
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
@@ -191,6 +201,7 @@ func TestFlushPage(t *testing.T) {
 		PinCount: 1,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	err = bpm.FlushPage(0)
 	require.NoError(t, err)
@@ -198,7 +209,7 @@ func TestFlushPage(t *testing.T) {
 
 	dataOnDisk, err := bpm.dm.ReadPage(0)
 	require.NoError(t, err)
-	assert.Equal(t, data, dataOnDisk)
+	assert.Equal(t, data[shared.PAGE_HEADER_SIZE:], dataOnDisk[shared.PAGE_HEADER_SIZE:])
 }
 
 func TestFlushPage_CleanPage(t *testing.T) {
@@ -208,8 +219,8 @@ func TestFlushPage_CleanPage(t *testing.T) {
 	_, err := bpm.dm.AllocatePage()
 	require.NoError(t, err)
 
-	data := make([]byte, shared.PAGE_SIZE)
-	copy(data, []byte("clean data"))
+	data := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(data[shared.PAGE_HEADER_SIZE:], []byte("clean data"))
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
@@ -218,6 +229,7 @@ func TestFlushPage_CleanPage(t *testing.T) {
 		PinCount: 1,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	err = bpm.FlushPage(0)
 	require.NoError(t, err)
@@ -225,7 +237,7 @@ func TestFlushPage_CleanPage(t *testing.T) {
 
 	dataOnDisk, err := bpm.dm.ReadPage(0)
 	require.NoError(t, err)
-	assert.Equal(t, data, dataOnDisk)
+	assert.Equal(t, data[shared.PAGE_HEADER_SIZE:], dataOnDisk[shared.PAGE_HEADER_SIZE:])
 }
 
 func TestFlushPage_PageNotFound(t *testing.T) {
@@ -242,7 +254,7 @@ func TestNewPage(t *testing.T) {
 
 	newPageID, newFrame, err := bpm.NewPage()
 	require.NoError(t, err)
-	assert.Equal(t, uint32(0), newPageID)
+	assert.Equal(t, uint32(1), newPageID)
 	assert.Equal(t, newPageID, newFrame.PageID)
 	assert.Equal(t, 1, newFrame.PinCount)
 	assert.Equal(t, 0, bpm.pageTable[newPageID])
@@ -255,11 +267,12 @@ func TestNewPage_BufferPoolFull(t *testing.T) {
 
 	bpm.frames[0] = &Frame{
 		PageID:   0,
-		Data:     make([]byte, shared.PAGE_SIZE),
+		Data:     make([]byte, shared.DEFAULT_PAGE_SIZE),
 		Dirty:    false,
 		PinCount: 1,
 	}
 	bpm.pageTable[0] = 0
+	registerTestFrame(bpm, 0)
 
 	_, _, err := bpm.NewPage()
 	require.ErrorIs(t, err, ErrBufferPoolFull)
@@ -272,24 +285,281 @@ func TestNewPage_EvictDirtyFrame(t *testing.T) {
 	_, err := bpm.dm.AllocatePage()
 	require.NoError(t, err)
 
-	dirtyData := make([]byte, shared.PAGE_SIZE)
-	copy(dirtyData, []byte("dirty data"))
+	dirtyData := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(dirtyData[shared.PAGE_HEADER_SIZE:], []byte("dirty data"))
 
 	bpm.frames[0] = &Frame{
-		PageID:   0,
+		PageID:   1,
 		Data:     dirtyData,
 		Dirty:    true,
 		PinCount: 0,
 	}
-	bpm.pageTable[0] = 0
+	bpm.pageTable[1] = 0
+	registerTestFrame(bpm, 0)
 
 	newPageID, newFrame, err := bpm.NewPage()
 	require.NoError(t, err)
-	assert.Equal(t, uint32(1), newPageID)
+	assert.Equal(t, uint32(2), newPageID)
 	assert.Equal(t, newPageID, newFrame.PageID)
-	assert.NotContains(t, bpm.pageTable, uint32(0))
+	assert.NotContains(t, bpm.pageTable, uint32(1))
 
-	dataOnDisk, err := bpm.dm.ReadPage(0)
+	dataOnDisk, err := bpm.dm.ReadPage(1)
+	require.NoError(t, err)
+	assert.Equal(t, dirtyData[shared.PAGE_HEADER_SIZE:], dataOnDisk[shared.PAGE_HEADER_SIZE:])
+}
+
+func TestNewPage_BufferPoolFullDoesNotAllocate(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 1)
+	defer cleanup()
+
+	_, _, err := bpm.NewPage()
 	require.NoError(t, err)
-	assert.Equal(t, dirtyData, dataOnDisk)
+
+	_, _, err = bpm.NewPage()
+	require.ErrorIs(t, err, ErrBufferPoolFull)
+	assert.Equal(t, uint32(2), bpm.dm.NumPages)
+}
+
+func TestDeletePage(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 2)
+	defer cleanup()
+
+	pageID, frame, err := bpm.NewPage()
+	require.NoError(t, err)
+	copy(frame.Data[shared.PAGE_HEADER_SIZE:], "stale data")
+	require.NoError(t, bpm.UnpinPage(pageID, true))
+
+	require.NoError(t, bpm.DeletePage(pageID))
+	assert.NotContains(t, bpm.pageTable, pageID)
+	assert.Equal(t, pageID, bpm.dm.GetFreeListHead())
+
+	newPageID, newFrame, err := bpm.NewPage()
+	require.NoError(t, err)
+	assert.Equal(t, pageID, newPageID)
+	assert.Equal(t, make([]byte, shared.DEFAULT_PAGE_SIZE)[shared.PAGE_HEADER_SIZE:], newFrame.Data[shared.PAGE_HEADER_SIZE:])
+}
+
+func TestDeletePage_Pinned(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 1)
+	defer cleanup()
+
+	pageID, _, err := bpm.NewPage()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, bpm.DeletePage(pageID), ErrPagePinned)
+	assert.Contains(t, bpm.pageTable, pageID)
+	assert.Equal(t, disk_manager.INVALID_PAGE_ID, bpm.dm.GetFreeListHead())
+}
+
+func TestConcurrentNewPageAndFetchPage(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 16)
+	defer cleanup()
+
+	const workers = 8
+	const pagesPerWorker = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			pageIDs := make([]uint32, 0, pagesPerWorker)
+			for i := 0; i < pagesPerWorker; i++ {
+				pageID, frame, err := bpm.NewPage()
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				frame.WLatch()
+				binary.LittleEndian.PutUint32(frame.Data[shared.PAGE_HEADER_SIZE:], pageID)
+				frame.WUnlatch()
+				bpm.UnpinPage(pageID, true)
+
+				pageIDs = append(pageIDs, pageID)
+			}
+
+			for _, pageID := range pageIDs {
+				frame, err := bpm.FetchPage(pageID)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				frame.RLatch()
+				stored := binary.LittleEndian.Uint32(frame.Data[shared.PAGE_HEADER_SIZE:])
+				frame.RUnlatch()
+				bpm.UnpinPage(pageID, false)
+
+				if stored != pageID {
+					errs <- fmt.Errorf("page %d contains data of page %d", pageID, stored)
+					return
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestConcurrentWritesToSamePage(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 4)
+	defer cleanup()
+
+	pageID, _, err := bpm.NewPage()
+	require.NoError(t, err)
+	require.NoError(t, bpm.UnpinPage(pageID, true))
+
+	const workers = 8
+	const incrementsPerWorker = 200
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < incrementsPerWorker; i++ {
+				frame, err := bpm.FetchPage(pageID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				frame.WLatch()
+				counter := binary.LittleEndian.Uint32(frame.Data[shared.PAGE_HEADER_SIZE:])
+				binary.LittleEndian.PutUint32(frame.Data[shared.PAGE_HEADER_SIZE:], counter+1)
+				frame.WUnlatch()
+				bpm.UnpinPage(pageID, true)
+
+				if i%50 == 0 {
+					bpm.FlushPage(pageID)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.NoError(t, bpm.FlushAllPages())
+
+	data, err := bpm.dm.ReadPage(pageID)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(workers*incrementsPerWorker), binary.LittleEndian.Uint32(data[shared.PAGE_HEADER_SIZE:]))
+}
+
+func TestConcurrentFetchWithEviction(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 4)
+	defer cleanup()
+
+	const numPages = 32
+	for i := 0; i < numPages; i++ {
+		pageID, frame, err := bpm.NewPage()
+		require.NoError(t, err)
+		binary.LittleEndian.PutUint32(frame.Data[shared.PAGE_HEADER_SIZE:], pageID)
+		require.NoError(t, bpm.UnpinPage(pageID, true))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < 200; i++ {
+				pageID := uint32((w*7+i)%numPages) + 1
+
+				frame, err := bpm.FetchPage(pageID)
+				if errors.Is(err, ErrBufferPoolFull) {
+					continue
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				frame.RLatch()
+				stored := binary.LittleEndian.Uint32(frame.Data[shared.PAGE_HEADER_SIZE:])
+				frame.RUnlatch()
+				bpm.UnpinPage(pageID, false)
+
+				if stored != pageID {
+					t.Errorf("page %d contains data of page %d", pageID, stored)
+					return
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+}
+
+func TestFetchPage_SequentialScanKeepsHotPage(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t)
+	defer cleanup()
+
+	for i := 0; i < 10; i++ {
+		_, err := dm.AllocatePage()
+		require.NoError(t, err)
+	}
+
+	bpm := NewBufferPoolManager(dm, 3, WithLRUKReplacer(2))
+
+	for i := 0; i < 2; i++ {
+		_, err := bpm.FetchPage(0)
+		require.NoError(t, err)
+		require.NoError(t, bpm.UnpinPage(0, false))
+	}
+
+	for pageID := uint32(1); pageID < 10; pageID++ {
+		_, err := bpm.FetchPage(pageID)
+		require.NoError(t, err)
+		require.NoError(t, bpm.UnpinPage(pageID, false))
+	}
+
+	assert.Contains(t, bpm.pageTable, uint32(0))
+}
+
+func TestFetchPage_ClockReplacer(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t)
+	defer cleanup()
+
+	for i := 0; i < 4; i++ {
+		_, err := dm.AllocatePage()
+		require.NoError(t, err)
+	}
+
+	bpm := NewBufferPoolManager(dm, 2, WithClockReplacer())
+	assert.IsType(t, &ClockReplacer{}, bpm.replacer)
+
+	for pageID := uint32(0); pageID < 4; pageID++ {
+		_, err := bpm.FetchPage(pageID)
+		require.NoError(t, err)
+		require.NoError(t, bpm.UnpinPage(pageID, false))
+	}
+
+	assert.Len(t, bpm.pageTable, 2)
+	assert.Contains(t, bpm.pageTable, uint32(3))
+}
+
+func TestFetchPage_PageCorrupted(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 1)
+	defer cleanup()
+
+	pageID, err := bpm.dm.AllocatePage()
+	require.NoError(t, err)
+
+	_, err = bpm.dm.File.WriteAt([]byte("bit rot"), int64(pageID)*int64(shared.DEFAULT_PAGE_SIZE)+int64(shared.PAGE_HEADER_SIZE))
+	require.NoError(t, err)
+
+	_, err = bpm.FetchPage(pageID)
+	require.ErrorIs(t, err, disk_manager.ErrPageCorrupted)
+	assert.NotContains(t, bpm.pageTable, pageID)
 }
@@ -0,0 +1,78 @@
+package buffer_pool_manager
+
+type clockEntry struct {
+	tracked    bool
+	evictable  bool
+	referenced bool
+}
+
+type ClockReplacer struct {
+	entries []clockEntry
+	hand    int
+	size    int
+}
+
+func NewClockReplacer(poolSize int) *ClockReplacer {
+	return &ClockReplacer{
+		entries: make([]clockEntry, poolSize),
+	}
+}
+
+func (r *ClockReplacer) RecordAccess(frameIndex int) {
+	entry := &r.entries[frameIndex]
+	entry.tracked = true
+	entry.referenced = true
+}
+
+func (r *ClockReplacer) SetEvictable(frameIndex int, evictable bool) {
+	entry := &r.entries[frameIndex]
+	if !entry.tracked || entry.evictable == evictable {
+		return
+	}
+
+	entry.evictable = evictable
+	if evictable {
+		r.size++
+	} else {
+		r.size--
+	}
+}
+
+func (r *ClockReplacer) Evict() (int, bool) {
+	if r.size == 0 {
+		return 0, false
+	}
+
+	for {
+		frameIndex := r.hand
+		entry := &r.entries[frameIndex]
+		r.hand = (r.hand + 1) % len(r.entries)
+
+		if !entry.tracked || !entry.evictable {
+			continue
+		}
+
+		if entry.referenced {
+			entry.referenced = false
+			continue
+		}
+
+		*entry = clockEntry{}
+		r.size--
+
+		return frameIndex, true
+	}
+}
+
+func (r *ClockReplacer) Remove(frameIndex int) {
+	entry := &r.entries[frameIndex]
+	if entry.evictable {
+		r.size--
+	}
+
+	*entry = clockEntry{}
+}
+
+func (r *ClockReplacer) Size() int {
+	return r.size
+}
@@ -0,0 +1,48 @@
+package buffer_pool_manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockReplacer_GivesSecondChance(t *testing.T) {
+	r := NewClockReplacer(3)
+
+	for i := 0; i < 3; i++ {
+		r.RecordAccess(i)
+		r.SetEvictable(i, true)
+	}
+
+	frameIndex, ok := r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 0, frameIndex)
+
+	r.RecordAccess(1)
+
+	frameIndex, ok = r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 2, frameIndex)
+
+	assert.Equal(t, 1, r.Size())
+}
+
+func TestClockReplacer_SkipsPinnedFrames(t *testing.T) {
+	r := NewClockReplacer(2)
+
+	r.RecordAccess(0)
+	r.RecordAccess(1)
+	r.SetEvictable(1, true)
+
+	frameIndex, ok := r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 1, frameIndex)
+
+	_, ok = r.Evict()
+	assert.False(t, ok)
+
+	r.SetEvictable(0, true)
+	r.Remove(0)
+	assert.Equal(t, 0, r.Size())
+}
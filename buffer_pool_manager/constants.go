@@ -0,0 +1,5 @@
+package buffer_pool_manager
+
+const (
+	DEFAULT_LRU_K = 2
+)
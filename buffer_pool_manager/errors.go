@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrBufferPoolFull    = errors.New("buffer pool is full")
 	ErrPageNotFound = errors.New("page not found")
+	ErrPagePinned   = errors.New("page is pinned")
 )
\ No newline at end of file
@@ -1,21 +1,61 @@
 package buffer_pool_manager
 
+import (
+	"gobase/disk_manager"
+	"gobase/log_manager"
+	"gobase/shared"
+)
+
+func (bpm *BufferPoolManager) GetLogManager() *log_manager.LogManager {
+	return bpm.dm.Log
+}
+
+func (bpm *BufferPoolManager) GetDiskManager() *disk_manager.DiskManager {
+	return bpm.dm
+}
+
+func (bpm *BufferPoolManager) GetPageSize() uint32 {
+	return bpm.dm.PageSize
+}
+
 func (bpm *BufferPoolManager) findFreeFrame() (int, error) {
-	for i, f := range bpm.frames {
-		if f == nil {
-			return i, nil
-		}
+	if len(bpm.freeFrames) > 0 {
+		frameIndex := bpm.freeFrames[len(bpm.freeFrames)-1]
+		bpm.freeFrames = bpm.freeFrames[:len(bpm.freeFrames)-1]
+		return frameIndex, nil
 	}
 
-	for i, f := range bpm.frames {
-		if f.PinCount == 0 {
-			return i, nil
-		}
+	if frameIndex, ok := bpm.replacer.Evict(); ok {
+		return frameIndex, nil
 	}
 
 	return 0, ErrBufferPoolFull
 }
 
+// releaseFrame hands back a frame that holds no page.
+func (bpm *BufferPoolManager) releaseFrame(frameIndex int) {
+	bpm.frames[frameIndex] = nil
+	bpm.freeFrames = append(bpm.freeFrames, frameIndex)
+}
+
+func (bpm *BufferPoolManager) recordAccess(frameIndex int) {
+	bpm.replacer.RecordAccess(frameIndex)
+	bpm.replacer.SetEvictable(frameIndex, false)
+}
+
+func (bpm *BufferPoolManager) pinFrame(frameIndex int) {
+	bpm.frames[frameIndex].PinCount++
+	bpm.replacer.SetEvictable(frameIndex, false)
+}
+
+func (bpm *BufferPoolManager) unpinFrame(frameIndex int) {
+	frame := bpm.frames[frameIndex]
+	frame.PinCount--
+	if frame.PinCount == 0 {
+		bpm.replacer.SetEvictable(frameIndex, true)
+	}
+}
+
 func (bpm *BufferPoolManager) evictFrame(frameIndex int) error {
 	oldFrame := bpm.frames[frameIndex]
 	if oldFrame == nil {
@@ -23,12 +63,56 @@ func (bpm *BufferPoolManager) evictFrame(frameIndex int) error {
 	}
 
 	if oldFrame.Dirty {
-		err := bpm.dm.WritePage(oldFrame.PageID, oldFrame.Data)
+		err := bpm.writeFrame(oldFrame)
 		if err != nil {
 			return err
 		}
 	}
 
 	delete(bpm.pageTable, oldFrame.PageID)
+	bpm.frames[frameIndex] = nil
+	bpm.replacer.Remove(frameIndex)
 	return nil
 }
+
+func (bpm *BufferPoolManager) writeFrame(frame *Frame) error {
+	if bpm.dm.Log != nil {
+		err := bpm.dm.Log.Flush(shared.GetPageLSN(frame.Data))
+		if err != nil {
+			return err
+		}
+	}
+
+	return bpm.dm.WritePage(frame.PageID, frame.Data)
+}
+
+func (bpm *BufferPoolManager) flushPinnedFrame(frame *Frame) error {
+	frame.RLatch()
+	err := bpm.writeFrame(frame)
+	frame.RUnlatch()
+
+	bpm.mu.Lock()
+	bpm.unpinFrame(bpm.pageTable[frame.PageID])
+	if err != nil {
+		frame.Dirty = true
+	}
+	bpm.mu.Unlock()
+
+	return err
+}
+
+func (f *Frame) RLatch() {
+	f.latch.RLock()
+}
+
+func (f *Frame) RUnlatch() {
+	f.latch.RUnlock()
+}
+
+func (f *Frame) WLatch() {
+	f.latch.Lock()
+}
+
+func (f *Frame) WUnlatch() {
+	f.latch.Unlock()
+}
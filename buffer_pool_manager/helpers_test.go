@@ -3,6 +3,7 @@ package buffer_pool_manager
 import (
 	"testing"
 
+	"gobase/disk_manager"
 	"gobase/shared"
 
 	"github.com/stretchr/testify/assert"
@@ -22,8 +23,10 @@ func TestFindFreeFrame_PartiallyFilled(t *testing.T) {
 	bpm, cleanup := newTestBufferPoolManager(t, 3)
 	defer cleanup()
 
-	bpm.frames[0] = NewFrame(0, make([]byte, shared.PAGE_SIZE))
-	bpm.frames[1] = NewFrame(1, make([]byte, shared.PAGE_SIZE))
+	bpm.frames[0] = NewFrame(0, make([]byte, shared.DEFAULT_PAGE_SIZE))
+	bpm.frames[1] = NewFrame(1, make([]byte, shared.DEFAULT_PAGE_SIZE))
+	claimTestFrame(bpm, 0)
+	claimTestFrame(bpm, 1)
 
 	index, err := bpm.findFreeFrame()
 	require.NoError(t, err)
@@ -35,11 +38,14 @@ func TestFindFreeFrame_FullPoolWithUnpinnedFrame(t *testing.T) {
 	defer cleanup()
 
 	for i := 0; i < 3; i++ {
-		bpm.frames[i] = NewFrame(uint32(i), make([]byte, shared.PAGE_SIZE))
+		bpm.frames[i] = NewFrame(uint32(i), make([]byte, shared.DEFAULT_PAGE_SIZE))
 		bpm.frames[i].PinCount = 1
 	}
 
 	bpm.frames[1].PinCount = 0
+	for i := 0; i < 3; i++ {
+		registerTestFrame(bpm, i)
+	}
 
 	index, err := bpm.findFreeFrame()
 	require.NoError(t, err)
@@ -51,14 +57,43 @@ func TestFindFreeFrame_FullPoolAllPinned(t *testing.T) {
 	defer cleanup()
 
 	for i := 0; i < 3; i++ {
-		bpm.frames[i] = NewFrame(uint32(i), make([]byte, shared.PAGE_SIZE))
+		bpm.frames[i] = NewFrame(uint32(i), make([]byte, shared.DEFAULT_PAGE_SIZE))
 		bpm.frames[i].PinCount = 1
+		claimTestFrame(bpm, i)
 	}
 
 	_, err := bpm.findFreeFrame()
 	require.ErrorIs(t, err, ErrBufferPoolFull)
 }
 
+func TestFindFreeFrame_ReusesDeletedPageFrame(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 2)
+	defer cleanup()
+
+	first, _, err := bpm.NewPage()
+	require.NoError(t, err)
+	_, _, err = bpm.NewPage()
+	require.NoError(t, err)
+	assert.Empty(t, bpm.freeFrames)
+
+	require.NoError(t, bpm.UnpinPage(first, false))
+	require.NoError(t, bpm.DeletePage(first))
+	assert.Equal(t, []int{0}, bpm.freeFrames)
+
+	index, err := bpm.findFreeFrame()
+	require.NoError(t, err)
+	assert.Equal(t, 0, index)
+}
+
+func TestFindFreeFrame_KeepsFrameOnFailedFetch(t *testing.T) {
+	bpm, cleanup := newTestBufferPoolManager(t, 1)
+	defer cleanup()
+
+	_, err := bpm.FetchPage(1000)
+	require.ErrorIs(t, err, disk_manager.ErrPageDoesNotExist)
+	assert.Equal(t, []int{0}, bpm.freeFrames)
+}
+
 func TestEvictFrame_NilFrame(t *testing.T) {
 	bpm, cleanup := newTestBufferPoolManager(t, 3)
 	defer cleanup()
@@ -72,7 +107,7 @@ func TestEvictFrame_CleanFrame(t *testing.T) {
 	defer cleanup()
 
 	pageID := uint32(0)
-	bpm.frames[0] = NewFrame(pageID, make([]byte, shared.PAGE_SIZE))
+	bpm.frames[0] = NewFrame(pageID, make([]byte, shared.DEFAULT_PAGE_SIZE))
 	bpm.frames[0].Dirty = false
 	bpm.pageTable[pageID] = 0
 
@@ -91,8 +126,8 @@ func TestEvictFrame_DirtyFrame(t *testing.T) {
 	bpm := NewBufferPoolManager(dm, 3)
 
 	pageID := uint32(0)
-	data := make([]byte, shared.PAGE_SIZE)
-	copy(data, []byte("dirty data"))
+	data := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(data[shared.PAGE_HEADER_SIZE:], []byte("dirty data"))
 
 	bpm.frames[0] = NewFrame(pageID, data)
 	bpm.frames[0].Dirty = true
@@ -104,5 +139,5 @@ func TestEvictFrame_DirtyFrame(t *testing.T) {
 
 	readData, err := dm.ReadPage(pageID)
 	require.NoError(t, err)
-	assert.Equal(t, data, readData)
+	assert.Equal(t, data[shared.PAGE_HEADER_SIZE:], readData[shared.PAGE_HEADER_SIZE:])
 }
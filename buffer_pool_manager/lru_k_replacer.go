@@ -0,0 +1,172 @@
+package buffer_pool_manager
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+type lruKNode struct {
+	frameIndex int
+	history    []uint64
+	evictable  bool
+	element    *list.Element
+	heapIndex  int
+}
+
+type lruKHeap []*lruKNode
+
+type LRUKReplacer struct {
+	k           int
+	currentTime uint64
+	nodes       map[int]*lruKNode
+	historyList *list.List
+	cacheHeap   lruKHeap
+}
+
+func NewLRUKReplacer(k int) *LRUKReplacer {
+	if k < 1 {
+		k = 1
+	}
+
+	return &LRUKReplacer{
+		k:           k,
+		nodes:       make(map[int]*lruKNode),
+		historyList: list.New(),
+	}
+}
+
+func (r *LRUKReplacer) RecordAccess(frameIndex int) {
+	r.currentTime++
+
+	node, exists := r.nodes[frameIndex]
+	if !exists {
+		node = &lruKNode{frameIndex: frameIndex, heapIndex: -1}
+		r.nodes[frameIndex] = node
+	}
+
+	wasCached := len(node.history) >= r.k
+
+	node.history = append(node.history, r.currentTime)
+	if len(node.history) > r.k {
+		node.history = node.history[1:]
+	}
+
+	if !node.evictable {
+		return
+	}
+
+	if wasCached {
+		heap.Fix(&r.cacheHeap, node.heapIndex)
+		return
+	}
+
+	if len(node.history) >= r.k {
+		r.historyList.Remove(node.element)
+		node.element = nil
+		heap.Push(&r.cacheHeap, node)
+	}
+}
+
+func (r *LRUKReplacer) SetEvictable(frameIndex int, evictable bool) {
+	node, exists := r.nodes[frameIndex]
+	if !exists || node.evictable == evictable {
+		return
+	}
+
+	node.evictable = evictable
+
+	if evictable {
+		r.track(node)
+	} else {
+		r.untrack(node)
+	}
+}
+
+func (r *LRUKReplacer) Evict() (int, bool) {
+	var victim *lruKNode
+
+	if front := r.historyList.Front(); front != nil {
+		victim = front.Value.(*lruKNode)
+	} else if len(r.cacheHeap) > 0 {
+		victim = r.cacheHeap[0]
+	} else {
+		return 0, false
+	}
+
+	r.untrack(victim)
+	delete(r.nodes, victim.frameIndex)
+
+	return victim.frameIndex, true
+}
+
+func (r *LRUKReplacer) Remove(frameIndex int) {
+	node, exists := r.nodes[frameIndex]
+	if !exists {
+		return
+	}
+
+	if node.evictable {
+		r.untrack(node)
+	}
+
+	delete(r.nodes, frameIndex)
+}
+
+func (r *LRUKReplacer) Size() int {
+	return r.historyList.Len() + len(r.cacheHeap)
+}
+
+func (r *LRUKReplacer) track(node *lruKNode) {
+	if len(node.history) >= r.k {
+		heap.Push(&r.cacheHeap, node)
+		return
+	}
+
+	for e := r.historyList.Back(); e != nil; e = e.Prev() {
+		if e.Value.(*lruKNode).history[0] < node.history[0] {
+			node.element = r.historyList.InsertAfter(node, e)
+			return
+		}
+	}
+
+	node.element = r.historyList.PushFront(node)
+}
+
+func (r *LRUKReplacer) untrack(node *lruKNode) {
+	if node.element != nil {
+		r.historyList.Remove(node.element)
+		node.element = nil
+	}
+
+	if node.heapIndex >= 0 {
+		heap.Remove(&r.cacheHeap, node.heapIndex)
+	}
+}
+
+func (h lruKHeap) Len() int {
+	return len(h)
+}
+
+func (h lruKHeap) Less(i, j int) bool {
+	return h[i].history[0] < h[j].history[0]
+}
+
+func (h lruKHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *lruKHeap) Push(x any) {
+	node := x.(*lruKNode)
+	node.heapIndex = len(*h)
+	*h = append(*h, node)
+}
+
+func (h *lruKHeap) Pop() any {
+	old := *h
+	node := old[len(old)-1]
+	node.heapIndex = -1
+	*h = old[:len(old)-1]
+	return node
+}
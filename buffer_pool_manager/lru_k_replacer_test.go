@@ -0,0 +1,74 @@
+package buffer_pool_manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUKReplacer_EvictsInfiniteDistanceFirst(t *testing.T) {
+	r := NewLRUKReplacer(2)
+
+	r.RecordAccess(0)
+	r.RecordAccess(0)
+	r.RecordAccess(1)
+	r.RecordAccess(2)
+	r.RecordAccess(2)
+
+	for i := 0; i < 3; i++ {
+		r.SetEvictable(i, true)
+	}
+	assert.Equal(t, 3, r.Size())
+
+	frameIndex, ok := r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 1, frameIndex)
+
+	frameIndex, ok = r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 0, frameIndex)
+
+	frameIndex, ok = r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 2, frameIndex)
+
+	_, ok = r.Evict()
+	assert.False(t, ok)
+}
+
+func TestLRUKReplacer_UsesKthMostRecentAccess(t *testing.T) {
+	r := NewLRUKReplacer(2)
+
+	r.RecordAccess(0)
+	r.RecordAccess(1)
+	r.RecordAccess(1)
+	r.RecordAccess(0)
+	r.SetEvictable(0, true)
+	r.SetEvictable(1, true)
+
+	r.RecordAccess(1)
+
+	frameIndex, ok := r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 0, frameIndex)
+}
+
+func TestLRUKReplacer_SkipsPinnedFrames(t *testing.T) {
+	r := NewLRUKReplacer(2)
+
+	r.RecordAccess(0)
+	r.RecordAccess(1)
+	r.SetEvictable(1, true)
+
+	frameIndex, ok := r.Evict()
+	require.True(t, ok)
+	assert.Equal(t, 1, frameIndex)
+
+	_, ok = r.Evict()
+	assert.False(t, ok)
+
+	r.SetEvictable(0, true)
+	r.Remove(0)
+	assert.Equal(t, 0, r.Size())
+}
@@ -0,0 +1,9 @@
+package buffer_pool_manager
+
+type Replacer interface {
+	RecordAccess(frameIndex int)
+	SetEvictable(frameIndex int, evictable bool)
+	Evict() (int, bool)
+	Remove(frameIndex int)
+	Size() int
+}
@@ -1,21 +1,31 @@
 package buffer_pool_manager
 
-import "gobase/disk_manager"
+import (
+	"sync"
+
+	"gobase/disk_manager"
+)
 
 type Frame struct {
 	PageID   uint32
 	Data     []byte
 	Dirty    bool
 	PinCount int
+	latch    sync.RWMutex
 }
 
 type BufferPoolManager struct {
+	mu         sync.Mutex
 	frames     []*Frame
-	pageTable map[uint32]int
+	freeFrames []int
+	pageTable  map[uint32]int
 	dm         *disk_manager.DiskManager
 	poolSize   int
+	replacer   Replacer
 }
 
+type Option func(*BufferPoolManager)
+
 func NewFrame(pageID uint32, data []byte) *Frame {
 	return &Frame{
 		PageID:   pageID,
@@ -25,14 +35,49 @@ func NewFrame(pageID uint32, data []byte) *Frame {
 	}
 }
 
-func NewBufferPoolManager(dm *disk_manager.DiskManager, poolSize int) *BufferPoolManager {
+func NewBufferPoolManager(dm *disk_manager.DiskManager, poolSize int, options ...Option) *BufferPoolManager {
 	frames := make([]*Frame, poolSize)
 	pageTable := make(map[uint32]int)
 
-	return &BufferPoolManager{
+	// Free frames are taken from the end, so the pool fills from frame 0.
+	freeFrames := make([]int, poolSize)
+	for i := range freeFrames {
+		freeFrames[i] = poolSize - 1 - i
+	}
+
+	bpm := &BufferPoolManager{
 		frames:     frames,
-		pageTable: pageTable,
+		freeFrames: freeFrames,
+		pageTable:  pageTable,
 		dm:         dm,
 		poolSize:   poolSize,
 	}
+
+	for _, option := range options {
+		option(bpm)
+	}
+
+	if bpm.replacer == nil {
+		bpm.replacer = NewLRUKReplacer(DEFAULT_LRU_K)
+	}
+
+	return bpm
+}
+
+func WithReplacer(replacer Replacer) Option {
+	return func(bpm *BufferPoolManager) {
+		bpm.replacer = replacer
+	}
+}
+
+func WithLRUKReplacer(k int) Option {
+	return func(bpm *BufferPoolManager) {
+		bpm.replacer = NewLRUKReplacer(k)
+	}
+}
+
+func WithClockReplacer() Option {
+	return func(bpm *BufferPoolManager) {
+		bpm.replacer = NewClockReplacer(bpm.poolSize)
+	}
 }
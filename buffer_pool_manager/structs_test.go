@@ -28,4 +28,5 @@ func TestNewBufferPoolManager(t *testing.T) {
 	assert.Equal(t, poolSize, len(bpm.frames))
 	assert.Empty(t, bpm.pageTable)
 	assert.Equal(t, dm, bpm.dm)
+	assert.IsType(t, &LRUKReplacer{}, bpm.replacer)
 }
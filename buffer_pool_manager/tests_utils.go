@@ -21,6 +21,7 @@ func newTestDiskManager(t *testing.T) (*disk_manager.DiskManager, func()) {
 	cleanup := func() {
 		dm.Close()
 		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
 	}
 
 	return dm, cleanup
@@ -34,3 +35,19 @@ func newTestBufferPoolManager(t *testing.T, poolSize int) (*BufferPoolManager, f
 
 	return bpm, cleanup
 }
+
+// claimTestFrame takes a frame the test filled by hand off the free list.
+func claimTestFrame(bpm *BufferPoolManager, frameIndex int) {
+	for i, free := range bpm.freeFrames {
+		if free == frameIndex {
+			bpm.freeFrames = append(bpm.freeFrames[:i], bpm.freeFrames[i+1:]...)
+			return
+		}
+	}
+}
+
+func registerTestFrame(bpm *BufferPoolManager, frameIndex int) {
+	claimTestFrame(bpm, frameIndex)
+	bpm.replacer.RecordAccess(frameIndex)
+	bpm.replacer.SetEvictable(frameIndex, bpm.frames[frameIndex].PinCount == 0)
+}
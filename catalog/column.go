@@ -1,7 +1,5 @@
 package catalog
 
-import "errors"
-
 func (s *Schema) GetColumnIndex(name string) (int, error) {
 	for i, col := range s.Columns {
 		if col.Name == name {
@@ -9,5 +7,78 @@ func (s *Schema) GetColumnIndex(name string) (int, error) {
 		}
 	}
 
-	return -1, errors.New("column not found")
+	return -1, ErrColumnNotFound
+}
+
+func (s *Schema) GetColumnIndexes(names []string) ([]int, error) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		index, err := s.GetColumnIndex(name)
+		if err != nil {
+			return nil, err
+		}
+		indexes[i] = index
+	}
+
+	return indexes, nil
+}
+
+// ResolveColumn finds the column named by a reference that may carry a table
+// qualifier. Without one, the name must be unique across every table.
+func (s *Schema) ResolveColumn(table string, name string) (int, error) {
+	index := -1
+	tableFound := table == ""
+
+	for i, col := range s.Columns {
+		if table != "" && col.Table != table {
+			continue
+		}
+		tableFound = true
+
+		if col.Name != name {
+			continue
+		}
+		if index != -1 {
+			return -1, ErrAmbiguousColumn
+		}
+		index = i
+	}
+
+	if index == -1 && !tableFound {
+		return -1, ErrUnknownTable
+	}
+	if index == -1 {
+		return -1, ErrColumnNotFound
+	}
+
+	return index, nil
+}
+
+func (s *Schema) GetPrimaryKey() []string {
+	columns := []string{}
+
+	for _, col := range s.Columns {
+		if col.PrimaryKey {
+			columns = append(columns, col.Name)
+		}
+	}
+
+	return columns
+}
+
+func (s *Schema) GetUniqueColumns() []string {
+	columns := []string{}
+
+	for _, col := range s.Columns {
+		if col.Unique && !col.PrimaryKey {
+			columns = append(columns, col.Name)
+		}
+	}
+
+	return columns
+}
+
+// Primary key columns never accept NULL, whatever their Nullable flag says.
+func (c *Column) AcceptsNull() bool {
+	return c.Nullable && !c.PrimaryKey
 }
@@ -0,0 +1,8 @@
+package catalog
+
+const (
+	MAX_DECIMAL_PRECISION uint8 = 18
+	MAX_UINT64_POW10      uint8 = 19
+
+	SECONDS_PER_DAY = 24 * 60 * 60
+)
@@ -2,23 +2,30 @@ package catalog
 
 import (
 	"encoding/binary"
+	"math"
+	"time"
 
 	"gobase/shared"
 )
 
 func DecodeTuple(schema *Schema, tuple shared.Tuple) []any {
 	values := make([]any, len(schema.Columns))
-	offset := 0
+	nullBitmap := tuple[:nullBitmapSize(len(schema.Columns))]
+	offset := len(nullBitmap)
 
 	for i, col := range schema.Columns {
+		if nullBitmap[i/8]&(1<<(i%8)) != 0 {
+			continue
+		}
+
 		switch col.Type {
 		case TypeInt:
 			val := binary.LittleEndian.Uint32(tuple[offset:])
-			values[i] = int(val)
+			values[i] = int(int32(val))
 			offset += 4
 		case TypeSmallInt:
 			val := binary.LittleEndian.Uint16(tuple[offset:])
-			values[i] = int(val)
+			values[i] = int(int16(val))
 			offset += 2
 		case TypeBoolean:
 			values[i] = tuple[offset] != 0
@@ -28,6 +35,38 @@ func DecodeTuple(schema *Schema, tuple shared.Tuple) []any {
 			offset += 2
 			values[i] = string(tuple[offset : offset+int(length)])
 			offset += int(length)
+		case TypeBigInt:
+			val := binary.LittleEndian.Uint64(tuple[offset:])
+			values[i] = int64(val)
+			offset += 8
+		case TypeDouble:
+			val := binary.LittleEndian.Uint64(tuple[offset:])
+			values[i] = math.Float64frombits(val)
+			offset += 8
+		case TypeDecimal:
+			val := binary.LittleEndian.Uint64(tuple[offset:])
+			values[i] = NewDecimal(int64(val), col.Scale)
+			offset += 8
+		case TypeTimestamp:
+			val := binary.LittleEndian.Uint64(tuple[offset:])
+			values[i] = time.UnixMicro(int64(val)).UTC()
+			offset += 8
+		case TypeDate:
+			val := binary.LittleEndian.Uint32(tuple[offset:])
+			values[i] = time.Unix(int64(int32(val))*SECONDS_PER_DAY, 0).UTC()
+			offset += 4
+		case TypeUUID:
+			var val UUID
+			copy(val[:], tuple[offset:])
+			values[i] = val
+			offset += len(val)
+		case TypeBlob:
+			length := binary.LittleEndian.Uint16(tuple[offset:])
+			offset += 2
+			val := make([]byte, length)
+			copy(val, tuple[offset:])
+			values[i] = val
+			offset += int(length)
 		}
 	}
 
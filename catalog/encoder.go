@@ -3,36 +3,240 @@ package catalog
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
 
 	"gobase/shared"
 )
 
-func EncodeTuple(schema *Schema, values []any) shared.Tuple {
+func EncodeTuple(schema *Schema, values []any) (shared.Tuple, error) {
+	if len(values) != len(schema.Columns) {
+		return nil, &ValueCountError{Expected: len(schema.Columns), Got: len(values)}
+	}
+
 	buffer := new(bytes.Buffer)
 
+	nullBitmap := make([]byte, nullBitmapSize(len(schema.Columns)))
+	for i, col := range schema.Columns {
+		if values[i] == nil {
+			if !col.AcceptsNull() {
+				return nil, col.valueError(ErrNullValue, typeName(nil))
+			}
+			nullBitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	buffer.Write(nullBitmap)
+
 	for i, col := range schema.Columns {
+		if values[i] == nil {
+			continue
+		}
+
+		value, err := col.Convert(values[i])
+		if err != nil {
+			return nil, err
+		}
+
 		switch col.Type {
 		case TypeInt:
-			val := values[i].(int)
+			val := value.(int)
 			binary.Write(buffer, binary.LittleEndian, int32(val))
 		case TypeSmallInt:
-			val := values[i].(int)
+			val := value.(int)
 			binary.Write(buffer, binary.LittleEndian, int16(val))
 		case TypeBoolean:
-			val := values[i].(bool)
+			val := value.(bool)
 			if val {
 				buffer.WriteByte(1)
 			} else {
 				buffer.WriteByte(0)
 			}
 		case TypeVarchar:
-			val := values[i].(string)
+			val := value.(string)
 			binary.Write(buffer, binary.LittleEndian, uint16(len(val)))
 			buffer.WriteString(val)
-		default:
-			continue
+		case TypeBigInt:
+			val := value.(int64)
+			binary.Write(buffer, binary.LittleEndian, val)
+		case TypeDouble:
+			val := value.(float64)
+			binary.Write(buffer, binary.LittleEndian, math.Float64bits(val))
+		case TypeDecimal:
+			val := value.(Decimal)
+			binary.Write(buffer, binary.LittleEndian, val.Unscaled)
+		case TypeTimestamp:
+			val := value.(time.Time)
+			binary.Write(buffer, binary.LittleEndian, val.UnixMicro())
+		case TypeDate:
+			val := value.(time.Time)
+			binary.Write(buffer, binary.LittleEndian, int32(val.Unix()/SECONDS_PER_DAY))
+		case TypeUUID:
+			val := value.(UUID)
+			buffer.Write(val[:])
+		case TypeBlob:
+			val := value.([]byte)
+			binary.Write(buffer, binary.LittleEndian, uint16(len(val)))
+			buffer.Write(val)
+		}
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c *Column) Validate(value any) error {
+	_, err := c.Convert(value)
+	return err
+}
+
+// Convert returns value as the column stores it: integers widened to the
+// column type and decimals rounded to its scale.
+func (c *Column) Convert(value any) (any, error) {
+	switch c.Type {
+	case TypeInt:
+		val, ok := value.(int)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		if val < math.MinInt32 || val > math.MaxInt32 {
+			return nil, c.valueError(ErrValueOutOfRange, fmt.Sprint(val))
+		}
+		return val, nil
+	case TypeSmallInt:
+		val, ok := value.(int)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		if val < math.MinInt16 || val > math.MaxInt16 {
+			return nil, c.valueError(ErrValueOutOfRange, fmt.Sprint(val))
+		}
+		return val, nil
+	case TypeBoolean:
+		val, ok := value.(bool)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		return val, nil
+	case TypeVarchar:
+		val, ok := value.(string)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		if !c.fitsSize(len(val)) {
+			return nil, c.valueError(ErrValueTooLong, fmt.Sprintf("%d bytes", len(val)))
+		}
+		return val, nil
+	case TypeBigInt:
+		switch val := value.(type) {
+		case int64:
+			return val, nil
+		case int:
+			return int64(val), nil
+		}
+		return nil, c.valueError(ErrTypeMismatch, typeName(value))
+	case TypeDouble:
+		val, ok := value.(float64)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		return val, nil
+	case TypeDecimal:
+		val, ok := value.(Decimal)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
 		}
+		rescaled, ok := val.rescale(c.Scale)
+		if !ok || absInt64(rescaled.Unscaled) >= pow10(c.precision()) {
+			return nil, c.valueError(ErrValueOutOfRange, val.String())
+		}
+		return rescaled, nil
+	case TypeTimestamp:
+		val, ok := value.(time.Time)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		return val.UTC().Truncate(time.Microsecond), nil
+	case TypeDate:
+		val, ok := value.(time.Time)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		year, month, day := val.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	case TypeUUID:
+		switch val := value.(type) {
+		case UUID:
+			return val, nil
+		case [16]byte:
+			return UUID(val), nil
+		}
+		return nil, c.valueError(ErrTypeMismatch, typeName(value))
+	case TypeBlob:
+		val, ok := value.([]byte)
+		if !ok {
+			return nil, c.valueError(ErrTypeMismatch, typeName(value))
+		}
+		if !c.fitsSize(len(val)) {
+			return nil, c.valueError(ErrValueTooLong, fmt.Sprintf("%d bytes", len(val)))
+		}
+		return val, nil
+	}
+
+	return nil, c.valueError(ErrUnknownColumnType, typeName(value))
+}
+
+func (c *Column) fitsSize(length int) bool {
+	if c.Size > 0 && length > int(c.Size) {
+		return false
+	}
+
+	return length <= math.MaxUint16
+}
+
+func (c *Column) precision() uint8 {
+	if c.Precision == 0 || c.Precision > MAX_DECIMAL_PRECISION {
+		return MAX_DECIMAL_PRECISION
+	}
+
+	return c.Precision
+}
+
+func (c *Column) valueError(err error, got string) error {
+	expected := c.Type.String()
+	switch c.Type {
+	case TypeVarchar, TypeBlob:
+		if c.Size > 0 {
+			expected = fmt.Sprintf("%s(%d)", expected, c.Size)
+		}
+	case TypeDecimal:
+		expected = fmt.Sprintf("%s(%d,%d)", expected, c.precision(), c.Scale)
 	}
 
-	return buffer.Bytes()
+	return &ColumnValueError{
+		Column:   c.Name,
+		Expected: expected,
+		Got:      got,
+		Err:      err,
+	}
+}
+
+func typeName(value any) string {
+	if value == nil {
+		return "NULL"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func pow10(exponent uint8) uint64 {
+	result := uint64(1)
+	for i := uint8(0); i < exponent; i++ {
+		result *= 10
+	}
+
+	return result
+}
+
+func nullBitmapSize(numColumns int) int {
+	return (numColumns + 7) / 8
 }
@@ -0,0 +1,147 @@
+package catalog
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeTuple_ErrNullValue(t *testing.T) {
+	schema := NewSchema([]Column{
+		{Name: "id", Type: TypeInt, PrimaryKey: true, Nullable: true},
+		{Name: "name", Type: TypeVarchar, Size: 50},
+		{Name: "email", Type: TypeVarchar, Size: 100, Nullable: true},
+	})
+
+	tests := []struct {
+		name   string
+		values []any
+		column string
+	}{
+		{name: "primary key", values: []any{nil, "Alice", nil}, column: "id"},
+		{name: "not nullable", values: []any{1, nil, nil}, column: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncodeTuple(schema, tt.values)
+			require.ErrorIs(t, err, ErrNullValue)
+
+			var valueErr *ColumnValueError
+			require.ErrorAs(t, err, &valueErr)
+			assert.Equal(t, tt.column, valueErr.Column)
+		})
+	}
+
+	tuple, err := EncodeTuple(schema, []any{1, "Alice", nil})
+	require.NoError(t, err)
+	assert.Equal(t, []any{1, "Alice", nil}, DecodeTuple(schema, tuple))
+}
+
+func TestEncodeTuple_RoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 3, 15, 10, 30, 45, 123456000, time.UTC)
+	date := time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC)
+	uuid, err := ParseUUID("123e4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		column   Column
+		value    any
+		expected any
+	}{
+		{name: "int", column: Column{Type: TypeInt}, value: -42, expected: -42},
+		{name: "smallint", column: Column{Type: TypeSmallInt}, value: math.MaxInt16, expected: math.MaxInt16},
+		{name: "boolean", column: Column{Type: TypeBoolean}, value: true, expected: true},
+		{name: "varchar", column: Column{Type: TypeVarchar, Size: 10}, value: "hello", expected: "hello"},
+		{name: "bigint", column: Column{Type: TypeBigInt}, value: int64(math.MinInt64), expected: int64(math.MinInt64)},
+		{name: "bigint from int", column: Column{Type: TypeBigInt}, value: 7, expected: int64(7)},
+		{name: "double", column: Column{Type: TypeDouble}, value: -3.25, expected: -3.25},
+		{name: "decimal", column: Column{Type: TypeDecimal, Precision: 10, Scale: 2}, value: NewDecimal(-12345, 2), expected: NewDecimal(-12345, 2)},
+		{name: "decimal rescaled", column: Column{Type: TypeDecimal, Precision: 10, Scale: 2}, value: NewDecimal(12345, 3), expected: NewDecimal(1235, 2)},
+		{name: "decimal rounded once", column: Column{Type: TypeDecimal, Precision: 10, Scale: 1}, value: NewDecimal(149, 3), expected: NewDecimal(1, 1)},
+		{name: "negative decimal rounded once", column: Column{Type: TypeDecimal, Precision: 10, Scale: 1}, value: NewDecimal(-149, 3), expected: NewDecimal(-1, 1)},
+		{name: "timestamp", column: Column{Type: TypeTimestamp}, value: timestamp.Add(789), expected: timestamp},
+		{name: "timestamp in another zone", column: Column{Type: TypeTimestamp}, value: timestamp.In(time.FixedZone("UTC+2", 2*60*60)), expected: timestamp},
+		{name: "date", column: Column{Type: TypeDate}, value: date.Add(13 * time.Hour), expected: date},
+		{name: "uuid", column: Column{Type: TypeUUID}, value: uuid, expected: uuid},
+		{name: "uuid from array", column: Column{Type: TypeUUID}, value: [16]byte(uuid), expected: uuid},
+		{name: "blob", column: Column{Type: TypeBlob}, value: []byte{0x00, 0xFF, 0x01}, expected: []byte{0x00, 0xFF, 0x01}},
+		{name: "empty blob", column: Column{Type: TypeBlob}, value: []byte{}, expected: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := tt.column
+			column.Name = "value"
+			column.Nullable = true
+			schema := NewSchema([]Column{column, {Name: "after", Type: TypeInt}})
+
+			tuple, err := EncodeTuple(schema, []any{tt.value, 1})
+			require.NoError(t, err)
+			assert.Equal(t, []any{tt.expected, 1}, DecodeTuple(schema, tuple))
+
+			tuple, err = EncodeTuple(schema, []any{nil, 1})
+			require.NoError(t, err)
+			assert.Equal(t, []any{nil, 1}, DecodeTuple(schema, tuple))
+		})
+	}
+}
+
+func TestEncodeTuple_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   Column
+		value    any
+		err      error
+		expected string
+	}{
+		{name: "int type mismatch", column: Column{Type: TypeInt}, value: "1", err: ErrTypeMismatch, expected: "INT"},
+		{name: "int out of range", column: Column{Type: TypeInt}, value: math.MaxInt32 + 1, err: ErrValueOutOfRange, expected: "INT"},
+		{name: "smallint out of range", column: Column{Type: TypeSmallInt}, value: math.MinInt16 - 1, err: ErrValueOutOfRange, expected: "SMALLINT"},
+		{name: "boolean type mismatch", column: Column{Type: TypeBoolean}, value: 1, err: ErrTypeMismatch, expected: "BOOLEAN"},
+		{name: "varchar too long", column: Column{Type: TypeVarchar, Size: 3}, value: "abcd", err: ErrValueTooLong, expected: "VARCHAR(3)"},
+		{name: "bigint type mismatch", column: Column{Type: TypeBigInt}, value: 1.5, err: ErrTypeMismatch, expected: "BIGINT"},
+		{name: "double type mismatch", column: Column{Type: TypeDouble}, value: 1, err: ErrTypeMismatch, expected: "DOUBLE"},
+		{name: "decimal type mismatch", column: Column{Type: TypeDecimal, Precision: 5, Scale: 2}, value: 1.5, err: ErrTypeMismatch, expected: "DECIMAL(5,2)"},
+		{name: "decimal exceeds precision", column: Column{Type: TypeDecimal, Precision: 5, Scale: 2}, value: NewDecimal(100000, 2), err: ErrValueOutOfRange, expected: "DECIMAL(5,2)"},
+		{name: "decimal rescale overflows", column: Column{Type: TypeDecimal, Scale: 18}, value: NewDecimal(math.MaxInt64, 0), err: ErrValueOutOfRange, expected: "DECIMAL(18,18)"},
+		{name: "timestamp type mismatch", column: Column{Type: TypeTimestamp}, value: "2024-01-01", err: ErrTypeMismatch, expected: "TIMESTAMP"},
+		{name: "date type mismatch", column: Column{Type: TypeDate}, value: int64(0), err: ErrTypeMismatch, expected: "DATE"},
+		{name: "uuid type mismatch", column: Column{Type: TypeUUID}, value: "123e4567-e89b-12d3-a456-426614174000", err: ErrTypeMismatch, expected: "UUID"},
+		{name: "blob type mismatch", column: Column{Type: TypeBlob}, value: "abc", err: ErrTypeMismatch, expected: "BLOB"},
+		{name: "blob too long", column: Column{Type: TypeBlob, Size: 2}, value: []byte{1, 2, 3}, err: ErrValueTooLong, expected: "BLOB(2)"},
+		{name: "unknown column type", column: Column{Type: ColumnType(99)}, value: 1, err: ErrUnknownColumnType, expected: "UNKNOWN(99)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := tt.column
+			column.Name = "value"
+			schema := NewSchema([]Column{column})
+
+			_, err := EncodeTuple(schema, []any{tt.value})
+			require.ErrorIs(t, err, tt.err)
+
+			var valueErr *ColumnValueError
+			require.ErrorAs(t, err, &valueErr)
+			assert.Equal(t, "value", valueErr.Column)
+			assert.Equal(t, tt.expected, valueErr.Expected)
+		})
+	}
+}
+
+func TestEncodeTuple_ErrValueCountMismatch(t *testing.T) {
+	schema := NewSchema([]Column{{Name: "id", Type: TypeInt}, {Name: "name", Type: TypeVarchar}})
+
+	_, err := EncodeTuple(schema, []any{1})
+	require.ErrorIs(t, err, ErrValueCountMismatch)
+
+	var countErr *ValueCountError
+	require.ErrorAs(t, err, &countErr)
+	assert.Equal(t, 2, countErr.Expected)
+	assert.Equal(t, 1, countErr.Got)
+}
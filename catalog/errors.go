@@ -0,0 +1,36 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrValueCountMismatch = errors.New("value count mismatch")
+	ErrTypeMismatch       = errors.New("type mismatch")
+	ErrValueOutOfRange    = errors.New("value out of range")
+	ErrValueTooLong       = errors.New("value too long")
+	ErrUnknownColumnType  = errors.New("unknown column type")
+	ErrInvalidDecimal     = errors.New("invalid decimal")
+	ErrInvalidUUID        = errors.New("invalid uuid")
+	ErrNullValue          = errors.New("null value in a column that is not nullable")
+	ErrColumnNotFound     = errors.New("column not found")
+	ErrAmbiguousColumn    = errors.New("column reference is ambiguous")
+	ErrUnknownTable       = errors.New("unknown table")
+)
+
+func (e *ValueCountError) Error() string {
+	return fmt.Sprintf("%v: expected %d values, got %d", ErrValueCountMismatch, e.Expected, e.Got)
+}
+
+func (e *ValueCountError) Unwrap() error {
+	return ErrValueCountMismatch
+}
+
+func (e *ColumnValueError) Error() string {
+	return fmt.Sprintf("%v: column %s expects %s, got %s", e.Err, e.Column, e.Expected, e.Got)
+}
+
+func (e *ColumnValueError) Unwrap() error {
+	return e.Err
+}
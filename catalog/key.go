@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+func EncodeKey(schema *Schema, columnIndexes []int, values []any) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+
+	for i, columnIndex := range columnIndexes {
+		col := schema.Columns[columnIndex]
+		if col.Nullable {
+			if values[i] == nil {
+				buffer.WriteByte(0)
+				continue
+			}
+			buffer.WriteByte(1)
+		} else if values[i] == nil {
+			return nil, col.valueError(ErrNullValue, typeName(nil))
+		}
+
+		value, err := col.Convert(values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		switch col.Type {
+		case TypeInt:
+			val := value.(int)
+			binary.Write(buffer, binary.BigEndian, uint32(int32(val))^(1<<31))
+		case TypeSmallInt:
+			val := value.(int)
+			binary.Write(buffer, binary.BigEndian, uint16(int16(val))^(1<<15))
+		case TypeBoolean:
+			val := value.(bool)
+			if val {
+				buffer.WriteByte(1)
+			} else {
+				buffer.WriteByte(0)
+			}
+		case TypeVarchar:
+			val := value.(string)
+			writeEscapedKey(buffer, []byte(val))
+		case TypeBigInt:
+			val := value.(int64)
+			binary.Write(buffer, binary.BigEndian, uint64(val)^(1<<63))
+		case TypeDouble:
+			val := value.(float64)
+			bits := math.Float64bits(val)
+			if bits&(1<<63) != 0 {
+				bits = ^bits
+			} else {
+				bits ^= 1 << 63
+			}
+			binary.Write(buffer, binary.BigEndian, bits)
+		case TypeDecimal:
+			val := value.(Decimal)
+			binary.Write(buffer, binary.BigEndian, uint64(val.Unscaled)^(1<<63))
+		case TypeTimestamp:
+			val := value.(time.Time)
+			binary.Write(buffer, binary.BigEndian, uint64(val.UnixMicro())^(1<<63))
+		case TypeDate:
+			val := value.(time.Time)
+			binary.Write(buffer, binary.BigEndian, uint32(int32(val.Unix()/SECONDS_PER_DAY))^(1<<31))
+		case TypeUUID:
+			val := value.(UUID)
+			buffer.Write(val[:])
+		case TypeBlob:
+			val := value.([]byte)
+			writeEscapedKey(buffer, val)
+		}
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeEscapedKey(buffer *bytes.Buffer, val []byte) {
+	for j := 0; j < len(val); j++ {
+		buffer.WriteByte(val[j])
+		if val[j] == 0x00 {
+			buffer.WriteByte(0xFF)
+		}
+	}
+	buffer.Write([]byte{0x00, 0x01})
+}
@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeKey_Errors(t *testing.T) {
+	schema := NewSchema([]Column{
+		{Name: "id", Type: TypeInt},
+		{Name: "name", Type: TypeVarchar, Size: 5},
+		{Name: "email", Type: TypeVarchar, Size: 100, Nullable: true},
+	})
+
+	tests := []struct {
+		name    string
+		columns []int
+		values  []any
+		err     error
+		column  string
+	}{
+		{name: "null in not nullable column", columns: []int{0}, values: []any{nil}, err: ErrNullValue, column: "id"},
+		{name: "type mismatch", columns: []int{2, 0}, values: []any{"a@b.c", "one"}, err: ErrTypeMismatch, column: "id"},
+		{name: "value too long", columns: []int{1}, values: []any{"Alexandra"}, err: ErrValueTooLong, column: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := EncodeKey(schema, tt.columns, tt.values)
+			require.ErrorIs(t, err, tt.err)
+			assert.Nil(t, key)
+
+			var valueErr *ColumnValueError
+			require.ErrorAs(t, err, &valueErr)
+			assert.Equal(t, tt.column, valueErr.Column)
+		})
+	}
+
+	withNull, err := EncodeKey(schema, []int{2, 0}, []any{nil, 1})
+	require.NoError(t, err)
+	withValue, err := EncodeKey(schema, []int{2, 0}, []any{"a@b.c", 1})
+	require.NoError(t, err)
+	assert.Less(t, string(withNull), string(withValue))
+}
+
+func TestEncodeKey_PreservesOrder(t *testing.T) {
+	day := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		column Column
+		values []any
+	}{
+		{name: "int", column: Column{Type: TypeInt}, values: []any{math.MinInt32, -1, 0, 1, math.MaxInt32}},
+		{name: "smallint", column: Column{Type: TypeSmallInt}, values: []any{math.MinInt16, -1, 0, math.MaxInt16}},
+		{name: "boolean", column: Column{Type: TypeBoolean}, values: []any{false, true}},
+		{name: "varchar", column: Column{Type: TypeVarchar}, values: []any{"", "a", "a\x00", "a\x00b", "ab", "b"}},
+		{name: "bigint", column: Column{Type: TypeBigInt}, values: []any{int64(math.MinInt64), int64(-1), int64(0), int64(math.MaxInt64)}},
+		{name: "double", column: Column{Type: TypeDouble}, values: []any{math.Inf(-1), -2.5, -0.5, 0.0, 0.5, 2.5, math.Inf(1)}},
+		{name: "decimal", column: Column{Type: TypeDecimal, Precision: 10, Scale: 2}, values: []any{NewDecimal(-100, 2), NewDecimal(-5, 1), NewDecimal(0, 0), NewDecimal(1, 2), NewDecimal(1, 0)}},
+		{name: "timestamp", column: Column{Type: TypeTimestamp}, values: []any{time.Unix(-1, 0), time.Unix(0, 0), day, day.Add(time.Microsecond)}},
+		{name: "date", column: Column{Type: TypeDate}, values: []any{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), day, day.AddDate(0, 0, 1)}},
+		{name: "uuid", column: Column{Type: TypeUUID}, values: []any{UUID{}, UUID{0, 1}, UUID{1}}},
+		{name: "blob", column: Column{Type: TypeBlob}, values: []any{[]byte{}, []byte{0x00}, []byte{0x00, 0x00}, []byte{0x01}, []byte{0xFF}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := tt.column
+			column.Name = "value"
+			schema := NewSchema([]Column{column})
+
+			var previous []byte
+			for i, value := range tt.values {
+				key, err := EncodeKey(schema, []int{0}, []any{value})
+				require.NoError(t, err)
+				if i > 0 {
+					assert.Less(t, string(previous), string(key), "%v < %v", tt.values[i-1], value)
+				}
+				previous = key
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package catalog
 
+// Table only qualifies the columns of query results; stored schemas leave it
+// empty.
 type Column struct {
-	Name     string
-	Type     ColumnType
-	Size     uint16
-	Nullable bool
+	Table      string
+	Name       string
+	Type       ColumnType
+	Size       uint16
+	Precision  uint8
+	Scale      uint8
+	Nullable   bool
+	PrimaryKey bool
+	Unique     bool
 }
 
 type Schema struct {
@@ -14,3 +21,26 @@ type Schema struct {
 func NewSchema(columns []Column) *Schema {
 	return &Schema{Columns: columns}
 }
+
+func NewDecimal(unscaled int64, scale uint8) Decimal {
+	return Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+type Decimal struct {
+	Unscaled int64
+	Scale    uint8
+}
+
+type UUID [16]byte
+
+type ValueCountError struct {
+	Expected int
+	Got      int
+}
+
+type ColumnValueError struct {
+	Column   string
+	Expected string
+	Got      string
+	Err      error
+}
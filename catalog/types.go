@@ -1,5 +1,7 @@
 package catalog
 
+import "fmt"
+
 type ColumnType uint8
 
 const (
@@ -7,4 +9,40 @@ const (
 	TypeSmallInt
 	TypeBoolean
 	TypeVarchar
+	TypeBigInt
+	TypeDouble
+	TypeDecimal
+	TypeTimestamp
+	TypeDate
+	TypeUUID
+	TypeBlob
 )
+
+func (t ColumnType) String() string {
+	switch t {
+	case TypeInt:
+		return "INT"
+	case TypeSmallInt:
+		return "SMALLINT"
+	case TypeBoolean:
+		return "BOOLEAN"
+	case TypeVarchar:
+		return "VARCHAR"
+	case TypeBigInt:
+		return "BIGINT"
+	case TypeDouble:
+		return "DOUBLE"
+	case TypeDecimal:
+		return "DECIMAL"
+	case TypeTimestamp:
+		return "TIMESTAMP"
+	case TypeDate:
+		return "DATE"
+	case TypeUUID:
+		return "UUID"
+	case TypeBlob:
+		return "BLOB"
+	}
+
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(t))
+}
@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func ParseDecimal(s string) (Decimal, error) {
+	integerPart, fractionPart, _ := strings.Cut(s, ".")
+	if len(fractionPart) > int(MAX_DECIMAL_PRECISION) {
+		return Decimal{}, ErrInvalidDecimal
+	}
+
+	unscaled, err := strconv.ParseInt(integerPart+fractionPart, 10, 64)
+	if err != nil || strings.ContainsAny(fractionPart, "+-") {
+		return Decimal{}, ErrInvalidDecimal
+	}
+
+	return NewDecimal(unscaled, uint8(len(fractionPart))), nil
+}
+
+func (d Decimal) String() string {
+	digits := strconv.FormatUint(absInt64(d.Unscaled), 10)
+	if d.Scale > 0 {
+		if len(digits) <= int(d.Scale) {
+			digits = strings.Repeat("0", int(d.Scale)-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-int(d.Scale)] + "." + digits[len(digits)-int(d.Scale):]
+	}
+
+	if d.Unscaled < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// ToFloat64 widens any numeric value to a float64; other values give 0.
+func ToFloat64(value any) float64 {
+	switch val := value.(type) {
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case float64:
+		return val
+	case Decimal:
+		f, _ := strconv.ParseFloat(val.String(), 64)
+		return f
+	}
+
+	return 0
+}
+
+func (d Decimal) rescale(scale uint8) (Decimal, bool) {
+	unscaled := d.Unscaled
+
+	for s := d.Scale; s < scale; s++ {
+		if unscaled > math.MaxInt64/10 || unscaled < math.MinInt64/10 {
+			return Decimal{}, false
+		}
+		unscaled *= 10
+	}
+
+	if d.Scale <= scale {
+		return NewDecimal(unscaled, scale), true
+	}
+
+	// Rounding half away from zero must look at the whole dropped remainder
+	// at once: rounding digit by digit turns 0.149 into 0.15 and then 0.2.
+	magnitude := absInt64(unscaled)
+	quotient := uint64(0)
+	if d.Scale-scale <= MAX_UINT64_POW10 {
+		divisor := pow10(d.Scale - scale)
+		quotient = magnitude / divisor
+		if remainder := magnitude % divisor; remainder >= divisor-remainder {
+			quotient++
+		}
+	}
+
+	if unscaled < 0 {
+		return NewDecimal(-int64(quotient), scale), true
+	}
+
+	return NewDecimal(int64(quotient), scale), true
+}
+
+func ParseUUID(s string) (UUID, error) {
+	var uuid UUID
+
+	digits := strings.ReplaceAll(s, "-", "")
+	if len(digits) != 2*len(uuid) {
+		return UUID{}, ErrInvalidUUID
+	}
+
+	_, err := hex.Decode(uuid[:], []byte(digits))
+	if err != nil {
+		return UUID{}, ErrInvalidUUID
+	}
+
+	return uuid, nil
+}
+
+func (u UUID) String() string {
+	digits := hex.EncodeToString(u[:])
+	return digits[0:8] + "-" + digits[8:12] + "-" + digits[12:16] + "-" + digits[16:20] + "-" + digits[20:]
+}
+
+func absInt64(val int64) uint64 {
+	if val < 0 {
+		return uint64(-(val + 1)) + 1
+	}
+
+	return uint64(val)
+}
@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Decimal
+		output   string
+	}{
+		{input: "12.50", expected: NewDecimal(1250, 2), output: "12.50"},
+		{input: "-0.05", expected: NewDecimal(-5, 2), output: "-0.05"},
+		{input: "42", expected: NewDecimal(42, 0), output: "42"},
+		{input: ".5", expected: NewDecimal(5, 1), output: "0.5"},
+		{input: "-9223372036854775808", expected: NewDecimal(-9223372036854775808, 0), output: "-9223372036854775808"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			decimal, err := ParseDecimal(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decimal)
+			assert.Equal(t, tt.output, decimal.String())
+		})
+	}
+}
+
+func TestParseDecimal_ErrInvalidDecimal(t *testing.T) {
+	inputs := []string{"", "abc", "1.2.3", "1.-5", "1.+5", "99999999999999999999", "0.1234567890123456789"}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseDecimal(input)
+			assert.ErrorIs(t, err, ErrInvalidDecimal)
+		})
+	}
+}
+
+func TestDecimal_Rescale(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Decimal
+		scale    uint8
+		expected Decimal
+	}{
+		{name: "rounds once", value: NewDecimal(149, 3), scale: 1, expected: NewDecimal(1, 1)},
+		{name: "rounds once negative", value: NewDecimal(-149, 3), scale: 1, expected: NewDecimal(-1, 1)},
+		{name: "half away from zero", value: NewDecimal(150, 3), scale: 1, expected: NewDecimal(2, 1)},
+		{name: "half away from zero negative", value: NewDecimal(-150, 3), scale: 1, expected: NewDecimal(-2, 1)},
+		{name: "widens", value: NewDecimal(15, 1), scale: 3, expected: NewDecimal(1500, 3)},
+		{name: "min int64", value: NewDecimal(math.MinInt64, 18), scale: 0, expected: NewDecimal(-9, 0)},
+		{name: "drops every digit", value: NewDecimal(math.MaxInt64, 19), scale: 0, expected: NewDecimal(1, 0)},
+		{name: "beyond uint64 range", value: NewDecimal(math.MaxInt64, 40), scale: 0, expected: NewDecimal(0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rescaled, ok := tt.value.rescale(tt.scale)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, rescaled)
+		})
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	uuid, err := ParseUUID("123E4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", uuid.String())
+
+	withoutDashes, err := ParseUUID("123e4567e89b12d3a456426614174000")
+	require.NoError(t, err)
+	assert.Equal(t, uuid, withoutDashes)
+}
+
+func TestParseUUID_ErrInvalidUUID(t *testing.T) {
+	inputs := []string{"", "123e4567-e89b-12d3-a456-42661417400", "123e4567-e89b-12d3-a456-4266141740000", "123e4567-e89b-12d3-a456-42661417400g"}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseUUID(input)
+			assert.ErrorIs(t, err, ErrInvalidUUID)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package disk_manager
+
+const (
+	HEADER_PAGE_ID  uint32 = 0
+	INVALID_PAGE_ID uint32 = 0xFFFFFFFF
+
+	MAGIC          = "GOBASEDB"
+	FORMAT_VERSION = uint32(1)
+
+	MAGIC_OFFSET                uint32 = 0
+	VERSION_OFFSET              uint32 = 12
+	PAGE_SIZE_OFFSET            uint32 = 16
+	CATALOG_ROOT_PAGE_ID_OFFSET uint32 = 20
+	FREE_LIST_HEAD_OFFSET       uint32 = 24
+
+	// The marker overlays the slot count of slotted pages and the node type of
+	// B+tree nodes, so neither mistakes a freed page for one of its own.
+	FREE_PAGE_MARKER_OFFSET uint32 = 12
+	FREE_PAGE_NEXT_OFFSET   uint32 = 16
+	FREE_PAGE_MARKER               = uint16(0xFFFF)
+)
@@ -1,6 +1,19 @@
 package disk_manager
 
+import (
+	"encoding/binary"
+
+	"gobase/shared"
+)
+
 func (dm *DiskManager) ReadPage(pageID uint32) (pageData []byte, err error) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	return dm.readPage(pageID)
+}
+
+func (dm *DiskManager) readPage(pageID uint32) ([]byte, error) {
 	if pageID >= dm.NumPages {
 		return nil, ErrPageDoesNotExist
 	}
@@ -17,10 +30,21 @@ func (dm *DiskManager) ReadPage(pageID uint32) (pageData []byte, err error) {
 		return nil, ErrIncompleteRead
 	}
 
+	if shared.GetPageChecksum(data) != shared.ComputePageChecksum(data) {
+		return nil, ErrPageCorrupted
+	}
+
 	return data, nil
 }
 
 func (dm *DiskManager) WritePage(pageID uint32, data []byte) error {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	return dm.writePage(pageID, data)
+}
+
+func (dm *DiskManager) writePage(pageID uint32, data []byte) error {
 	if pageID >= dm.NumPages {
 		return ErrPageDoesNotExist
 	}
@@ -31,7 +55,7 @@ func (dm *DiskManager) WritePage(pageID uint32, data []byte) error {
 
 	offset := calculateOffset(pageID, dm.PageSize)
 
-	_, err := dm.File.WriteAt(data, offset)
+	_, err := dm.File.WriteAt(withChecksum(data), offset)
 	if err != nil {
 		return ErrWriteFailed
 	}
@@ -40,23 +64,58 @@ func (dm *DiskManager) WritePage(pageID uint32, data []byte) error {
 }
 
 func (dm *DiskManager) AllocatePage() (newPageID uint32, err error) {
-	newPageID = dm.NumPages
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
 
-	offset := calculateOffset(dm.NumPages, dm.PageSize)
+	if dm.freeListHead != INVALID_PAGE_ID {
+		return dm.reuseFreePage()
+	}
 
-	emptyPage := make([]byte, dm.PageSize)
-	_, err = dm.File.WriteAt(emptyPage, offset)
-	if err != nil {
-		return 0, ErrAllocatePageFailed
+	return dm.appendPage()
+}
+
+// Freed pages keep the LSN of the last log record so recovery never replays
+// older records onto them. Flushing the log first makes the records that
+// unlinked the page durable before it can be reused.
+func (dm *DiskManager) DeallocatePage(pageID uint32) error {
+	var lsn uint64
+	if dm.Log != nil {
+		lsn = dm.Log.GetNextLSN() - 1
+		err := dm.Log.Flush(lsn)
+		if err != nil {
+			return err
+		}
 	}
 
-	dm.File.Sync()
-	dm.NumPages += 1
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
 
-	return newPageID, nil
+	if pageID == HEADER_PAGE_ID {
+		return ErrDeallocateHeaderPage
+	}
+	if pageID >= dm.NumPages {
+		return ErrPageDoesNotExist
+	}
+
+	data := make([]byte, dm.PageSize)
+	shared.SetPageLSN(data, lsn)
+	binary.LittleEndian.PutUint16(data[FREE_PAGE_MARKER_OFFSET:], FREE_PAGE_MARKER)
+	binary.LittleEndian.PutUint32(data[FREE_PAGE_NEXT_OFFSET:], dm.freeListHead)
+
+	err := dm.writePage(pageID, data)
+	if err != nil {
+		return err
+	}
+
+	dm.freeListHead = pageID
+	return dm.writeHeader()
 }
 
 func (dm *DiskManager) Close() error {
+	if dm.Log != nil {
+		dm.Log.Close()
+	}
+
 	dm.File.Sync()
 	return dm.File.Close()
 }
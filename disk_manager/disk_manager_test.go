@@ -4,6 +4,9 @@ import (
 	"os"
 	"testing"
 
+	"gobase/shared"
+	"gobase/slotted_page"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,14 +23,23 @@ func newTestDiskManager(t *testing.T, numPages uint32) (*DiskManager, func()) {
 	}
 
 	return &DiskManager{
-		File:     file,
-		PageSize: 8,
-		NumPages: numPages,
+		File:              file,
+		PageSize:          shared.DEFAULT_PAGE_SIZE,
+		NumPages:          numPages,
+		catalogRootPageID: INVALID_PAGE_ID,
+		freeListHead:      INVALID_PAGE_ID,
 	}, cleanup
 }
 
+func newTestPage(payload string) []byte {
+	data := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(data[shared.PAGE_HEADER_SIZE:], payload)
+
+	return data
+}
+
 func TestReadPage(t *testing.T) {
-	initialData := []byte("ABCDEFGH")
+	initialData := newTestPage("ABCDEFGH")
 
 	dm, cleanup := newTestDiskManager(t, 1)
 	defer cleanup()
@@ -37,7 +49,20 @@ func TestReadPage(t *testing.T) {
 	data, err := dm.ReadPage(0)
 	require.NoError(t, err)
 	assert.Equal(t, int(dm.PageSize), len(data))
-	assert.Equal(t, initialData, data)
+	assert.Equal(t, initialData[shared.PAGE_HEADER_SIZE:], data[shared.PAGE_HEADER_SIZE:])
+}
+
+func TestReadPage_Corrupted(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t, 1)
+	defer cleanup()
+
+	require.NoError(t, dm.WritePage(0, newTestPage("ABCDEFGH")))
+
+	_, err := dm.File.WriteAt([]byte("X"), int64(shared.PAGE_HEADER_SIZE))
+	require.NoError(t, err)
+
+	_, err = dm.ReadPage(0)
+	require.ErrorIs(t, err, ErrPageCorrupted)
 }
 
 func TestReadPage_PageIDDoesNotExist(t *testing.T) {
@@ -54,7 +79,7 @@ func TestReadPage_PageIDDoesNotExist(t *testing.T) {
 }
 
 func TestWritePage(t *testing.T) {
-	initialData := []byte("ABCDEFGH")
+	initialData := newTestPage("ABCDEFGH")
 
 	dm, cleanup := newTestDiskManager(t, 1)
 	defer cleanup()
@@ -63,11 +88,12 @@ func TestWritePage(t *testing.T) {
 
 	data, err := os.ReadFile(dm.File.Name())
 	require.NoError(t, err)
-	assert.Equal(t, initialData, data)
+	assert.Equal(t, initialData[shared.PAGE_HEADER_SIZE:], data[shared.PAGE_HEADER_SIZE:])
+	assert.Equal(t, shared.ComputePageChecksum(data), shared.GetPageChecksum(data))
 }
 
 func TestWritePage_PageIDDoesNotExist(t *testing.T) {
-	data := []byte("ABCDEFGH")
+	data := newTestPage("ABCDEFGH")
 
 	dm, cleanup := newTestDiskManager(t, 1)
 	defer cleanup()
@@ -77,7 +103,7 @@ func TestWritePage_PageIDDoesNotExist(t *testing.T) {
 }
 
 func TestWritePage_DataSizeDoesNotMatchPageSize(t *testing.T) {
-	data := []byte("ABCDEFGHI")
+	data := make([]byte, shared.DEFAULT_PAGE_SIZE+1)
 
 	dm, cleanup := newTestDiskManager(t, 1)
 	defer cleanup()
@@ -99,7 +125,7 @@ func TestAllocatePage(t *testing.T) {
 	require.NoError(t, err)
 
 	expectedData := make([]byte, dm.PageSize)
-	assert.Equal(t, expectedData, data)
+	assert.Equal(t, expectedData[shared.PAGE_HEADER_SIZE:], data[shared.PAGE_HEADER_SIZE:])
 }
 
 func TestAllocatePage_FileWriteError(t *testing.T) {
@@ -112,3 +138,77 @@ func TestAllocatePage_FileWriteError(t *testing.T) {
 	require.ErrorIs(t, err, ErrAllocatePageFailed)
 	assert.Equal(t, uint32(1), dm.NumPages)
 }
+
+func TestDeallocatePage_ReusesPage(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t, 1)
+	defer cleanup()
+
+	first, err := dm.AllocatePage()
+	require.NoError(t, err)
+	second, err := dm.AllocatePage()
+	require.NoError(t, err)
+
+	require.NoError(t, dm.WritePage(first, newTestPage("ABCDEFGH")))
+	require.NoError(t, dm.DeallocatePage(first))
+	require.NoError(t, dm.DeallocatePage(second))
+	assert.Equal(t, second, dm.GetFreeListHead())
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+	assert.Equal(t, second, pageID)
+
+	pageID, err = dm.AllocatePage()
+	require.NoError(t, err)
+	assert.Equal(t, first, pageID)
+	assert.Equal(t, INVALID_PAGE_ID, dm.GetFreeListHead())
+	assert.Equal(t, uint32(3), dm.NumPages)
+
+	data, err := dm.ReadPage(first)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, dm.PageSize)[shared.PAGE_HEADER_SIZE:], data[shared.PAGE_HEADER_SIZE:])
+
+	pageID, err = dm.AllocatePage()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), pageID)
+}
+
+func TestDeallocatePage_MarksPageAsFree(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t, 1)
+	defer cleanup()
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+
+	sp := slotted_page.NewSlottedPage(dm.PageSize)
+	_, err = sp.InsertTuple(shared.NewTuple("data_test"))
+	require.NoError(t, err)
+	require.NoError(t, dm.WritePage(pageID, sp.GetData()))
+	require.NoError(t, dm.DeallocatePage(pageID))
+
+	data, err := dm.ReadPage(pageID)
+	require.NoError(t, err)
+
+	_, err = slotted_page.FromData(data).GetTuple(0)
+	require.ErrorIs(t, err, slotted_page.ErrInvalidPageLayout)
+}
+
+func TestAllocatePage_ErrInvalidFreePage(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t, 1)
+	defer cleanup()
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+	require.NoError(t, dm.DeallocatePage(pageID))
+	require.NoError(t, dm.WritePage(pageID, newTestPage("ABCDEFGH")))
+
+	_, err = dm.AllocatePage()
+	require.ErrorIs(t, err, ErrInvalidFreePage)
+}
+
+func TestDeallocatePage_InvalidPage(t *testing.T) {
+	dm, cleanup := newTestDiskManager(t, 1)
+	defer cleanup()
+
+	require.ErrorIs(t, dm.DeallocatePage(HEADER_PAGE_ID), ErrDeallocateHeaderPage)
+	require.ErrorIs(t, dm.DeallocatePage(1), ErrPageDoesNotExist)
+}
@@ -3,11 +3,18 @@ package disk_manager
 import "errors"
 
 var (
-	ErrPageDoesNotExist    = errors.New("page does not exist")
-	ErrIncompleteRead      = errors.New("incomplete read")
-	ErrInvalidPageDataSize = errors.New("data size does not match page size")
-	ErrWriteFailed         = errors.New("write failed")
-	ErrAllocatePageFailed  = errors.New("failed to allocate page")
-	ErrOpenFileFailed      = errors.New("failed to open file")
-	ErrStatFileFailed      = errors.New("failed to stat file")
-)
\ No newline at end of file
+	ErrPageDoesNotExist     = errors.New("page does not exist")
+	ErrIncompleteRead       = errors.New("incomplete read")
+	ErrInvalidPageDataSize  = errors.New("data size does not match page size")
+	ErrWriteFailed          = errors.New("write failed")
+	ErrAllocatePageFailed   = errors.New("failed to allocate page")
+	ErrOpenFileFailed       = errors.New("failed to open file")
+	ErrStatFileFailed       = errors.New("failed to stat file")
+	ErrNotADatabase         = errors.New("file is not a gobase database")
+	ErrUnsupportedVersion   = errors.New("unsupported database format version")
+	ErrPageSizeMismatch     = errors.New("database page size does not match")
+	ErrInvalidPageSize      = errors.New("page size must be a power of two between 4 KiB and 64 KiB")
+	ErrPageCorrupted        = errors.New("page checksum mismatch")
+	ErrDeallocateHeaderPage = errors.New("cannot deallocate the header page")
+	ErrInvalidFreePage      = errors.New("free list points at a page that is not free")
+)
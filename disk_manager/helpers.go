@@ -1,5 +1,175 @@
 package disk_manager
 
+import (
+	"encoding/binary"
+
+	"gobase/shared"
+)
+
 func calculateOffset(pageID uint32, pageSize uint32) int64 {
 	return int64(pageID) * int64(pageSize)
 }
+
+func LogFilePath(filePath string) string {
+	return filePath + ".log"
+}
+
+func withChecksum(data []byte) []byte {
+	page := make([]byte, len(data))
+	copy(page, data)
+	shared.SetPageChecksum(page, shared.ComputePageChecksum(page))
+
+	return page
+}
+
+func (dm *DiskManager) ensurePageExists(pageID uint32) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	for dm.NumPages <= pageID {
+		_, err := dm.appendPage()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (dm *DiskManager) appendPage() (uint32, error) {
+	newPageID := dm.NumPages
+
+	offset := calculateOffset(dm.NumPages, dm.PageSize)
+
+	emptyPage := make([]byte, dm.PageSize)
+	_, err := dm.File.WriteAt(withChecksum(emptyPage), offset)
+	if err != nil {
+		return 0, ErrAllocatePageFailed
+	}
+
+	dm.File.Sync()
+	dm.NumPages += 1
+
+	return newPageID, nil
+}
+
+func (dm *DiskManager) reuseFreePage() (uint32, error) {
+	pageID := dm.freeListHead
+
+	data, err := dm.readPage(pageID)
+	if err != nil {
+		return 0, err
+	}
+
+	if binary.LittleEndian.Uint16(data[FREE_PAGE_MARKER_OFFSET:]) != FREE_PAGE_MARKER {
+		return 0, ErrInvalidFreePage
+	}
+
+	nextFreePageID := binary.LittleEndian.Uint32(data[FREE_PAGE_NEXT_OFFSET:])
+
+	emptyPage := make([]byte, dm.PageSize)
+	shared.SetPageLSN(emptyPage, shared.GetPageLSN(data))
+
+	err = dm.writePage(pageID, emptyPage)
+	if err != nil {
+		return 0, ErrAllocatePageFailed
+	}
+
+	dm.freeListHead = nextFreePageID
+	err = dm.writeHeader()
+	if err != nil {
+		return 0, err
+	}
+
+	return pageID, nil
+}
+
+func (dm *DiskManager) GetCatalogRootPageID() uint32 {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	return dm.catalogRootPageID
+}
+
+func (dm *DiskManager) SetCatalogRootPageID(pageID uint32) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	dm.catalogRootPageID = pageID
+	return dm.writeHeader()
+}
+
+func (dm *DiskManager) GetFreeListHead() uint32 {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	return dm.freeListHead
+}
+
+func (dm *DiskManager) initHeader() error {
+	if dm.PageSize == 0 {
+		dm.PageSize = shared.DEFAULT_PAGE_SIZE
+	}
+	if !shared.IsValidPageSize(dm.PageSize) {
+		return ErrInvalidPageSize
+	}
+
+	dm.NumPages = 1
+	return dm.writeHeader()
+}
+
+func (dm *DiskManager) readHeader(fileSize int64) error {
+	prefix := make([]byte, PAGE_SIZE_OFFSET+4)
+	_, err := dm.File.ReadAt(prefix, 0)
+	if err != nil {
+		return ErrNotADatabase
+	}
+
+	if string(prefix[MAGIC_OFFSET:MAGIC_OFFSET+uint32(len(MAGIC))]) != MAGIC {
+		return ErrNotADatabase
+	}
+	if binary.LittleEndian.Uint32(prefix[VERSION_OFFSET:]) != FORMAT_VERSION {
+		return ErrUnsupportedVersion
+	}
+
+	pageSize := binary.LittleEndian.Uint32(prefix[PAGE_SIZE_OFFSET:])
+	if !shared.IsValidPageSize(pageSize) {
+		return ErrInvalidPageSize
+	}
+	if dm.PageSize != 0 && dm.PageSize != pageSize {
+		return ErrPageSizeMismatch
+	}
+
+	data := make([]byte, pageSize)
+	_, err = dm.File.ReadAt(data, calculateOffset(HEADER_PAGE_ID, pageSize))
+	if err != nil {
+		return ErrNotADatabase
+	}
+
+	if shared.GetPageChecksum(data) != shared.ComputePageChecksum(data) {
+		return ErrPageCorrupted
+	}
+
+	dm.PageSize = pageSize
+	dm.NumPages = uint32(fileSize / int64(pageSize))
+	dm.catalogRootPageID = binary.LittleEndian.Uint32(data[CATALOG_ROOT_PAGE_ID_OFFSET:])
+	dm.freeListHead = binary.LittleEndian.Uint32(data[FREE_LIST_HEAD_OFFSET:])
+
+	return nil
+}
+
+func (dm *DiskManager) writeHeader() error {
+	data := make([]byte, dm.PageSize)
+	copy(data[MAGIC_OFFSET:], MAGIC)
+	binary.LittleEndian.PutUint32(data[VERSION_OFFSET:], FORMAT_VERSION)
+	binary.LittleEndian.PutUint32(data[PAGE_SIZE_OFFSET:], dm.PageSize)
+	binary.LittleEndian.PutUint32(data[CATALOG_ROOT_PAGE_ID_OFFSET:], dm.catalogRootPageID)
+	binary.LittleEndian.PutUint32(data[FREE_LIST_HEAD_OFFSET:], dm.freeListHead)
+
+	_, err := dm.File.WriteAt(withChecksum(data), calculateOffset(HEADER_PAGE_ID, dm.PageSize))
+	if err != nil {
+		return ErrWriteFailed
+	}
+
+	return dm.File.Sync()
+}
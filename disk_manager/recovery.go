@@ -0,0 +1,239 @@
+package disk_manager
+
+import (
+	"errors"
+
+	"gobase/log_manager"
+	"gobase/shared"
+	"gobase/slotted_page"
+)
+
+func (dm *DiskManager) recover() error {
+	records, err := dm.Log.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 || len(records) == 1 && records[0].Type == log_manager.RecordCheckpoint {
+		return nil
+	}
+
+	recordsByLSN := make(map[uint64]*log_manager.LogRecord)
+	lastLSNs := make(map[uint64]uint64)
+
+	for _, rec := range records {
+		recordsByLSN[rec.LSN] = rec
+
+		if rec.TxnID == log_manager.INVALID_TXN_ID {
+			continue
+		}
+
+		switch rec.Type {
+		case log_manager.RecordCommit, log_manager.RecordAbort:
+			delete(lastLSNs, rec.TxnID)
+		default:
+			lastLSNs[rec.TxnID] = rec.LSN
+		}
+	}
+
+	for _, rec := range records {
+		err = dm.redo(rec)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = dm.undo(lastLSNs, recordsByLSN)
+	if err != nil {
+		return err
+	}
+
+	return dm.Log.Checkpoint()
+}
+
+func (dm *DiskManager) redo(rec *log_manager.LogRecord) error {
+	switch rec.Type {
+	case log_manager.RecordInsertTuple:
+		return dm.redoSlottedPage(rec.PageID, rec.LSN, func(sp *slotted_page.SlottedPage) error {
+			return sp.RestoreTuple(rec.SlotID, rec.Data)
+		})
+	case log_manager.RecordDeleteTuple:
+		return dm.redoSlottedPage(rec.PageID, rec.LSN, func(sp *slotted_page.SlottedPage) error {
+			return sp.DeleteTuple(rec.SlotID)
+		})
+	case log_manager.RecordUpdateTuple:
+		_, newTuple, err := log_manager.DecodeUpdate(rec.Data)
+		if err != nil {
+			return err
+		}
+
+		return dm.redoSlottedPage(rec.PageID, rec.LSN, func(sp *slotted_page.SlottedPage) error {
+			return sp.UpdateTuple(rec.SlotID, newTuple)
+		})
+	case log_manager.RecordCompensation:
+		return dm.redoSlottedPage(rec.PageID, rec.LSN, func(sp *slotted_page.SlottedPage) error {
+			if len(rec.Data) == 0 {
+				return sp.DeleteTuple(rec.SlotID)
+			}
+
+			err := sp.UpdateTuple(rec.SlotID, rec.Data)
+			if errors.Is(err, slotted_page.ErrTupleHasBeenDeleted) || errors.Is(err, slotted_page.ErrorSlotDidntExists) {
+				return sp.RestoreTuple(rec.SlotID, rec.Data)
+			}
+			return err
+		})
+	case log_manager.RecordNewPage:
+		err := dm.redoFullPage(rec.PageID, rec.LSN, func(data []byte) error {
+			slotted_page.InitSlottedPage(data)
+			slotted_page.FromData(data).SetPrevPageID(rec.PrevPageID)
+			return nil
+		})
+		if err != nil || rec.PrevPageID == slotted_page.NULL_PAGE_ID {
+			return err
+		}
+
+		return dm.redoSlottedPage(rec.PrevPageID, rec.LSN, func(sp *slotted_page.SlottedPage) error {
+			sp.SetNextPageID(rec.PageID)
+			return nil
+		})
+	case log_manager.RecordPageImage:
+		return dm.redoFullPage(rec.PageID, rec.LSN, func(data []byte) error {
+			copy(data, rec.Data)
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func (dm *DiskManager) undo(lastLSNs map[uint64]uint64, recordsByLSN map[uint64]*log_manager.LogRecord) error {
+	undoNextLSNs := make(map[uint64]uint64)
+	for txnID, lsn := range lastLSNs {
+		undoNextLSNs[txnID] = lsn
+	}
+
+	for len(undoNextLSNs) > 0 {
+		txnID, lsn := nextToUndo(undoNextLSNs)
+		rec := recordsByLSN[lsn]
+
+		nextLSN := log_manager.INVALID_LSN
+		if rec != nil {
+			nextLSN = rec.PrevLSN
+		}
+
+		switch {
+		case rec == nil || rec.Type == log_manager.RecordBegin:
+			nextLSN = log_manager.INVALID_LSN
+		case rec.Type == log_manager.RecordCompensation:
+			nextLSN = rec.UndoNextLSN
+		case rec.IsUndoable():
+			clr := compensationFor(rec)
+			clr.TxnID = txnID
+			clr.PrevLSN = lastLSNs[txnID]
+
+			clrLSN, err := dm.Log.AppendRecord(clr)
+			if err != nil {
+				return err
+			}
+
+			err = dm.redo(clr)
+			if err != nil {
+				return err
+			}
+
+			lastLSNs[txnID] = clrLSN
+		}
+
+		if nextLSN != log_manager.INVALID_LSN {
+			undoNextLSNs[txnID] = nextLSN
+			continue
+		}
+
+		_, err := dm.Log.AppendRecord(&log_manager.LogRecord{
+			TxnID:   txnID,
+			PrevLSN: lastLSNs[txnID],
+			Type:    log_manager.RecordAbort,
+		})
+		if err != nil {
+			return err
+		}
+
+		delete(undoNextLSNs, txnID)
+	}
+
+	return nil
+}
+
+func nextToUndo(undoNextLSNs map[uint64]uint64) (uint64, uint64) {
+	var nextTxnID, nextLSN uint64
+
+	for txnID, lsn := range undoNextLSNs {
+		if lsn >= nextLSN {
+			nextTxnID = txnID
+			nextLSN = lsn
+		}
+	}
+
+	return nextTxnID, nextLSN
+}
+
+func compensationFor(rec *log_manager.LogRecord) *log_manager.LogRecord {
+	clr := &log_manager.LogRecord{
+		Type:        log_manager.RecordCompensation,
+		PageID:      rec.PageID,
+		SlotID:      rec.SlotID,
+		UndoNextLSN: rec.PrevLSN,
+	}
+
+	switch rec.Type {
+	case log_manager.RecordDeleteTuple:
+		clr.Data = rec.Data
+	case log_manager.RecordUpdateTuple:
+		clr.Data, _, _ = log_manager.DecodeUpdate(rec.Data)
+	}
+
+	return clr
+}
+
+func (dm *DiskManager) redoSlottedPage(pageID uint32, lsn uint64, apply func(sp *slotted_page.SlottedPage) error) error {
+	return dm.redoPage(pageID, lsn, func(data []byte) error {
+		return apply(slotted_page.FromData(data))
+	})
+}
+
+func (dm *DiskManager) redoPage(pageID uint32, lsn uint64, apply func(data []byte) error) error {
+	return dm.applyRedo(pageID, lsn, false, apply)
+}
+
+// A record that rewrites the whole page also repairs a torn or corrupted copy on disk.
+func (dm *DiskManager) redoFullPage(pageID uint32, lsn uint64, apply func(data []byte) error) error {
+	return dm.applyRedo(pageID, lsn, true, apply)
+}
+
+func (dm *DiskManager) applyRedo(pageID uint32, lsn uint64, fullPage bool, apply func(data []byte) error) error {
+	err := dm.ensurePageExists(pageID)
+	if err != nil {
+		return err
+	}
+
+	data, err := dm.ReadPage(pageID)
+	if errors.Is(err, ErrPageCorrupted) && fullPage {
+		data, err = make([]byte, dm.PageSize), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if shared.GetPageLSN(data) >= lsn {
+		return nil
+	}
+
+	err = apply(data)
+	if err != nil {
+		return err
+	}
+
+	shared.SetPageLSN(data, lsn)
+
+	return dm.WritePage(pageID, data)
+}
@@ -0,0 +1,235 @@
+package disk_manager
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"gobase/log_manager"
+	"gobase/shared"
+	"gobase/slotted_page"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecoveryDiskManager(t *testing.T) (*DiskManager, string, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "recovery_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	dm, err := NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	cleanup := func() {
+		os.Remove(tmpFile.Name())
+		os.Remove(LogFilePath(tmpFile.Name()))
+	}
+
+	return dm, tmpFile.Name(), cleanup
+}
+
+func appendTestRecord(t *testing.T, dm *DiskManager, rec *log_manager.LogRecord) uint64 {
+	t.Helper()
+
+	lsn, err := dm.Log.AppendRecord(rec)
+	require.NoError(t, err)
+
+	return lsn
+}
+
+func readSlottedPage(t *testing.T, dm *DiskManager, pageID uint32) *slotted_page.SlottedPage {
+	t.Helper()
+
+	data, err := dm.ReadPage(pageID)
+	require.NoError(t, err)
+
+	return slotted_page.FromData(data)
+}
+
+func TestRecover_RedoLinkedPages(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: 1, PrevPageID: slotted_page.NULL_PAGE_ID})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 1, SlotID: 0, Data: []byte("first")})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: 2, PrevPageID: 1})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 2, SlotID: 0, Data: []byte("second")})
+	require.NoError(t, dm.Close())
+
+	dm, err := NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	assert.Equal(t, uint32(3), dm.NumPages)
+
+	first := readSlottedPage(t, dm, 1)
+	assert.Equal(t, uint32(2), first.GetNextPageID())
+	tuple, err := first.GetTuple(0)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Tuple("first"), tuple)
+
+	second := readSlottedPage(t, dm, 2)
+	assert.Equal(t, uint32(1), second.GetPrevPageID())
+	assert.Equal(t, slotted_page.NULL_PAGE_ID, second.GetNextPageID())
+	tuple, err = second.GetTuple(0)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Tuple("second"), tuple)
+}
+
+func TestRecover_SkipsAppliedRecords(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	_, err := dm.AllocatePage()
+	require.NoError(t, err)
+
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: 1, PrevPageID: slotted_page.NULL_PAGE_ID})
+	lsn := appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 1, SlotID: 0, Data: []byte("first")})
+
+	data := make([]byte, dm.PageSize)
+	slotted_page.InitSlottedPage(data)
+	sp := slotted_page.FromData(data)
+	_, err = sp.InsertTuple(shared.Tuple("first"))
+	require.NoError(t, err)
+	sp.SetLSN(lsn)
+	require.NoError(t, dm.WritePage(1, data))
+	require.NoError(t, dm.Close())
+
+	dm, err = NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	assert.Equal(t, uint16(1), readSlottedPage(t, dm, 1).GetNumSlots())
+}
+
+func TestRecover_UndoUncommittedTransaction(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: 1, PrevPageID: slotted_page.NULL_PAGE_ID})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 1, SlotID: 0, Data: []byte("kept")})
+
+	begin := appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordBegin, TxnID: 1})
+	insert := appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, TxnID: 1, PrevLSN: begin, PageID: 1, SlotID: 1, Data: []byte("uncommitted")})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordDeleteTuple, TxnID: 1, PrevLSN: insert, PageID: 1, SlotID: 0, Data: []byte("kept")})
+
+	committed := appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordBegin, TxnID: 2})
+	committed = appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, TxnID: 2, PrevLSN: committed, PageID: 1, SlotID: 2, Data: []byte("committed")})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordCommit, TxnID: 2, PrevLSN: committed})
+	require.NoError(t, dm.Close())
+
+	dm, err := NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	sp := readSlottedPage(t, dm, 1)
+
+	tuple, err := sp.GetTuple(0)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Tuple("kept"), tuple)
+
+	_, err = sp.GetTuple(1)
+	require.ErrorIs(t, err, slotted_page.ErrTupleHasBeenDeleted)
+
+	tuple, err = sp.GetTuple(2)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Tuple("committed"), tuple)
+}
+
+func TestRecover_UpdateTuple(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: 1, PrevPageID: slotted_page.NULL_PAGE_ID})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 1, SlotID: 0, Data: []byte("first")})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 1, SlotID: 1, Data: []byte("second")})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordUpdateTuple, PageID: 1, SlotID: 0, Data: log_manager.EncodeUpdate([]byte("first"), []byte("first, updated"))})
+
+	begin := appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordBegin, TxnID: 1})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordUpdateTuple, TxnID: 1, PrevLSN: begin, PageID: 1, SlotID: 1, Data: log_manager.EncodeUpdate([]byte("second"), []byte("uncommitted"))})
+	require.NoError(t, dm.Close())
+
+	dm, err := NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	sp := readSlottedPage(t, dm, 1)
+
+	tuple, err := sp.GetTuple(0)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Tuple("first, updated"), tuple)
+
+	tuple, err = sp.GetTuple(1)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Tuple("second"), tuple)
+}
+
+func TestRecover_TruncatesLog(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: 1, PrevPageID: slotted_page.NULL_PAGE_ID})
+	lsn := appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: 1, SlotID: 0, Data: []byte("first")})
+	require.NoError(t, dm.Close())
+
+	dm, err := NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	records, err := dm.Log.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, log_manager.RecordCheckpoint, records[0].Type)
+	assert.Greater(t, records[0].LSN, lsn)
+}
+
+func TestRecover_RepairsCorruptedPage(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+
+	image := make([]byte, dm.PageSize)
+	copy(image[shared.PAGE_HEADER_SIZE:], "page image")
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordPageImage, PageID: pageID, Data: image})
+
+	_, err = dm.File.WriteAt([]byte("torn"), calculateOffset(pageID, dm.PageSize)+int64(shared.PAGE_HEADER_SIZE))
+	require.NoError(t, err)
+	require.NoError(t, dm.Close())
+
+	dm, err = NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	data, err := dm.ReadPage(pageID)
+	require.NoError(t, err)
+	assert.Equal(t, image[shared.PAGE_HEADER_SIZE:], data[shared.PAGE_HEADER_SIZE:])
+}
+
+func TestRecover_SkipsRecordsOfFreedPage(t *testing.T) {
+	dm, filePath, cleanup := newTestRecoveryDiskManager(t)
+	defer cleanup()
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordNewPage, PageID: pageID, PrevPageID: slotted_page.NULL_PAGE_ID})
+	appendTestRecord(t, dm, &log_manager.LogRecord{Type: log_manager.RecordInsertTuple, PageID: pageID, SlotID: 0, Data: []byte("stale")})
+	require.NoError(t, dm.DeallocatePage(pageID))
+	require.NoError(t, dm.Close())
+
+	dm, err = NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	assert.Equal(t, pageID, dm.GetFreeListHead())
+
+	data, err := dm.ReadPage(pageID)
+	require.NoError(t, err)
+	assert.Equal(t, INVALID_PAGE_ID, binary.LittleEndian.Uint32(data[FREE_PAGE_NEXT_OFFSET:]))
+	assert.Equal(t, make([]byte, dm.PageSize)[FREE_PAGE_NEXT_OFFSET+4:], data[FREE_PAGE_NEXT_OFFSET+4:])
+}
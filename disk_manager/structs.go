@@ -2,17 +2,24 @@ package disk_manager
 
 import (
 	"os"
+	"sync"
 
-	"gobase/shared"
+	"gobase/log_manager"
 )
 
 type DiskManager struct {
-	File     *os.File
-	PageSize uint32
-	NumPages uint32
+	mu                sync.RWMutex
+	File              *os.File
+	PageSize          uint32
+	NumPages          uint32
+	Log               *log_manager.LogManager
+	catalogRootPageID uint32
+	freeListHead      uint32
 }
 
-func NewDiskManager(filePath string) (*DiskManager, error) {
+type Option func(*DiskManager)
+
+func NewDiskManager(filePath string, options ...Option) (*DiskManager, error) {
 	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		return nil, ErrOpenFileFailed
@@ -24,13 +31,47 @@ func NewDiskManager(filePath string) (*DiskManager, error) {
 		return nil, ErrStatFileFailed
 	}
 
-	numPages := stats.Size() / int64(shared.PAGE_SIZE)
-
 	newDiskManager := &DiskManager{
-		File:     file,
-		PageSize: shared.PAGE_SIZE,
-		NumPages: uint32(numPages),
+		File:              file,
+		catalogRootPageID: INVALID_PAGE_ID,
+		freeListHead:      INVALID_PAGE_ID,
+	}
+
+	for _, option := range options {
+		option(newDiskManager)
+	}
+
+	if stats.Size() == 0 {
+		err = newDiskManager.initHeader()
+	} else {
+		err = newDiskManager.readHeader(stats.Size())
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	logManager, err := log_manager.NewLogManager(LogFilePath(filePath))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	newDiskManager.Log = logManager
+
+	err = newDiskManager.recover()
+	if err != nil {
+		newDiskManager.Close()
+		return nil, err
 	}
 
 	return newDiskManager, nil
 }
+
+// The page size only applies when the database is created; an existing file
+// must be opened with the size recorded in its header.
+func WithPageSize(pageSize uint32) Option {
+	return func(dm *DiskManager) {
+		dm.PageSize = pageSize
+	}
+}
@@ -1,6 +1,7 @@
 package disk_manager
 
 import (
+	"encoding/binary"
 	"gobase/shared"
 	"os"
 	"testing"
@@ -14,28 +15,135 @@ func TestNewDiskManager(t *testing.T) {
 	require.NoError(t, err)
 	filePath := tmpFile.Name()
 	defer os.Remove(filePath)
+	defer os.Remove(LogFilePath(filePath))
 	tmpFile.Close()
 
 	dm, err := NewDiskManager(filePath)
 	require.NoError(t, err)
 	assert.NotNil(t, dm)
-	assert.Equal(t, uint32(0), dm.NumPages, "empty file should have 0 pages")
-	assert.Equal(t, shared.PAGE_SIZE, dm.PageSize)
+	assert.Equal(t, uint32(1), dm.NumPages, "empty file should get a header page")
+	assert.Equal(t, shared.DEFAULT_PAGE_SIZE, dm.PageSize)
+	assert.Equal(t, INVALID_PAGE_ID, dm.GetCatalogRootPageID())
 
-	require.NoError(t, dm.File.Close())
-
-	err = os.WriteFile(filePath, make([]byte, shared.PAGE_SIZE), 0644)
-	require.NoError(t, err)
+	require.NoError(t, dm.SetCatalogRootPageID(3))
+	require.NoError(t, dm.Close())
 
 	dm2, err := NewDiskManager(filePath)
 	require.NoError(t, err)
 	assert.NotNil(t, dm2)
 	assert.Equal(t, uint32(1), dm2.NumPages, "file with 1 page should have NumPages=1")
-	assert.Equal(t, shared.PAGE_SIZE, dm2.PageSize)
+	assert.Equal(t, shared.DEFAULT_PAGE_SIZE, dm2.PageSize)
+	assert.Equal(t, uint32(3), dm2.GetCatalogRootPageID())
+	assert.Equal(t, INVALID_PAGE_ID, dm2.GetFreeListHead())
 
 	info, err := dm2.File.Stat()
 	require.NoError(t, err)
-	assert.Equal(t, int64(shared.PAGE_SIZE), info.Size())
+	assert.Equal(t, int64(shared.DEFAULT_PAGE_SIZE), info.Size())
+
+	require.NoError(t, dm2.Close())
+}
+
+func TestNewDiskManager_FreeListPersists(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "disk_manager_test")
+	require.NoError(t, err)
+	filePath := tmpFile.Name()
+	defer os.Remove(filePath)
+	defer os.Remove(LogFilePath(filePath))
+	tmpFile.Close()
+
+	dm, err := NewDiskManager(filePath)
+	require.NoError(t, err)
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+	_, err = dm.AllocatePage()
+	require.NoError(t, err)
+	require.NoError(t, dm.DeallocatePage(pageID))
+	require.NoError(t, dm.Close())
+
+	dm, err = NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	assert.Equal(t, pageID, dm.GetFreeListHead())
+
+	reused, err := dm.AllocatePage()
+	require.NoError(t, err)
+	assert.Equal(t, pageID, reused)
+	assert.Equal(t, uint32(3), dm.NumPages)
+}
+
+func TestNewDiskManager_InvalidHeader(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "disk_manager_test")
+	require.NoError(t, err)
+	filePath := tmpFile.Name()
+	defer os.Remove(filePath)
+	defer os.Remove(LogFilePath(filePath))
+	tmpFile.Close()
 
-	require.NoError(t, dm2.File.Close())
-}
\ No newline at end of file
+	header := make([]byte, shared.DEFAULT_PAGE_SIZE)
+
+	require.NoError(t, os.WriteFile(filePath, header, 0644))
+	_, err = NewDiskManager(filePath)
+	require.ErrorIs(t, err, ErrNotADatabase)
+
+	copy(header[MAGIC_OFFSET:], MAGIC)
+	binary.LittleEndian.PutUint32(header[VERSION_OFFSET:], FORMAT_VERSION+1)
+	require.NoError(t, os.WriteFile(filePath, header, 0644))
+	_, err = NewDiskManager(filePath)
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	binary.LittleEndian.PutUint32(header[VERSION_OFFSET:], FORMAT_VERSION)
+	binary.LittleEndian.PutUint32(header[PAGE_SIZE_OFFSET:], shared.DEFAULT_PAGE_SIZE+1)
+	require.NoError(t, os.WriteFile(filePath, header, 0644))
+	_, err = NewDiskManager(filePath)
+	require.ErrorIs(t, err, ErrInvalidPageSize)
+
+	binary.LittleEndian.PutUint32(header[PAGE_SIZE_OFFSET:], shared.DEFAULT_PAGE_SIZE)
+	require.NoError(t, os.WriteFile(filePath, header, 0644))
+	_, err = NewDiskManager(filePath)
+	require.ErrorIs(t, err, ErrPageCorrupted)
+
+	shared.SetPageChecksum(header, shared.ComputePageChecksum(header))
+	require.NoError(t, os.WriteFile(filePath, header, 0644))
+	_, err = NewDiskManager(filePath, WithPageSize(2*shared.DEFAULT_PAGE_SIZE))
+	require.ErrorIs(t, err, ErrPageSizeMismatch)
+
+	dm, err := NewDiskManager(filePath)
+	require.NoError(t, err)
+	require.NoError(t, dm.Close())
+}
+
+func TestNewDiskManager_WithPageSize(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "disk_manager_test")
+	require.NoError(t, err)
+	filePath := tmpFile.Name()
+	defer os.Remove(filePath)
+	defer os.Remove(LogFilePath(filePath))
+	tmpFile.Close()
+
+	_, err = NewDiskManager(filePath, WithPageSize(3000))
+	require.ErrorIs(t, err, ErrInvalidPageSize)
+
+	dm, err := NewDiskManager(filePath, WithPageSize(shared.MAX_PAGE_SIZE))
+	require.NoError(t, err)
+
+	pageID, err := dm.AllocatePage()
+	require.NoError(t, err)
+
+	data := make([]byte, shared.MAX_PAGE_SIZE)
+	copy(data[shared.MAX_PAGE_SIZE-4:], "tail")
+	require.NoError(t, dm.WritePage(pageID, data))
+	require.NoError(t, dm.Close())
+
+	dm, err = NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	assert.Equal(t, shared.MAX_PAGE_SIZE, dm.PageSize)
+	assert.Equal(t, uint32(2), dm.NumPages)
+
+	read, err := dm.ReadPage(pageID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("tail"), read[shared.MAX_PAGE_SIZE-4:])
+}
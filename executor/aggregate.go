@@ -0,0 +1,218 @@
+package executor
+
+import (
+	"bytes"
+	"strings"
+
+	"gobase/catalog"
+)
+
+func (ha *HashAggregate) Init() error {
+	err := ha.child.Init()
+	if err != nil {
+		return err
+	}
+
+	rows, err := drain(ha.child)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string]*aggregateGroup)
+	order := []*aggregateGroup{}
+
+	for _, row := range rows {
+		values := make([]any, len(ha.groupIndexes))
+		for i, groupIndex := range ha.groupIndexes {
+			values[i] = row.Values[groupIndex]
+		}
+
+		encoded, err := catalog.EncodeKey(ha.child.Schema(), ha.groupIndexes, values)
+		if err != nil {
+			return err
+		}
+
+		key := string(encoded)
+		group, exists := groups[key]
+		if !exists {
+			group = &aggregateGroup{values: values, states: make([]aggregateState, len(ha.aggregates))}
+			groups[key] = group
+			order = append(order, group)
+		}
+
+		for i := range ha.aggregates {
+			err = ha.update(&group.states[i], i, row.Values)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Without GROUP BY an empty input still yields one row, as in SQL.
+	if len(order) == 0 && len(ha.groupIndexes) == 0 {
+		order = append(order, &aggregateGroup{states: make([]aggregateState, len(ha.aggregates))})
+	}
+
+	ha.rows = make([]Row, len(order))
+	for i, group := range order {
+		values := append([]any{}, group.values...)
+		for j := range ha.aggregates {
+			values = append(values, ha.result(&group.states[j], j))
+		}
+		ha.rows[i] = Row{Values: values}
+	}
+	ha.position = 0
+
+	return nil
+}
+
+func (ha *HashAggregate) Next() (Row, bool, error) {
+	if ha.position >= len(ha.rows) {
+		return Row{}, false, nil
+	}
+
+	row := ha.rows[ha.position]
+	ha.position++
+
+	return row, true, nil
+}
+
+func (ha *HashAggregate) Close() error {
+	ha.rows = nil
+	return ha.child.Close()
+}
+
+func (ha *HashAggregate) Schema() *catalog.Schema {
+	return ha.schema
+}
+
+func (ha *HashAggregate) resultColumn(i int) (catalog.Column, error) {
+	aggregate := ha.aggregates[i]
+
+	name := aggregate.Name
+	if name == "" {
+		name = strings.ToLower(aggregate.Func.String())
+		if aggregate.Column != "" {
+			name += "_" + aggregate.Column
+		}
+	}
+
+	if aggregate.Func == AggregateCount {
+		return catalog.Column{Name: name, Type: catalog.TypeBigInt}, nil
+	}
+
+	if ha.columnIndexes[i] == -1 {
+		return catalog.Column{}, ErrAggregateWithoutColumn
+	}
+	col := ha.child.Schema().Columns[ha.columnIndexes[i]]
+
+	switch aggregate.Func {
+	case AggregateSum:
+		switch col.Type {
+		case catalog.TypeInt, catalog.TypeSmallInt, catalog.TypeBigInt:
+			return catalog.Column{Name: name, Type: catalog.TypeBigInt, Nullable: true}, nil
+		case catalog.TypeDouble:
+			return catalog.Column{Name: name, Type: catalog.TypeDouble, Nullable: true}, nil
+		case catalog.TypeDecimal:
+			return catalog.Column{Name: name, Type: catalog.TypeDecimal, Precision: catalog.MAX_DECIMAL_PRECISION, Scale: col.Scale, Nullable: true}, nil
+		}
+	case AggregateAvg:
+		if isNumeric(col.Type) {
+			return catalog.Column{Name: name, Type: catalog.TypeDouble, Nullable: true}, nil
+		}
+	case AggregateMin, AggregateMax:
+		return catalog.Column{Name: name, Type: col.Type, Size: col.Size, Precision: col.Precision, Scale: col.Scale, Nullable: true}, nil
+	}
+
+	return catalog.Column{}, ErrInvalidAggregate
+}
+
+func (ha *HashAggregate) update(state *aggregateState, i int, values []any) error {
+	columnIndex := ha.columnIndexes[i]
+	if columnIndex == -1 {
+		state.count++
+		return nil
+	}
+
+	value := values[columnIndex]
+	if value == nil {
+		return nil
+	}
+	state.count++
+
+	switch ha.aggregates[i].Func {
+	case AggregateSum:
+		switch val := value.(type) {
+		case float64:
+			state.floatSum += val
+		case catalog.Decimal:
+			return addInt64(&state.intSum, val.Unscaled)
+		case int:
+			return addInt64(&state.intSum, int64(val))
+		case int64:
+			return addInt64(&state.intSum, val)
+		}
+	case AggregateAvg:
+		state.floatSum += catalog.ToFloat64(value)
+	case AggregateMin, AggregateMax:
+		key, err := encodeValue(ha.child.Schema(), columnIndex, value)
+		if err != nil {
+			return err
+		}
+		cmp := bytes.Compare(key, state.key)
+		if ha.aggregates[i].Func == AggregateMax {
+			cmp = -cmp
+		}
+		if state.key == nil || cmp < 0 {
+			state.value = value
+			state.key = key
+		}
+	}
+
+	return nil
+}
+
+func (ha *HashAggregate) result(state *aggregateState, i int) any {
+	aggregate := ha.aggregates[i]
+	if aggregate.Func == AggregateCount {
+		return state.count
+	}
+
+	if state.count == 0 {
+		return nil
+	}
+
+	switch aggregate.Func {
+	case AggregateSum:
+		switch ha.schema.Columns[len(ha.groupIndexes)+i].Type {
+		case catalog.TypeDouble:
+			return state.floatSum
+		case catalog.TypeDecimal:
+			return catalog.NewDecimal(state.intSum, ha.schema.Columns[len(ha.groupIndexes)+i].Scale)
+		}
+		return state.intSum
+	case AggregateAvg:
+		return state.floatSum / float64(state.count)
+	}
+
+	return state.value
+}
+
+func addInt64(sum *int64, value int64) error {
+	result := *sum + value
+	if value > 0 && result < *sum || value < 0 && result > *sum {
+		return ErrSumOutOfRange
+	}
+
+	*sum = result
+	return nil
+}
+
+func isNumeric(columnType catalog.ColumnType) bool {
+	switch columnType {
+	case catalog.TypeInt, catalog.TypeSmallInt, catalog.TypeBigInt, catalog.TypeDouble, catalog.TypeDecimal:
+		return true
+	}
+
+	return false
+}
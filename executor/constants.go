@@ -0,0 +1,11 @@
+package executor
+
+const (
+	HASH_JOIN_MEMORY_LIMIT = 4 << 20
+	HASH_JOIN_PARTITIONS   = 8
+
+	SPILL_PAGE_ID_COLUMN = "_rid_page"
+	SPILL_SLOT_ID_COLUMN = "_rid_slot"
+
+	SPILL_LENGTH_SIZE = 4
+)
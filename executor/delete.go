@@ -0,0 +1,42 @@
+package executor
+
+import "gobase/catalog"
+
+func (d *Delete) Init() error {
+	d.done = false
+	return d.child.Init()
+}
+
+// Next deletes every row of the child and returns the number of deleted rows.
+func (d *Delete) Next() (Row, bool, error) {
+	if d.done {
+		return Row{}, false, nil
+	}
+	d.done = true
+
+	rows, err := drain(d.child)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	for _, row := range rows {
+		if row.RID == nil {
+			return Row{}, false, ErrRowWithoutRID
+		}
+
+		err = d.table.Delete(d.txn, *row.RID)
+		if err != nil {
+			return Row{}, false, err
+		}
+	}
+
+	return Row{Values: []any{int64(len(rows))}}, true, nil
+}
+
+func (d *Delete) Close() error {
+	return d.child.Close()
+}
+
+func (d *Delete) Schema() *catalog.Schema {
+	return countSchema()
+}
@@ -0,0 +1,13 @@
+package executor
+
+import "errors"
+
+var (
+	ErrRowWithoutRID          = errors.New("row has no record id")
+	ErrInvalidAggregate       = errors.New("aggregate not supported for this column type")
+	ErrAggregateWithoutColumn = errors.New("aggregate must have a column")
+	ErrSumOutOfRange          = errors.New("sum out of range")
+
+	ErrJoinKeyCount    = errors.New("join must have the same number of keys on both sides")
+	ErrJoinKeyMismatch = errors.New("join key columns have incompatible types")
+)
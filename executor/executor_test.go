@@ -0,0 +1,289 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"gobase/catalog"
+	"gobase/disk_manager"
+	"gobase/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeqScan(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	rows, err := Collect(NewSeqScan(users))
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+
+	for _, row := range rows {
+		require.NotNil(t, row.RID)
+		values, err := users.GetByRID(*row.RID)
+		require.NoError(t, err)
+		assert.Equal(t, values, row.Values)
+	}
+	assert.Equal(t, []any{3, "Carol", "Paris", nil}, rows[2].Values)
+}
+
+func TestSeqScan_CorruptedPage(t *testing.T) {
+	columns := []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "body", Type: catalog.TypeVarchar, Size: 200},
+	}
+
+	docs, cleanup := newTestTable(t, "docs", columns)
+	defer cleanup()
+
+	var firstPageID, corruptedPageID uint32
+	for i := 0; i < 1000; i++ {
+		rid, err := docs.Insert(nil, i, strings.Repeat("x", 200))
+		require.NoError(t, err)
+
+		if i == 0 {
+			firstPageID = rid.GetPageID()
+		}
+		if corruptedPageID == 0 && rid.GetPageID() != firstPageID {
+			corruptedPageID = rid.GetPageID()
+		}
+	}
+
+	// The pool is far smaller than the table, so the page is read back from
+	// disk when the scan reaches it.
+	bpm := docs.Heap.GetBufferPoolManager()
+	require.NoError(t, bpm.FlushAllPages())
+
+	_, err := bpm.GetDiskManager().File.WriteAt([]byte("bit rot"), int64(corruptedPageID)*int64(bpm.GetPageSize())+int64(shared.PAGE_HEADER_SIZE))
+	require.NoError(t, err)
+
+	scan := NewSeqScan(docs)
+	require.NoError(t, scan.Init())
+	defer scan.Close()
+
+	count := 0
+	for {
+		_, ok, err := scan.Next()
+		if err != nil {
+			require.ErrorIs(t, err, disk_manager.ErrPageCorrupted)
+			break
+		}
+		require.True(t, ok, "scan ended without reporting the corrupted page")
+		count++
+	}
+	assert.Positive(t, count)
+}
+
+func TestIndexScan(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	require.NoError(t, users.CreateIndex("users_city", "city"))
+
+	values := collectValues(t, NewIndexScan(users, "users_pkey", []any{2}, []any{4}))
+	assert.Equal(t, [][]any{
+		{2, "Bob", "Lyon", 25},
+		{3, "Carol", "Paris", nil},
+		{4, "Dave", nil, 40},
+	}, values)
+
+	values = collectValues(t, NewIndexScan(users, "users_city", []any{"Paris"}, []any{"Paris"}))
+	assert.Equal(t, [][]any{
+		{1, "Alice", "Paris", 30},
+		{3, "Carol", "Paris", nil},
+	}, values)
+
+	values = collectValues(t, NewIndexScan(users, "users_pkey", []any{4}, nil))
+	assert.Len(t, values, 2)
+
+	_, err := Collect(NewIndexScan(users, "missing", nil, nil))
+	assert.Error(t, err)
+}
+
+func TestFilterProjectionLimit(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	filter := NewFilter(NewSeqScan(users), func(values []any) (bool, error) {
+		return values[2] == "Lyon" || values[2] == "Paris", nil
+	})
+
+	projection, err := NewProjection(filter, "name", "id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "id"}, []string{projection.Schema().Columns[0].Name, projection.Schema().Columns[1].Name})
+
+	values := collectValues(t, NewLimit(projection, 2, 1))
+	assert.Equal(t, [][]any{{"Bob", 2}, {"Carol", 3}}, values)
+
+	values = collectValues(t, NewLimit(projection, 10, 3))
+	assert.Equal(t, [][]any{{"Eve", 5}}, values)
+
+	_, err = NewProjection(NewSeqScan(users), "missing")
+	assert.Error(t, err)
+}
+
+func TestSort(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	sorted, err := NewSort(NewSeqScan(users), SortKey{Column: "city"}, SortKey{Column: "age", Desc: true})
+	require.NoError(t, err)
+	projection, err := NewProjection(sorted, "id")
+	require.NoError(t, err)
+
+	values := collectValues(t, projection)
+	assert.Equal(t, [][]any{{4}, {5}, {2}, {1}, {3}}, values)
+
+	sorted, err = NewSort(NewSeqScan(users), SortKey{Column: "age"})
+	require.NoError(t, err)
+	projection, err = NewProjection(sorted, "age")
+	require.NoError(t, err)
+
+	values = collectValues(t, projection)
+	assert.Equal(t, [][]any{{nil}, {25}, {30}, {35}, {40}}, values)
+}
+
+func TestHashAggregate(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	aggregate, err := NewHashAggregate(NewSeqScan(users), []string{"city"},
+		Aggregate{Func: AggregateCount},
+		Aggregate{Func: AggregateCount, Column: "age"},
+		Aggregate{Func: AggregateSum, Column: "age"},
+		Aggregate{Func: AggregateMin, Column: "name", Name: "first"},
+		Aggregate{Func: AggregateAvg, Column: "age"},
+	)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, column := range aggregate.Schema().Columns {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{"city", "count", "count_age", "sum_age", "first", "avg_age"}, names)
+	assert.Equal(t, catalog.TypeBigInt, aggregate.Schema().Columns[3].Type)
+
+	values := collectValues(t, aggregate)
+	assert.Equal(t, [][]any{
+		{"Paris", int64(2), int64(1), int64(30), "Alice", 30.0},
+		{"Lyon", int64(2), int64(2), int64(60), "Bob", 30.0},
+		{nil, int64(1), int64(1), int64(40), "Dave", 40.0},
+	}, values)
+}
+
+func TestHashAggregate_WithoutGroupBy(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	empty := NewFilter(NewSeqScan(users), func(values []any) (bool, error) {
+		return false, nil
+	})
+
+	aggregate, err := NewHashAggregate(empty, nil,
+		Aggregate{Func: AggregateCount},
+		Aggregate{Func: AggregateMax, Column: "age"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{int64(0), nil}}, collectValues(t, aggregate))
+
+	aggregate, err = NewHashAggregate(NewSeqScan(users), nil,
+		Aggregate{Func: AggregateCount},
+		Aggregate{Func: AggregateMax, Column: "age"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{int64(5), 40}}, collectValues(t, aggregate))
+}
+
+func TestHashAggregate_Decimal(t *testing.T) {
+	columns := []catalog.Column{
+		{Name: "price", Type: catalog.TypeDecimal, Precision: 6, Scale: 2},
+	}
+	prices, cleanup := newTestTable(t, "prices", columns,
+		[]any{catalog.NewDecimal(1050, 2)},
+		[]any{catalog.NewDecimal(225, 2)},
+	)
+	defer cleanup()
+
+	aggregate, err := NewHashAggregate(NewSeqScan(prices), nil,
+		Aggregate{Func: AggregateSum, Column: "price"},
+		Aggregate{Func: AggregateAvg, Column: "price"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{catalog.NewDecimal(1275, 2), 6.375}}, collectValues(t, aggregate))
+}
+
+func TestHashAggregate_Errors(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	_, err := NewHashAggregate(NewSeqScan(users), nil, Aggregate{Func: AggregateSum, Column: "name"})
+	assert.ErrorIs(t, err, ErrInvalidAggregate)
+
+	_, err = NewHashAggregate(NewSeqScan(users), nil, Aggregate{Func: AggregateMax})
+	assert.ErrorIs(t, err, ErrAggregateWithoutColumn)
+
+	_, err = NewHashAggregate(NewSeqScan(users), []string{"missing"})
+	assert.Error(t, err)
+
+	schema := catalog.NewSchema([]catalog.Column{{Name: "n", Type: catalog.TypeBigInt}})
+	values := NewValues(schema, []any{int64(1) << 62}, []any{int64(1) << 62})
+	aggregate, err := NewHashAggregate(values, nil, Aggregate{Func: AggregateSum, Column: "n"})
+	require.NoError(t, err)
+
+	_, err = Collect(aggregate)
+	assert.ErrorIs(t, err, ErrSumOutOfRange)
+}
+
+func TestInsertAndDelete(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	insert := NewInsert(nil, users, NewValues(users.Schema,
+		[]any{6, "Frank", "Nice", 50},
+		[]any{7, "Grace", nil, nil},
+	))
+	assert.Equal(t, [][]any{{int64(2)}}, collectValues(t, insert))
+
+	rows, err := users.LookupByIndex("users_pkey", 7)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{7, "Grace", nil, nil}}, rows)
+
+	duplicate := NewInsert(nil, users, NewValues(users.Schema, []any{1, "Again", nil, nil}))
+	_, err = Collect(duplicate)
+	assert.Error(t, err)
+
+	noCity := NewFilter(NewSeqScan(users), func(values []any) (bool, error) {
+		return values[2] == nil, nil
+	})
+	assert.Equal(t, [][]any{{int64(2)}}, collectValues(t, NewDelete(nil, users, noCity)))
+
+	values := collectValues(t, NewSeqScan(users))
+	assert.Len(t, values, 5)
+	for _, row := range values {
+		assert.NotNil(t, row[2])
+	}
+
+	_, err = Collect(NewDelete(nil, users, NewValues(users.Schema, []any{1, "Alice", nil, nil})))
+	assert.ErrorIs(t, err, ErrRowWithoutRID)
+}
+
+func TestInsert_FromOwnScan(t *testing.T) {
+	users, cleanup := newTestUsers(t)
+	defer cleanup()
+
+	projection, err := NewProjection(NewSeqScan(users), "name")
+	require.NoError(t, err)
+
+	columns := []catalog.Column{{Name: "name", Type: catalog.TypeVarchar, Size: 50}}
+	names, cleanupNames := newTestTable(t, "names", columns)
+	defer cleanupNames()
+
+	insert := NewInsert(nil, names, projection)
+	assert.Equal(t, [][]any{{int64(5)}}, collectValues(t, insert))
+
+	insert = NewInsert(nil, names, NewSeqScan(names))
+	assert.Equal(t, [][]any{{int64(5)}}, collectValues(t, insert))
+	assert.Len(t, collectValues(t, NewSeqScan(names)), 10)
+}
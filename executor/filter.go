@@ -0,0 +1,33 @@
+package executor
+
+import "gobase/catalog"
+
+func (f *Filter) Init() error {
+	return f.child.Init()
+}
+
+func (f *Filter) Next() (Row, bool, error) {
+	for {
+		row, ok, err := f.child.Next()
+		if err != nil || !ok {
+			return Row{}, false, err
+		}
+
+		keep, err := f.predicate(row.Values)
+		if err != nil {
+			return Row{}, false, err
+		}
+
+		if keep {
+			return row, true, nil
+		}
+	}
+}
+
+func (f *Filter) Close() error {
+	return f.child.Close()
+}
+
+func (f *Filter) Schema() *catalog.Schema {
+	return f.child.Schema()
+}
@@ -0,0 +1,286 @@
+package executor
+
+import (
+	"errors"
+
+	"gobase/catalog"
+)
+
+func (hj *HashJoin) Init() error {
+	err := hj.dropPartitions()
+	if err != nil {
+		return err
+	}
+
+	hj.buckets = make(map[string][]Row)
+	hj.hasLeft = false
+	hj.matches = nil
+	hj.spilled = false
+
+	err = hj.right.Init()
+	if err != nil {
+		return err
+	}
+
+	size := 0
+	for {
+		row, ok, err := hj.right.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		key, ok, err := hj.keys.rightKey(row.Values)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if hj.spilled {
+			err = spillRow(hj.build[partitionOf(key)], hj.rightSpill, row)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		hj.buckets[string(key)] = append(hj.buckets[string(key)], row)
+
+		tuple, err := encodeSpillRow(hj.rightSpill, row)
+		if err != nil {
+			return err
+		}
+		size += len(tuple)
+
+		if size > hj.memoryLimit {
+			err = hj.spill()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = hj.left.Init()
+	if err != nil {
+		return err
+	}
+
+	if hj.spilled {
+		return hj.partitionProbe()
+	}
+
+	return nil
+}
+
+func (hj *HashJoin) Next() (Row, bool, error) {
+	for {
+		for hj.position < len(hj.matches) {
+			rightRow := hj.matches[hj.position]
+			hj.position++
+
+			values := joinValues(hj.leftRow.Values, rightRow.Values, len(rightRow.Values))
+			match, err := joinMatches(hj.predicate, values)
+			if err != nil {
+				return Row{}, false, err
+			}
+			if !match {
+				continue
+			}
+
+			hj.matched = true
+			if hj.joinType == JoinSemi {
+				hj.matches = nil
+				return hj.leftRow, true, nil
+			}
+
+			return Row{Values: values}, true, nil
+		}
+
+		if hj.hasLeft {
+			hj.hasLeft = false
+			if hj.joinType == JoinLeft && !hj.matched {
+				return Row{Values: joinValues(hj.leftRow.Values, nil, len(hj.right.Schema().Columns))}, true, nil
+			}
+		}
+
+		row, ok, err := hj.nextProbe()
+		if err != nil || !ok {
+			return Row{}, false, err
+		}
+
+		hj.leftRow = row
+		hj.hasLeft = true
+		hj.matched = false
+		hj.matches = nil
+		hj.position = 0
+
+		key, ok, err := hj.keys.leftKey(row.Values)
+		if err != nil {
+			return Row{}, false, err
+		}
+		if ok {
+			hj.matches = hj.buckets[string(key)]
+		}
+	}
+}
+
+func (hj *HashJoin) Close() error {
+	hj.buckets = nil
+	hj.matches = nil
+
+	err := hj.dropPartitions()
+	if err != nil {
+		hj.left.Close()
+		hj.right.Close()
+		return err
+	}
+
+	err = hj.left.Close()
+	if err != nil {
+		hj.right.Close()
+		return err
+	}
+
+	return hj.right.Close()
+}
+
+func (hj *HashJoin) Schema() *catalog.Schema {
+	return hj.schema
+}
+
+// spill moves the in-memory build rows to temporary partitions; the rest of
+// the build side is then written straight to its partition.
+func (hj *HashJoin) spill() error {
+	hj.build = hj.newPartitions()
+	hj.spilled = true
+
+	for key, rows := range hj.buckets {
+		for _, row := range rows {
+			err := spillRow(hj.build[partitionOf([]byte(key))], hj.rightSpill, row)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	hj.buckets = make(map[string][]Row)
+	return nil
+}
+
+// Left rows with a NULL key cannot match any partition, so they go to the
+// first one to still be returned by a left join.
+func (hj *HashJoin) partitionProbe() error {
+	hj.probe = hj.newPartitions()
+
+	for {
+		row, ok, err := hj.left.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		partition := 0
+		key, ok, err := hj.keys.leftKey(row.Values)
+		if err != nil {
+			return err
+		}
+		if ok {
+			partition = partitionOf(key)
+		}
+
+		err = spillRow(hj.probe[partition], hj.leftSpill, row)
+		if err != nil {
+			return err
+		}
+	}
+
+	hj.partition = -1
+	hj.probeIter = nil
+	return nil
+}
+
+func (hj *HashJoin) nextProbe() (Row, bool, error) {
+	if !hj.spilled {
+		return hj.left.Next()
+	}
+
+	for {
+		if hj.probeIter != nil {
+			tuple, ok, err := hj.probeIter.next()
+			if err != nil {
+				return Row{}, false, err
+			}
+			if ok {
+				return unspillRow(hj.leftSpill, tuple), true, nil
+			}
+		}
+
+		hj.partition++
+		if hj.partition >= len(hj.probe) {
+			hj.probeIter = nil
+			return Row{}, false, nil
+		}
+
+		err := hj.loadPartition(hj.partition)
+		if err != nil {
+			return Row{}, false, err
+		}
+	}
+}
+
+// A partition is loaded whole even if it still exceeds the memory limit.
+func (hj *HashJoin) loadPartition(partition int) error {
+	hj.buckets = make(map[string][]Row)
+
+	iter := hj.build[partition].scan()
+	for {
+		tuple, ok, err := iter.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		row := unspillRow(hj.rightSpill, tuple)
+		key, _, err := hj.keys.rightKey(row.Values)
+		if err != nil {
+			return err
+		}
+		hj.buckets[string(key)] = append(hj.buckets[string(key)], row)
+	}
+
+	hj.probeIter = hj.probe[partition].scan()
+	return nil
+}
+
+func (hj *HashJoin) newPartitions() []*spillPartition {
+	partitions := make([]*spillPartition, HASH_JOIN_PARTITIONS)
+	for i := range partitions {
+		partitions[i] = newSpillPartition(hj.bpm)
+	}
+
+	return partitions
+}
+
+func (hj *HashJoin) dropPartitions() error {
+	partitions := append(hj.build, hj.probe...)
+	hj.build = nil
+	hj.probe = nil
+	hj.probeIter = nil
+
+	var errs []error
+	for _, partition := range partitions {
+		if partition != nil {
+			errs = append(errs, partition.remove())
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"gobase/catalog"
+	"gobase/table"
+)
+
+func Collect(e Executor) ([]Row, error) {
+	err := e.Init()
+	if err != nil {
+		e.Close()
+		return nil, err
+	}
+
+	rows, err := drain(e)
+	if err != nil {
+		e.Close()
+		return nil, err
+	}
+
+	return rows, e.Close()
+}
+
+func drain(e Executor) ([]Row, error) {
+	rows := []Row{}
+	for {
+		row, ok, err := e.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return rows, nil
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func encodeColumns(schema *catalog.Schema, columnIndexes []int, values []any) ([][]byte, error) {
+	keys := make([][]byte, len(columnIndexes))
+	for i, columnIndex := range columnIndexes {
+		key, err := encodeValue(schema, columnIndex, values[columnIndex])
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+func encodeValue(schema *catalog.Schema, columnIndex int, value any) ([]byte, error) {
+	return catalog.EncodeKey(schema, []int{columnIndex}, []any{value})
+}
+
+// Scans qualify their columns with the table name, so predicates over a join
+// can tell apart columns of the same name.
+func tableSchema(t *table.Table) *catalog.Schema {
+	columns := make([]catalog.Column, len(t.Schema.Columns))
+	copy(columns, t.Schema.Columns)
+	for i := range columns {
+		columns[i].Table = t.Name
+	}
+
+	return catalog.NewSchema(columns)
+}
+
+func countSchema() *catalog.Schema {
+	return catalog.NewSchema([]catalog.Column{{Name: "count", Type: catalog.TypeBigInt}})
+}
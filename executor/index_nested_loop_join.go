@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+
+	"gobase/catalog"
+)
+
+func (j *IndexNestedLoopJoin) Init() error {
+	j.hasLeft = false
+	j.scanner = nil
+	return j.left.Init()
+}
+
+func (j *IndexNestedLoopJoin) Next() (Row, bool, error) {
+	for {
+		if !j.hasLeft {
+			row, ok, err := j.left.Next()
+			if err != nil || !ok {
+				return Row{}, false, err
+			}
+
+			j.leftRow = row
+			j.hasLeft = true
+			j.matched = false
+
+			err = j.openScanner()
+			if err != nil {
+				return Row{}, false, err
+			}
+		}
+
+		var rightValues []any
+		ok := false
+		if j.scanner != nil {
+			var err error
+			_, rightValues, ok, err = j.scanner.Next()
+			if err != nil {
+				return Row{}, false, err
+			}
+		}
+
+		if !ok {
+			j.hasLeft = false
+			if j.joinType == JoinLeft && !j.matched {
+				return Row{Values: joinValues(j.leftRow.Values, nil, len(j.right.Schema.Columns))}, true, nil
+			}
+			continue
+		}
+
+		values := joinValues(j.leftRow.Values, rightValues, len(rightValues))
+		match, err := joinMatches(j.predicate, values)
+		if err != nil {
+			return Row{}, false, err
+		}
+		if !match {
+			continue
+		}
+
+		j.matched = true
+		if j.joinType == JoinSemi {
+			j.hasLeft = false
+			return j.leftRow, true, nil
+		}
+
+		return Row{Values: values}, true, nil
+	}
+}
+
+func (j *IndexNestedLoopJoin) Close() error {
+	j.scanner = nil
+	return j.left.Close()
+}
+
+func (j *IndexNestedLoopJoin) Schema() *catalog.Schema {
+	return j.schema
+}
+
+// A left row with a NULL key has no scanner, since NULL never matches, and
+// neither has one whose key the index columns cannot hold.
+func (j *IndexNestedLoopJoin) openScanner() error {
+	j.scanner = nil
+
+	key := make([]any, len(j.leftIndexes))
+	for i, leftIndex := range j.leftIndexes {
+		value, ok, err := j.probeValue(i, j.leftRow.Values[leftIndex])
+		if err != nil || !ok {
+			return err
+		}
+		key[i] = value
+	}
+
+	scanner, err := j.right.ScanIndex(j.index, key, key)
+	if err != nil {
+		return err
+	}
+
+	j.scanner = scanner
+	return nil
+}
+
+// The probe must match the same right rows as the other joins, which compare
+// both sides in the common key type: a value the index column would round or
+// reject cannot equal any of its values.
+func (j *IndexNestedLoopJoin) probeValue(i int, value any) (any, bool, error) {
+	if value == nil {
+		return nil, false, nil
+	}
+
+	column := j.right.Schema.Columns[j.rightIndexes[i]]
+
+	// Integer keys may come from a wider column than the indexed one.
+	val, isBigInt := value.(int64)
+	if isBigInt && column.Type != catalog.TypeBigInt {
+		value = int(val)
+	}
+
+	converted, err := column.Convert(value)
+	if errors.Is(err, catalog.ErrValueOutOfRange) || errors.Is(err, catalog.ErrValueTooLong) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	original, err := catalog.EncodeKey(j.keySchema, []int{i}, []any{value})
+	if err != nil {
+		return nil, false, err
+	}
+
+	probe, err := catalog.EncodeKey(j.keySchema, []int{i}, []any{converted})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return converted, bytes.Equal(original, probe), nil
+}
@@ -0,0 +1,31 @@
+package executor
+
+import "gobase/catalog"
+
+func (s *IndexScan) Init() error {
+	scanner, err := s.table.ScanIndex(s.index, s.low, s.high)
+	if err != nil {
+		return err
+	}
+
+	s.scanner = scanner
+	return nil
+}
+
+func (s *IndexScan) Next() (Row, bool, error) {
+	rid, values, ok, err := s.scanner.Next()
+	if err != nil || !ok {
+		return Row{}, false, err
+	}
+
+	return Row{Values: values, RID: rid}, true, nil
+}
+
+func (s *IndexScan) Close() error {
+	s.scanner = nil
+	return nil
+}
+
+func (s *IndexScan) Schema() *catalog.Schema {
+	return s.schema
+}
@@ -0,0 +1,40 @@
+package executor
+
+import "gobase/catalog"
+
+func (i *Insert) Init() error {
+	i.done = false
+	return i.child.Init()
+}
+
+// Next inserts every row of the child and returns the number of inserted
+// rows. The child is drained first so that inserting into the scanned table
+// cannot feed the scan.
+func (i *Insert) Next() (Row, bool, error) {
+	if i.done {
+		return Row{}, false, nil
+	}
+	i.done = true
+
+	rows, err := drain(i.child)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	for _, row := range rows {
+		_, err = i.table.Insert(i.txn, row.Values...)
+		if err != nil {
+			return Row{}, false, err
+		}
+	}
+
+	return Row{Values: []any{int64(len(rows))}}, true, nil
+}
+
+func (i *Insert) Close() error {
+	return i.child.Close()
+}
+
+func (i *Insert) Schema() *catalog.Schema {
+	return countSchema()
+}
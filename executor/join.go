@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"hash/fnv"
+
+	"gobase/catalog"
+	"gobase/shared"
+	"gobase/table_heap"
+)
+
+// Join keys are compared through their index key encoding, so both sides are
+// encoded with a common key schema: integer columns are widened to BIGINT and
+// decimals to the larger scale.
+func newJoinKeys(leftSchema *catalog.Schema, leftKeys []string, rightSchema *catalog.Schema, rightKeys []string) (*joinKeys, error) {
+	if len(leftKeys) == 0 || len(leftKeys) != len(rightKeys) {
+		return nil, ErrJoinKeyCount
+	}
+
+	leftIndexes, err := leftSchema.GetColumnIndexes(leftKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	rightIndexes, err := rightSchema.GetColumnIndexes(rightKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]int, len(leftIndexes))
+	columns := make([]catalog.Column, len(leftIndexes))
+	for i := range leftIndexes {
+		column, ok := keyColumn(leftSchema.Columns[leftIndexes[i]], rightSchema.Columns[rightIndexes[i]])
+		if !ok {
+			return nil, ErrJoinKeyMismatch
+		}
+
+		positions[i] = i
+		columns[i] = column
+	}
+
+	return &joinKeys{
+		leftIndexes:  leftIndexes,
+		rightIndexes: rightIndexes,
+		positions:    positions,
+		schema:       catalog.NewSchema(columns),
+	}, nil
+}
+
+func keyColumn(left catalog.Column, right catalog.Column) (catalog.Column, bool) {
+	if isInteger(left.Type) && isInteger(right.Type) {
+		return catalog.Column{Name: left.Name, Type: catalog.TypeBigInt}, true
+	}
+
+	if left.Type != right.Type {
+		return catalog.Column{}, false
+	}
+
+	if left.Type == catalog.TypeDecimal {
+		return catalog.Column{Name: left.Name, Type: catalog.TypeDecimal, Precision: catalog.MAX_DECIMAL_PRECISION, Scale: max(left.Scale, right.Scale)}, true
+	}
+
+	return catalog.Column{Name: left.Name, Type: left.Type}, true
+}
+
+func (k *joinKeys) leftKey(values []any) ([]byte, bool, error) {
+	return k.encode(values, k.leftIndexes)
+}
+
+func (k *joinKeys) rightKey(values []any) ([]byte, bool, error) {
+	return k.encode(values, k.rightIndexes)
+}
+
+// A key containing NULL never matches, so it is reported as missing.
+func (k *joinKeys) encode(values []any, columnIndexes []int) ([]byte, bool, error) {
+	keyValues := make([]any, len(columnIndexes))
+	for i, columnIndex := range columnIndexes {
+		if values[columnIndex] == nil {
+			return nil, false, nil
+		}
+		keyValues[i] = values[columnIndex]
+	}
+
+	key, err := catalog.EncodeKey(k.schema, k.positions, keyValues)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return key, true, nil
+}
+
+func joinSchema(left *catalog.Schema, right *catalog.Schema, joinType JoinType) *catalog.Schema {
+	if joinType == JoinSemi {
+		return left
+	}
+
+	columns := make([]catalog.Column, 0, len(left.Columns)+len(right.Columns))
+	columns = append(columns, left.Columns...)
+	for _, column := range right.Columns {
+		if joinType == JoinLeft {
+			column.Nullable = true
+		}
+		columns = append(columns, column)
+	}
+
+	return catalog.NewSchema(columns)
+}
+
+// A nil right row pads the left row with NULLs.
+func joinValues(left []any, right []any, rightWidth int) []any {
+	values := make([]any, len(left)+rightWidth)
+	copy(values, left)
+	copy(values[len(left):], right)
+
+	return values
+}
+
+func joinMatches(predicate Predicate, values []any) (bool, error) {
+	if predicate == nil {
+		return true, nil
+	}
+
+	return predicate(values)
+}
+
+func partitionOf(key []byte) int {
+	hash := fnv.New32a()
+	hash.Write(key)
+
+	return int(hash.Sum32() % HASH_JOIN_PARTITIONS)
+}
+
+// Spilled rows keep their RID in two extra columns, so a semi join still
+// returns rows that can be deleted or updated. Every column accepts NULL:
+// intermediate rows are stored as they are, not checked against the table.
+func spillSchema(schema *catalog.Schema) *catalog.Schema {
+	columns := append([]catalog.Column{}, schema.Columns...)
+	for i := range columns {
+		columns[i].Nullable = true
+		columns[i].PrimaryKey = false
+	}
+	columns = append(columns,
+		catalog.Column{Name: SPILL_PAGE_ID_COLUMN, Type: catalog.TypeBigInt, Nullable: true},
+		catalog.Column{Name: SPILL_SLOT_ID_COLUMN, Type: catalog.TypeInt, Nullable: true},
+	)
+
+	return catalog.NewSchema(columns)
+}
+
+func spillRow(partition *spillPartition, schema *catalog.Schema, row Row) error {
+	tuple, err := encodeSpillRow(schema, row)
+	if err != nil {
+		return err
+	}
+
+	return partition.append(tuple)
+}
+
+func encodeSpillRow(schema *catalog.Schema, row Row) (shared.Tuple, error) {
+	values := append([]any{}, row.Values...)
+	if row.RID != nil {
+		values = append(values, int64(row.RID.GetPageID()), int(row.RID.GetSlotID()))
+	} else {
+		values = append(values, nil, nil)
+	}
+
+	return catalog.EncodeTuple(schema, values)
+}
+
+func unspillRow(schema *catalog.Schema, tuple []byte) Row {
+	values := catalog.DecodeTuple(schema, tuple)
+	width := len(values) - 2
+
+	row := Row{Values: values[:width]}
+	if values[width] != nil {
+		row.RID = table_heap.NewRID(uint32(values[width].(int64)), uint16(values[width+1].(int)))
+	}
+
+	return row
+}
+
+func isInteger(columnType catalog.ColumnType) bool {
+	switch columnType {
+	case catalog.TypeInt, catalog.TypeSmallInt, catalog.TypeBigInt:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,340 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"gobase/catalog"
+	"gobase/disk_manager"
+	"gobase/table"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrders(t *testing.T) (*table.Table, func()) {
+	t.Helper()
+
+	columns := []catalog.Column{
+		{Name: "order_id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "user_id", Type: catalog.TypeBigInt, Nullable: true},
+		{Name: "amount", Type: catalog.TypeInt},
+	}
+
+	orders, cleanup := newTestTable(t, "orders", columns,
+		[]any{10, int64(1), 100},
+		[]any{11, int64(1), 50},
+		[]any{12, int64(2), 70},
+		[]any{13, int64(9), 20},
+		[]any{14, nil, 5},
+	)
+	require.NoError(t, orders.CreateIndex("orders_user", "user_id"))
+
+	return orders, cleanup
+}
+
+func sortedValues(t *testing.T, e Executor) []string {
+	t.Helper()
+
+	rows := []string{}
+	for _, values := range collectValues(t, e) {
+		rows = append(rows, fmt.Sprint(values))
+	}
+	sort.Strings(rows)
+
+	return rows
+}
+
+func TestJoins(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+	spillPool, cleanupPool := newTestBufferPool(t, 8)
+	defer cleanupPool()
+
+	sameUser := func(values []any) (bool, error) {
+		return values[5] != nil && int64(values[0].(int)) == values[5].(int64), nil
+	}
+
+	joins := map[string]func(joinType JoinType) (Executor, error){
+		"nested loop": func(joinType JoinType) (Executor, error) {
+			return NewNestedLoopJoin(NewSeqScan(users), NewSeqScan(orders), joinType, sameUser), nil
+		},
+		"index nested loop": func(joinType JoinType) (Executor, error) {
+			return NewIndexNestedLoopJoin(NewSeqScan(users), orders, "orders_user", []string{"id"}, joinType, nil)
+		},
+		"hash": func(joinType JoinType) (Executor, error) {
+			return NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, joinType, nil, users.Heap.GetBufferPoolManager())
+		},
+		"spilled hash": func(joinType JoinType) (Executor, error) {
+			return NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, joinType, nil, spillPool, WithMemoryLimit(16))
+		},
+		"sort merge": func(joinType JoinType) (Executor, error) {
+			return NewSortMergeJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, joinType, nil)
+		},
+	}
+
+	expected := map[JoinType][]string{
+		JoinInner: {
+			"[1 Alice Paris 30 10 1 100]",
+			"[1 Alice Paris 30 11 1 50]",
+			"[2 Bob Lyon 25 12 2 70]",
+		},
+		JoinLeft: {
+			"[1 Alice Paris 30 10 1 100]",
+			"[1 Alice Paris 30 11 1 50]",
+			"[2 Bob Lyon 25 12 2 70]",
+			"[3 Carol Paris <nil> <nil> <nil> <nil>]",
+			"[4 Dave <nil> 40 <nil> <nil> <nil>]",
+			"[5 Eve Lyon 35 <nil> <nil> <nil>]",
+		},
+		JoinSemi: {
+			"[1 Alice Paris 30]",
+			"[2 Bob Lyon 25]",
+		},
+	}
+
+	for name, newJoin := range joins {
+		for joinType, rows := range expected {
+			join, err := newJoin(joinType)
+			require.NoError(t, err, name)
+			assert.Equal(t, rows, sortedValues(t, join), "%s %v join", name, joinType)
+
+			// A second run must restart the join from scratch.
+			assert.Equal(t, rows, sortedValues(t, join), "%s %v join rerun", name, joinType)
+		}
+	}
+}
+
+func TestJoins_Predicate(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+
+	bigOrder := func(values []any) (bool, error) {
+		return values[6].(int) >= 70, nil
+	}
+
+	hash, err := NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, JoinLeft, bigOrder, users.Heap.GetBufferPoolManager())
+	require.NoError(t, err)
+	merge, err := NewSortMergeJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, JoinLeft, bigOrder)
+	require.NoError(t, err)
+	index, err := NewIndexNestedLoopJoin(NewSeqScan(users), orders, "orders_user", []string{"id"}, JoinLeft, bigOrder)
+	require.NoError(t, err)
+
+	expected := []string{
+		"[1 Alice Paris 30 10 1 100]",
+		"[2 Bob Lyon 25 12 2 70]",
+		"[3 Carol Paris <nil> <nil> <nil> <nil>]",
+		"[4 Dave <nil> 40 <nil> <nil> <nil>]",
+		"[5 Eve Lyon 35 <nil> <nil> <nil>]",
+	}
+	for _, join := range []Executor{hash, merge, index} {
+		assert.Equal(t, expected, sortedValues(t, join))
+	}
+}
+
+func TestJoins_Schema(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+
+	join := NewNestedLoopJoin(NewSeqScan(users), NewSeqScan(orders), JoinLeft, nil)
+	require.Len(t, join.Schema().Columns, 7)
+	assert.Equal(t, "order_id", join.Schema().Columns[4].Name)
+	assert.True(t, join.Schema().Columns[4].Nullable)
+	assert.Equal(t, "users", join.Schema().Columns[0].Table)
+	assert.Equal(t, "orders", join.Schema().Columns[4].Table)
+	assert.Len(t, collectValues(t, join), 25)
+
+	semi := NewNestedLoopJoin(NewSeqScan(users), NewSeqScan(orders), JoinSemi, nil)
+	assert.Equal(t, NewSeqScan(users).Schema(), semi.Schema())
+
+	// Semi joins return the left rows themselves, RIDs included.
+	rows, err := Collect(semi)
+	require.NoError(t, err)
+	for _, row := range rows {
+		assert.NotNil(t, row.RID)
+	}
+}
+
+func TestHashJoin_SpillKeepsRIDs(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+	spillPool, cleanupPool := newTestBufferPool(t, 8)
+	defer cleanupPool()
+
+	join, err := NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, JoinSemi, nil, spillPool, WithMemoryLimit(1))
+	require.NoError(t, err)
+
+	deleted := collectValues(t, NewDelete(nil, users, join))
+	assert.Equal(t, [][]any{{int64(2)}}, deleted)
+
+	remaining := sortedValues(t, NewSeqScan(users))
+	assert.Equal(t, []string{"[3 Carol Paris <nil>]", "[4 Dave <nil> 40]", "[5 Eve Lyon 35]"}, remaining)
+}
+
+func TestIndexNestedLoopJoin_WidensKeys(t *testing.T) {
+	left, cleanupLeft := newTestTable(t, "l", []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "amount", Type: catalog.TypeDecimal, Precision: 10, Scale: 3},
+		{Name: "n", Type: catalog.TypeInt},
+	},
+		[]any{1, catalog.NewDecimal(1234, 3), 40000},
+		[]any{2, catalog.NewDecimal(1230, 3), 7},
+	)
+	defer cleanupLeft()
+
+	right, cleanupRight := newTestTable(t, "r", []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "amount", Type: catalog.TypeDecimal, Precision: 10, Scale: 2},
+		{Name: "s", Type: catalog.TypeSmallInt},
+	},
+		[]any{1, catalog.NewDecimal(123, 2), 7},
+	)
+	defer cleanupRight()
+	require.NoError(t, right.CreateIndex("r_amount", "amount"))
+	require.NoError(t, right.CreateIndex("r_s", "s"))
+
+	tests := []struct {
+		name     string
+		leftKey  string
+		rightKey string
+		index    string
+	}{
+		{name: "decimal scale", leftKey: "amount", rightKey: "amount", index: "r_amount"},
+		{name: "integer range", leftKey: "n", rightKey: "s", index: "r_s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, joinType := range []JoinType{JoinInner, JoinLeft} {
+				hash, err := NewHashJoin(NewSeqScan(left), NewSeqScan(right), []string{tt.leftKey}, []string{tt.rightKey}, joinType, nil, left.Heap.GetBufferPoolManager())
+				require.NoError(t, err)
+				index, err := NewIndexNestedLoopJoin(NewSeqScan(left), right, tt.index, []string{tt.leftKey}, joinType, nil)
+				require.NoError(t, err)
+
+				assert.Equal(t, sortedValues(t, hash), sortedValues(t, index))
+			}
+		})
+	}
+}
+
+func TestJoins_Errors(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+
+	_, err := NewSortMergeJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, nil, JoinInner, nil)
+	assert.ErrorIs(t, err, ErrJoinKeyCount)
+
+	_, err = NewSortMergeJoin(NewSeqScan(users), NewSeqScan(orders), []string{"name"}, []string{"user_id"}, JoinInner, nil)
+	assert.ErrorIs(t, err, ErrJoinKeyMismatch)
+
+	_, err = NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"missing"}, []string{"user_id"}, JoinInner, nil, users.Heap.GetBufferPoolManager())
+	assert.Error(t, err)
+
+	_, err = NewIndexNestedLoopJoin(NewSeqScan(users), orders, "missing", []string{"id"}, JoinInner, nil)
+	assert.ErrorIs(t, err, table.ErrIndexNotFound)
+
+	_, err = NewIndexNestedLoopJoin(NewSeqScan(users), orders, "orders_user", []string{"id", "age"}, JoinInner, nil)
+	assert.ErrorIs(t, err, ErrJoinKeyCount)
+}
+
+func TestHashJoin_SpillsToBufferPoolPages(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+	bpm, cleanupPool := newTestBufferPool(t, 8)
+	defer cleanupPool()
+
+	dm := users.Heap.GetBufferPoolManager().GetDiskManager()
+	numPages := dm.NumPages
+
+	join, err := NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, JoinInner, nil, bpm, WithMemoryLimit(1))
+	require.NoError(t, err)
+
+	for run := 0; run < 2; run++ {
+		require.NoError(t, join.Init())
+
+		pages := 0
+		for _, partition := range append(join.build, join.probe...) {
+			pages += len(partition.pageIDs)
+		}
+		assert.Positive(t, pages)
+
+		count := 0
+		for {
+			_, ok, err := join.Next()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			count++
+		}
+		assert.Equal(t, 3, count)
+		require.NoError(t, join.Close())
+	}
+
+	// Spill pages freed by Close are reused by the next run.
+	spillPages := bpm.GetDiskManager().NumPages
+	require.NoError(t, join.Init())
+	require.NoError(t, join.Close())
+	assert.Equal(t, spillPages, bpm.GetDiskManager().NumPages)
+	assert.Equal(t, numPages, dm.NumPages)
+}
+
+func TestHashJoin_SpillReadError(t *testing.T) {
+	users, cleanupUsers := newTestUsers(t)
+	defer cleanupUsers()
+	orders, cleanupOrders := newTestOrders(t)
+	defer cleanupOrders()
+
+	tests := []struct {
+		name      string
+		corrupted func(join *HashJoin) []*spillPartition
+	}{
+		{name: "build partition", corrupted: func(join *HashJoin) []*spillPartition { return join.build }},
+		{name: "probe partition", corrupted: func(join *HashJoin) []*spillPartition { return join.probe }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bpm, cleanupPool := newTestBufferPool(t, 1)
+			defer cleanupPool()
+
+			join, err := NewHashJoin(NewSeqScan(users), NewSeqScan(orders), []string{"id"}, []string{"user_id"}, JoinInner, nil, bpm, WithMemoryLimit(1))
+			require.NoError(t, err)
+			defer join.Close()
+
+			require.NoError(t, join.Init())
+			require.NoError(t, bpm.FlushAllPages())
+
+			dm := bpm.GetDiskManager()
+			for _, partition := range tt.corrupted(join) {
+				for _, pageID := range partition.pageIDs {
+					_, err = dm.File.WriteAt([]byte{0xFF}, int64(pageID)*int64(dm.PageSize)+int64(dm.PageSize)-1)
+					require.NoError(t, err)
+				}
+			}
+
+			// A one-frame pool has to read every other spill page back from disk.
+			for {
+				_, ok, err := join.Next()
+				if err != nil {
+					assert.ErrorIs(t, err, disk_manager.ErrPageCorrupted)
+					return
+				}
+				require.True(t, ok, "corrupted partition read without error")
+			}
+		})
+	}
+}
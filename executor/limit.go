@@ -0,0 +1,38 @@
+package executor
+
+import "gobase/catalog"
+
+func (l *Limit) Init() error {
+	l.emitted = 0
+	return l.child.Init()
+}
+
+func (l *Limit) Next() (Row, bool, error) {
+	for l.emitted < l.offset {
+		_, ok, err := l.child.Next()
+		if err != nil || !ok {
+			return Row{}, false, err
+		}
+		l.emitted++
+	}
+
+	if l.emitted-l.offset >= l.limit {
+		return Row{}, false, nil
+	}
+
+	row, ok, err := l.child.Next()
+	if err != nil || !ok {
+		return Row{}, false, err
+	}
+
+	l.emitted++
+	return row, true, nil
+}
+
+func (l *Limit) Close() error {
+	return l.child.Close()
+}
+
+func (l *Limit) Schema() *catalog.Schema {
+	return l.child.Schema()
+}
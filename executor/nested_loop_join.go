@@ -0,0 +1,72 @@
+package executor
+
+import "gobase/catalog"
+
+func (j *NestedLoopJoin) Init() error {
+	j.hasLeft = false
+	return j.left.Init()
+}
+
+func (j *NestedLoopJoin) Next() (Row, bool, error) {
+	for {
+		if !j.hasLeft {
+			row, ok, err := j.left.Next()
+			if err != nil || !ok {
+				return Row{}, false, err
+			}
+
+			err = j.right.Init()
+			if err != nil {
+				return Row{}, false, err
+			}
+
+			j.leftRow = row
+			j.hasLeft = true
+			j.matched = false
+		}
+
+		rightRow, ok, err := j.right.Next()
+		if err != nil {
+			return Row{}, false, err
+		}
+
+		if !ok {
+			j.hasLeft = false
+			if j.joinType == JoinLeft && !j.matched {
+				return Row{Values: joinValues(j.leftRow.Values, nil, len(j.right.Schema().Columns))}, true, nil
+			}
+			continue
+		}
+
+		values := joinValues(j.leftRow.Values, rightRow.Values, len(rightRow.Values))
+		match, err := joinMatches(j.predicate, values)
+		if err != nil {
+			return Row{}, false, err
+		}
+		if !match {
+			continue
+		}
+
+		j.matched = true
+		if j.joinType == JoinSemi {
+			j.hasLeft = false
+			return j.leftRow, true, nil
+		}
+
+		return Row{Values: values}, true, nil
+	}
+}
+
+func (j *NestedLoopJoin) Close() error {
+	err := j.left.Close()
+	if err != nil {
+		j.right.Close()
+		return err
+	}
+
+	return j.right.Close()
+}
+
+func (j *NestedLoopJoin) Schema() *catalog.Schema {
+	return j.schema
+}
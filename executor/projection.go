@@ -0,0 +1,29 @@
+package executor
+
+import "gobase/catalog"
+
+func (p *Projection) Init() error {
+	return p.child.Init()
+}
+
+func (p *Projection) Next() (Row, bool, error) {
+	row, ok, err := p.child.Next()
+	if err != nil || !ok {
+		return Row{}, false, err
+	}
+
+	values := make([]any, len(p.columnIndexes))
+	for i, columnIndex := range p.columnIndexes {
+		values[i] = row.Values[columnIndex]
+	}
+
+	return Row{Values: values, RID: row.RID}, true, nil
+}
+
+func (p *Projection) Close() error {
+	return p.child.Close()
+}
+
+func (p *Projection) Schema() *catalog.Schema {
+	return p.schema
+}
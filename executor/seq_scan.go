@@ -0,0 +1,26 @@
+package executor
+
+import "gobase/catalog"
+
+func (s *SeqScan) Init() error {
+	s.iter = s.table.Heap.Scan()
+	return nil
+}
+
+func (s *SeqScan) Next() (Row, bool, error) {
+	rid, data, ok := s.iter.Next()
+	if !ok {
+		return Row{}, false, s.iter.Err()
+	}
+
+	return Row{Values: catalog.DecodeTuple(s.table.Schema, data), RID: rid}, true, nil
+}
+
+func (s *SeqScan) Close() error {
+	s.iter = nil
+	return nil
+}
+
+func (s *SeqScan) Schema() *catalog.Schema {
+	return s.schema
+}
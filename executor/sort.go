@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"bytes"
+	"sort"
+
+	"gobase/catalog"
+)
+
+// Rows are ordered by their index key encoding, so every column type sorts
+// the same way it does in a B+tree and NULLs come first.
+func (s *Sort) Init() error {
+	err := s.child.Init()
+	if err != nil {
+		return err
+	}
+
+	s.rows, err = drain(s.child)
+	if err != nil {
+		return err
+	}
+	s.position = 0
+
+	keys := make([][][]byte, len(s.rows))
+	for i, row := range s.rows {
+		keys[i], err = encodeColumns(s.child.Schema(), s.columnIndexes, row.Values)
+		if err != nil {
+			return err
+		}
+	}
+
+	order := make([]int, len(s.rows))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return s.compare(keys[order[i]], keys[order[j]]) < 0
+	})
+
+	sorted := make([]Row, len(s.rows))
+	for i, index := range order {
+		sorted[i] = s.rows[index]
+	}
+	s.rows = sorted
+
+	return nil
+}
+
+func (s *Sort) Next() (Row, bool, error) {
+	if s.position >= len(s.rows) {
+		return Row{}, false, nil
+	}
+
+	row := s.rows[s.position]
+	s.position++
+
+	return row, true, nil
+}
+
+func (s *Sort) Close() error {
+	s.rows = nil
+	return s.child.Close()
+}
+
+func (s *Sort) Schema() *catalog.Schema {
+	return s.child.Schema()
+}
+
+func (s *Sort) compare(left [][]byte, right [][]byte) int {
+	for i, key := range s.keys {
+		cmp := bytes.Compare(left[i], right[i])
+		if key.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"bytes"
+	"sort"
+
+	"gobase/catalog"
+)
+
+func (j *SortMergeJoin) Init() error {
+	err := j.left.Init()
+	if err != nil {
+		return err
+	}
+
+	err = j.right.Init()
+	if err != nil {
+		return err
+	}
+
+	leftRows, err := drain(j.left)
+	if err != nil {
+		return err
+	}
+
+	rightRows, err := drain(j.right)
+	if err != nil {
+		return err
+	}
+
+	j.leftRows, err = sortByKey(leftRows, j.keys.leftKey)
+	if err != nil {
+		return err
+	}
+
+	j.rightRows, err = sortByKey(rightRows, j.keys.rightKey)
+	if err != nil {
+		return err
+	}
+
+	j.position = 0
+	j.hasLeft = false
+	j.groupStart = 0
+	j.groupEnd = 0
+	j.current = 0
+
+	return nil
+}
+
+func (j *SortMergeJoin) Next() (Row, bool, error) {
+	for {
+		for j.hasLeft && j.current < j.groupEnd {
+			leftRow := j.leftRows[j.position].row
+			rightRow := j.rightRows[j.current].row
+			j.current++
+
+			values := joinValues(leftRow.Values, rightRow.Values, len(rightRow.Values))
+			match, err := joinMatches(j.predicate, values)
+			if err != nil {
+				return Row{}, false, err
+			}
+			if !match {
+				continue
+			}
+
+			j.matched = true
+			if j.joinType == JoinSemi {
+				j.current = j.groupEnd
+				return leftRow, true, nil
+			}
+
+			return Row{Values: values}, true, nil
+		}
+
+		if j.hasLeft {
+			leftRow := j.leftRows[j.position].row
+			j.hasLeft = false
+			j.position++
+
+			if j.joinType == JoinLeft && !j.matched {
+				return Row{Values: joinValues(leftRow.Values, nil, len(j.right.Schema().Columns))}, true, nil
+			}
+		}
+
+		if j.position >= len(j.leftRows) {
+			return Row{}, false, nil
+		}
+
+		j.hasLeft = true
+		j.matched = false
+		j.seekGroup(j.leftRows[j.position])
+	}
+}
+
+func (j *SortMergeJoin) Close() error {
+	j.leftRows = nil
+	j.rightRows = nil
+
+	err := j.left.Close()
+	if err != nil {
+		j.right.Close()
+		return err
+	}
+
+	return j.right.Close()
+}
+
+func (j *SortMergeJoin) Schema() *catalog.Schema {
+	return j.schema
+}
+
+// seekGroup positions the right cursor on the rows sharing the key of left.
+// Left keys only grow, so the right side is never rewound past the group.
+func (j *SortMergeJoin) seekGroup(left keyedRow) {
+	if !left.ok {
+		j.current = j.groupEnd
+		return
+	}
+
+	if j.groupStart < j.groupEnd && bytes.Equal(j.rightRows[j.groupStart].key, left.key) {
+		j.current = j.groupStart
+		return
+	}
+
+	start := j.groupEnd
+	for start < len(j.rightRows) && (!j.rightRows[start].ok || bytes.Compare(j.rightRows[start].key, left.key) < 0) {
+		start++
+	}
+
+	end := start
+	for end < len(j.rightRows) && bytes.Equal(j.rightRows[end].key, left.key) {
+		end++
+	}
+
+	j.groupStart = start
+	j.groupEnd = end
+	j.current = start
+}
+
+// Rows with a NULL key sort first and never match.
+func sortByKey(rows []Row, keyOf func(values []any) ([]byte, bool, error)) ([]keyedRow, error) {
+	keyed := make([]keyedRow, len(rows))
+	for i, row := range rows {
+		key, ok, err := keyOf(row.Values)
+		if err != nil {
+			return nil, err
+		}
+		keyed[i] = keyedRow{row: row, key: key, ok: ok}
+	}
+
+	sort.SliceStable(keyed, func(a, b int) bool {
+		if keyed[a].ok != keyed[b].ok {
+			return !keyed[a].ok
+		}
+
+		return bytes.Compare(keyed[a].key, keyed[b].key) < 0
+	})
+
+	return keyed, nil
+}
@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"encoding/binary"
+
+	"gobase/buffer_pool_manager"
+	"gobase/shared"
+)
+
+func newSpillPartition(bpm *buffer_pool_manager.BufferPoolManager) *spillPartition {
+	return &spillPartition{
+		bpm:      bpm,
+		capacity: int64(bpm.GetPageSize() - shared.PAGE_HEADER_SIZE),
+	}
+}
+
+func (sp *spillPartition) append(tuple []byte) error {
+	var length [SPILL_LENGTH_SIZE]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(tuple)))
+
+	err := sp.write(length[:])
+	if err != nil {
+		return err
+	}
+
+	return sp.write(tuple)
+}
+
+// Tuples are written back to back after the page header and may continue on
+// the next page.
+func (sp *spillPartition) write(data []byte) error {
+	for len(data) > 0 {
+		page := int(sp.size / sp.capacity)
+		offset := int(shared.PAGE_HEADER_SIZE) + int(sp.size%sp.capacity)
+
+		var pageID uint32
+		var frame *buffer_pool_manager.Frame
+		var err error
+		if page == len(sp.pageIDs) {
+			pageID, frame, err = sp.bpm.NewPage()
+			if err != nil {
+				return err
+			}
+			sp.pageIDs = append(sp.pageIDs, pageID)
+		} else {
+			pageID = sp.pageIDs[page]
+			frame, err = sp.bpm.FetchPage(pageID)
+			if err != nil {
+				return err
+			}
+		}
+
+		frame.WLatch()
+		n := copy(frame.Data[offset:], data)
+		frame.WUnlatch()
+
+		err = sp.bpm.UnpinPage(pageID, true)
+		if err != nil {
+			return err
+		}
+
+		data = data[n:]
+		sp.size += int64(n)
+	}
+
+	return nil
+}
+
+func (sp *spillPartition) readAt(data []byte, position int64) error {
+	for len(data) > 0 {
+		pageID := sp.pageIDs[position/sp.capacity]
+		offset := int(shared.PAGE_HEADER_SIZE) + int(position%sp.capacity)
+
+		frame, err := sp.bpm.FetchPage(pageID)
+		if err != nil {
+			return err
+		}
+
+		frame.RLatch()
+		n := copy(data, frame.Data[offset:])
+		frame.RUnlatch()
+
+		err = sp.bpm.UnpinPage(pageID, false)
+		if err != nil {
+			return err
+		}
+
+		data = data[n:]
+		position += int64(n)
+	}
+
+	return nil
+}
+
+// scan reads back every tuple appended so far.
+func (sp *spillPartition) scan() *spillReader {
+	return &spillReader{partition: sp, end: sp.size}
+}
+
+func (sp *spillPartition) remove() error {
+	for len(sp.pageIDs) > 0 {
+		err := sp.bpm.DeletePage(sp.pageIDs[len(sp.pageIDs)-1])
+		if err != nil {
+			return err
+		}
+		sp.pageIDs = sp.pageIDs[:len(sp.pageIDs)-1]
+	}
+
+	sp.size = 0
+	return nil
+}
+
+func (sr *spillReader) next() ([]byte, bool, error) {
+	if sr.position >= sr.end {
+		return nil, false, nil
+	}
+
+	var length [SPILL_LENGTH_SIZE]byte
+	err := sr.partition.readAt(length[:], sr.position)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tuple := make([]byte, binary.LittleEndian.Uint32(length[:]))
+	err = sr.partition.readAt(tuple, sr.position+SPILL_LENGTH_SIZE)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sr.position += SPILL_LENGTH_SIZE + int64(len(tuple))
+	return tuple, true, nil
+}
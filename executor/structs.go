@@ -0,0 +1,440 @@
+package executor
+
+import (
+	"gobase/buffer_pool_manager"
+	"gobase/catalog"
+	"gobase/table"
+	"gobase/table_heap"
+	"gobase/transaction_manager"
+)
+
+// Init (re)starts an executor, so a parent may scan the same child several
+// times. Next returns false once the executor is exhausted.
+type Executor interface {
+	Init() error
+	Next() (Row, bool, error)
+	Close() error
+	Schema() *catalog.Schema
+}
+
+type Row struct {
+	Values []any
+	RID    *table_heap.RID
+}
+
+type Predicate func(values []any) (bool, error)
+
+type SeqScan struct {
+	table  *table.Table
+	schema *catalog.Schema
+	iter   *table_heap.TableIterator
+}
+
+type IndexScan struct {
+	table   *table.Table
+	schema  *catalog.Schema
+	index   string
+	low     []any
+	high    []any
+	scanner *table.IndexScanner
+}
+
+type Filter struct {
+	child     Executor
+	predicate Predicate
+}
+
+type Projection struct {
+	child         Executor
+	columnIndexes []int
+	schema        *catalog.Schema
+}
+
+type Limit struct {
+	child   Executor
+	limit   int
+	offset  int
+	emitted int
+}
+
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+type Sort struct {
+	child         Executor
+	keys          []SortKey
+	columnIndexes []int
+	rows          []Row
+	position      int
+}
+
+type Aggregate struct {
+	Func   AggregateFunc
+	Column string
+	Name   string
+}
+
+type HashAggregate struct {
+	child         Executor
+	groupIndexes  []int
+	aggregates    []Aggregate
+	columnIndexes []int
+	schema        *catalog.Schema
+	rows          []Row
+	position      int
+}
+
+type aggregateGroup struct {
+	values []any
+	states []aggregateState
+}
+
+type aggregateState struct {
+	count    int64
+	intSum   int64
+	floatSum float64
+	value    any
+	key      []byte
+}
+
+type Values struct {
+	schema   *catalog.Schema
+	rows     [][]any
+	position int
+}
+
+type Insert struct {
+	txn   *transaction_manager.Transaction
+	table *table.Table
+	child Executor
+	done  bool
+}
+
+type Delete struct {
+	txn   *transaction_manager.Transaction
+	table *table.Table
+	child Executor
+	done  bool
+}
+
+type joinKeys struct {
+	leftIndexes  []int
+	rightIndexes []int
+	positions    []int
+	schema       *catalog.Schema
+}
+
+type NestedLoopJoin struct {
+	left      Executor
+	right     Executor
+	joinType  JoinType
+	predicate Predicate
+	schema    *catalog.Schema
+	leftRow   Row
+	hasLeft   bool
+	matched   bool
+}
+
+type IndexNestedLoopJoin struct {
+	left         Executor
+	right        *table.Table
+	index        string
+	leftIndexes  []int
+	rightIndexes []int
+	keySchema    *catalog.Schema
+	joinType     JoinType
+	predicate    Predicate
+	schema       *catalog.Schema
+	leftRow      Row
+	hasLeft      bool
+	matched      bool
+	scanner      *table.IndexScanner
+}
+
+type HashJoinOption func(*HashJoin)
+
+type HashJoin struct {
+	left        Executor
+	right       Executor
+	keys        *joinKeys
+	joinType    JoinType
+	predicate   Predicate
+	schema      *catalog.Schema
+	bpm         *buffer_pool_manager.BufferPoolManager
+	memoryLimit int
+	leftSpill   *catalog.Schema
+	rightSpill  *catalog.Schema
+
+	buckets   map[string][]Row
+	leftRow   Row
+	hasLeft   bool
+	matched   bool
+	matches   []Row
+	position  int
+	spilled   bool
+	partition int
+	build     []*spillPartition
+	probe     []*spillPartition
+	probeIter *spillReader
+}
+
+// spillPartition holds length-prefixed tuples in temporary buffer pool pages.
+// The pages are written without log records, so spilled rows are never
+// recovered.
+type spillPartition struct {
+	bpm      *buffer_pool_manager.BufferPoolManager
+	pageIDs  []uint32
+	capacity int64
+	size     int64
+}
+
+type spillReader struct {
+	partition *spillPartition
+	position  int64
+	end       int64
+}
+
+type SortMergeJoin struct {
+	left      Executor
+	right     Executor
+	keys      *joinKeys
+	joinType  JoinType
+	predicate Predicate
+	schema    *catalog.Schema
+
+	leftRows   []keyedRow
+	rightRows  []keyedRow
+	position   int
+	hasLeft    bool
+	matched    bool
+	groupStart int
+	groupEnd   int
+	current    int
+}
+
+type keyedRow struct {
+	row Row
+	key []byte
+	ok  bool
+}
+
+func NewSeqScan(t *table.Table) *SeqScan {
+	return &SeqScan{table: t, schema: tableSchema(t)}
+}
+
+func NewIndexScan(t *table.Table, index string, low []any, high []any) *IndexScan {
+	return &IndexScan{
+		table:  t,
+		schema: tableSchema(t),
+		index:  index,
+		low:    low,
+		high:   high,
+	}
+}
+
+func NewFilter(child Executor, predicate Predicate) *Filter {
+	return &Filter{
+		child:     child,
+		predicate: predicate,
+	}
+}
+
+func NewProjection(child Executor, columns ...string) (*Projection, error) {
+	columnIndexes, err := child.Schema().GetColumnIndexes(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaColumns := make([]catalog.Column, len(columnIndexes))
+	for i, columnIndex := range columnIndexes {
+		schemaColumns[i] = child.Schema().Columns[columnIndex]
+	}
+
+	return &Projection{
+		child:         child,
+		columnIndexes: columnIndexes,
+		schema:        catalog.NewSchema(schemaColumns),
+	}, nil
+}
+
+func NewLimit(child Executor, limit int, offset int) *Limit {
+	return &Limit{
+		child:  child,
+		limit:  limit,
+		offset: offset,
+	}
+}
+
+func NewSort(child Executor, keys ...SortKey) (*Sort, error) {
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.Column
+	}
+
+	columnIndexes, err := child.Schema().GetColumnIndexes(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sort{
+		child:         child,
+		keys:          keys,
+		columnIndexes: columnIndexes,
+	}, nil
+}
+
+func NewHashAggregate(child Executor, groupBy []string, aggregates ...Aggregate) (*HashAggregate, error) {
+	groupIndexes, err := child.Schema().GetColumnIndexes(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	ha := &HashAggregate{
+		child:         child,
+		groupIndexes:  groupIndexes,
+		aggregates:    aggregates,
+		columnIndexes: make([]int, len(aggregates)),
+	}
+
+	columns := make([]catalog.Column, 0, len(groupIndexes)+len(aggregates))
+	for _, groupIndex := range groupIndexes {
+		columns = append(columns, child.Schema().Columns[groupIndex])
+	}
+
+	for i, aggregate := range aggregates {
+		ha.columnIndexes[i] = -1
+		if aggregate.Column != "" {
+			ha.columnIndexes[i], err = child.Schema().GetColumnIndex(aggregate.Column)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		column, err := ha.resultColumn(i)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+
+	ha.schema = catalog.NewSchema(columns)
+	return ha, nil
+}
+
+func NewValues(schema *catalog.Schema, rows ...[]any) *Values {
+	return &Values{
+		schema: schema,
+		rows:   rows,
+	}
+}
+
+func NewInsert(txn *transaction_manager.Transaction, t *table.Table, child Executor) *Insert {
+	return &Insert{
+		txn:   txn,
+		table: t,
+		child: child,
+	}
+}
+
+func NewDelete(txn *transaction_manager.Transaction, t *table.Table, child Executor) *Delete {
+	return &Delete{
+		txn:   txn,
+		table: t,
+		child: child,
+	}
+}
+
+// A nil predicate joins every pair of rows.
+func NewNestedLoopJoin(left Executor, right Executor, joinType JoinType, predicate Predicate) *NestedLoopJoin {
+	return &NestedLoopJoin{
+		left:      left,
+		right:     right,
+		joinType:  joinType,
+		predicate: predicate,
+		schema:    joinSchema(left.Schema(), right.Schema(), joinType),
+	}
+}
+
+// For every left row, the right table is probed through index with the
+// values of leftKeys, which must match a prefix of the index columns.
+func NewIndexNestedLoopJoin(left Executor, right *table.Table, index string, leftKeys []string, joinType JoinType, predicate Predicate) (*IndexNestedLoopJoin, error) {
+	rightIndex, exists := right.Indexes[index]
+	if !exists {
+		return nil, table.ErrIndexNotFound
+	}
+	if len(leftKeys) == 0 || len(leftKeys) > len(rightIndex.Columns) {
+		return nil, ErrJoinKeyCount
+	}
+
+	keys, err := newJoinKeys(left.Schema(), leftKeys, right.Schema, rightIndex.Columns[:len(leftKeys)])
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexNestedLoopJoin{
+		left:         left,
+		right:        right,
+		index:        index,
+		leftIndexes:  keys.leftIndexes,
+		rightIndexes: keys.rightIndexes,
+		keySchema:    keys.schema,
+		joinType:     joinType,
+		predicate:    predicate,
+		schema:       joinSchema(left.Schema(), tableSchema(right), joinType),
+	}, nil
+}
+
+// The right child is the build side. When it outgrows the memory limit both
+// inputs are partitioned into temporary pages of bpm and joined one partition
+// at a time. The pages are freed when the join is closed or restarted; a pool
+// over a scratch database keeps them out of the main file.
+func NewHashJoin(left Executor, right Executor, leftKeys []string, rightKeys []string, joinType JoinType, predicate Predicate, bpm *buffer_pool_manager.BufferPoolManager, options ...HashJoinOption) (*HashJoin, error) {
+	keys, err := newJoinKeys(left.Schema(), leftKeys, right.Schema(), rightKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	hj := &HashJoin{
+		left:        left,
+		right:       right,
+		keys:        keys,
+		joinType:    joinType,
+		predicate:   predicate,
+		schema:      joinSchema(left.Schema(), right.Schema(), joinType),
+		bpm:         bpm,
+		memoryLimit: HASH_JOIN_MEMORY_LIMIT,
+		leftSpill:   spillSchema(left.Schema()),
+		rightSpill:  spillSchema(right.Schema()),
+	}
+
+	for _, option := range options {
+		option(hj)
+	}
+
+	return hj, nil
+}
+
+// WithMemoryLimit bounds, in encoded bytes, the build rows kept in memory.
+func WithMemoryLimit(bytes int) HashJoinOption {
+	return func(hj *HashJoin) {
+		hj.memoryLimit = bytes
+	}
+}
+
+func NewSortMergeJoin(left Executor, right Executor, leftKeys []string, rightKeys []string, joinType JoinType, predicate Predicate) (*SortMergeJoin, error) {
+	keys, err := newJoinKeys(left.Schema(), leftKeys, right.Schema(), rightKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SortMergeJoin{
+		left:      left,
+		right:     right,
+		keys:      keys,
+		joinType:  joinType,
+		predicate: predicate,
+		schema:    joinSchema(left.Schema(), right.Schema(), joinType),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"os"
+	"testing"
+
+	"gobase/buffer_pool_manager"
+	"gobase/catalog"
+	"gobase/disk_manager"
+	"gobase/table"
+	"gobase/table_heap"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTable(t *testing.T, name string, columns []catalog.Column, rows ...[]any) (*table.Table, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "executor_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	heap, err := table_heap.NewTableHeap(buffer_pool_manager.NewBufferPoolManager(dm, 32))
+	require.NoError(t, err)
+
+	tbl, err := table.NewTable(name, catalog.NewSchema(columns), heap)
+	require.NoError(t, err)
+
+	for _, row := range rows {
+		_, err = tbl.Insert(nil, row...)
+		require.NoError(t, err)
+	}
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return tbl, cleanup
+}
+
+func newTestBufferPool(t *testing.T, poolSize int) (*buffer_pool_manager.BufferPoolManager, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "executor_spill_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return buffer_pool_manager.NewBufferPoolManager(dm, poolSize), cleanup
+}
+
+func newTestUsers(t *testing.T) (*table.Table, func()) {
+	t.Helper()
+
+	columns := []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "city", Type: catalog.TypeVarchar, Size: 50, Nullable: true},
+		{Name: "age", Type: catalog.TypeInt, Nullable: true},
+	}
+
+	return newTestTable(t, "users", columns,
+		[]any{1, "Alice", "Paris", 30},
+		[]any{2, "Bob", "Lyon", 25},
+		[]any{3, "Carol", "Paris", nil},
+		[]any{4, "Dave", nil, 40},
+		[]any{5, "Eve", "Lyon", 35},
+	)
+}
+
+func collectValues(t *testing.T, e Executor) [][]any {
+	t.Helper()
+
+	rows, err := Collect(e)
+	require.NoError(t, err)
+
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		values[i] = row.Values
+	}
+
+	return values
+}
@@ -0,0 +1,51 @@
+package executor
+
+import "fmt"
+
+type AggregateFunc uint8
+
+const (
+	AggregateCount AggregateFunc = iota
+	AggregateSum
+	AggregateMin
+	AggregateMax
+	AggregateAvg
+)
+
+func (f AggregateFunc) String() string {
+	switch f {
+	case AggregateCount:
+		return "COUNT"
+	case AggregateSum:
+		return "SUM"
+	case AggregateMin:
+		return "MIN"
+	case AggregateMax:
+		return "MAX"
+	case AggregateAvg:
+		return "AVG"
+	}
+
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(f))
+}
+
+type JoinType uint8
+
+const (
+	JoinInner JoinType = iota
+	JoinLeft
+	JoinSemi
+)
+
+func (j JoinType) String() string {
+	switch j {
+	case JoinInner:
+		return "INNER"
+	case JoinLeft:
+		return "LEFT"
+	case JoinSemi:
+		return "SEMI"
+	}
+
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(j))
+}
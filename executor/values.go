@@ -0,0 +1,27 @@
+package executor
+
+import "gobase/catalog"
+
+func (v *Values) Init() error {
+	v.position = 0
+	return nil
+}
+
+func (v *Values) Next() (Row, bool, error) {
+	if v.position >= len(v.rows) {
+		return Row{}, false, nil
+	}
+
+	row := Row{Values: v.rows[v.position]}
+	v.position++
+
+	return row, true, nil
+}
+
+func (v *Values) Close() error {
+	return nil
+}
+
+func (v *Values) Schema() *catalog.Schema {
+	return v.schema
+}
@@ -0,0 +1,298 @@
+package expression
+
+import (
+	"math"
+	"time"
+
+	"gobase/catalog"
+	"gobase/sql"
+)
+
+func compile(expr sql.Expr, schema *catalog.Schema) (node, error) {
+	switch e := expr.(type) {
+	case *sql.ColumnRef:
+		return compileColumn(e, schema)
+	case *sql.Literal:
+		return compileLiteral(e.Value)
+	case *sql.UnaryExpr:
+		return compileUnary(e, schema)
+	case *sql.BinaryExpr:
+		return compileBinary(e, schema)
+	case *sql.LikeExpr:
+		return compileLike(e, schema)
+	case *sql.InExpr:
+		return compileIn(e, schema)
+	case *sql.IsNullExpr:
+		operand, err := compile(e.Expr, schema)
+		if err != nil {
+			return nil, err
+		}
+		return &isNullNode{expr: operand, not: e.Not}, nil
+	case *sql.CaseExpr:
+		return compileCase(e, schema)
+	}
+
+	return nil, ErrUnsupportedExpression
+}
+
+func compileColumn(ref *sql.ColumnRef, schema *catalog.Schema) (node, error) {
+	index, err := schema.ResolveColumn(ref.Table, ref.Column)
+	if err != nil {
+		return nil, err
+	}
+
+	column := schema.Columns[index]
+	return &columnNode{index: index, typ: valueType{column: column.Type, scale: column.Scale}}, nil
+}
+
+func compileLiteral(value any) (node, error) {
+	switch val := value.(type) {
+	case nil:
+		return &literalNode{typ: nullType}, nil
+	case int:
+		if val < math.MinInt32 || val > math.MaxInt32 {
+			return &literalNode{value: int64(val), typ: valueType{column: catalog.TypeBigInt}}, nil
+		}
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeInt}}, nil
+	case int64:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeBigInt}}, nil
+	case float64:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeDouble}}, nil
+	case catalog.Decimal:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeDecimal, scale: val.Scale}}, nil
+	case string:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeVarchar}}, nil
+	case bool:
+		return &literalNode{value: val, typ: booleanType}, nil
+	case time.Time:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeTimestamp}}, nil
+	case catalog.UUID:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeUUID}}, nil
+	case []byte:
+		return &literalNode{value: val, typ: valueType{column: catalog.TypeBlob}}, nil
+	}
+
+	return nil, ErrUnsupportedExpression
+}
+
+func compileUnary(expr *sql.UnaryExpr, schema *catalog.Schema) (node, error) {
+	operand, err := compile(expr.Operand, schema)
+	if err != nil {
+		return nil, err
+	}
+	typ := operand.resultType()
+
+	if expr.Op == sql.OpNot {
+		if !typ.accepts(classBoolean) {
+			return nil, typeError("argument of NOT must be BOOLEAN, not %s", typ)
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	if !typ.accepts(classNumeric) {
+		return nil, typeError("operator %s cannot be applied to %s", expr.Op, typ)
+	}
+	if typ.column == catalog.TypeSmallInt {
+		typ.column = catalog.TypeInt
+	}
+
+	return &negateNode{operand: operand, typ: typ}, nil
+}
+
+func compileBinary(expr *sql.BinaryExpr, schema *catalog.Schema) (node, error) {
+	left, err := compile(expr.Left, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := compile(expr.Right, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	leftType := left.resultType()
+	rightType := right.resultType()
+
+	switch expr.Op {
+	case sql.OpAnd, sql.OpOr:
+		for _, typ := range []valueType{leftType, rightType} {
+			if !typ.accepts(classBoolean) {
+				return nil, typeError("argument of %s must be BOOLEAN, not %s", expr.Op, typ)
+			}
+		}
+		return &logicalNode{op: expr.Op, left: left, right: right}, nil
+	case sql.OpEq, sql.OpNotEq, sql.OpLt, sql.OpLtEq, sql.OpGt, sql.OpGtEq:
+		if !comparable(leftType, rightType) {
+			return nil, operatorError(expr.Op.String(), leftType, rightType)
+		}
+		return &comparisonNode{op: expr.Op, left: left, right: right}, nil
+	}
+
+	typ, ok := arithmeticType(expr.Op, leftType, rightType)
+	if !ok {
+		return nil, operatorError(expr.Op.String(), leftType, rightType)
+	}
+
+	return &arithmeticNode{op: expr.Op, left: left, right: right, typ: typ}, nil
+}
+
+func compileLike(expr *sql.LikeExpr, schema *catalog.Schema) (node, error) {
+	operand, err := compile(expr.Expr, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := compile(expr.Pattern, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if !operand.resultType().accepts(classString) || !pattern.resultType().accepts(classString) {
+		return nil, operatorError("LIKE", operand.resultType(), pattern.resultType())
+	}
+
+	return &likeNode{expr: operand, pattern: pattern, not: expr.Not}, nil
+}
+
+func compileIn(expr *sql.InExpr, schema *catalog.Schema) (node, error) {
+	operand, err := compile(expr.Expr, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]node, len(expr.List))
+	for i, item := range expr.List {
+		list[i], err = compile(item, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		if !comparable(operand.resultType(), list[i].resultType()) {
+			return nil, operatorError("IN", operand.resultType(), list[i].resultType())
+		}
+	}
+
+	return &inNode{expr: operand, list: list, not: expr.Not}, nil
+}
+
+// A simple CASE is compiled into a searched one comparing the operand with
+// each WHEN value.
+func compileCase(expr *sql.CaseExpr, schema *catalog.Schema) (node, error) {
+	var operand node
+	var err error
+
+	if expr.Operand != nil {
+		operand, err = compile(expr.Operand, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cn := &caseNode{typ: nullType}
+	for _, when := range expr.Whens {
+		cond, err := compile(when.Cond, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		if operand != nil {
+			if !comparable(operand.resultType(), cond.resultType()) {
+				return nil, operatorError(sql.OpEq.String(), operand.resultType(), cond.resultType())
+			}
+			cond = &comparisonNode{op: sql.OpEq, left: operand, right: cond}
+		} else if !cond.resultType().accepts(classBoolean) {
+			return nil, typeError("argument of WHEN must be BOOLEAN, not %s", cond.resultType())
+		}
+
+		result, err := compile(when.Result, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		cn.typ, err = caseType(cn.typ, result.resultType())
+		if err != nil {
+			return nil, err
+		}
+		cn.whens = append(cn.whens, whenNode{cond: cond, result: result})
+	}
+
+	if expr.Else != nil {
+		cn.elseNode, err = compile(expr.Else, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		cn.typ, err = caseType(cn.typ, cn.elseNode.resultType())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+func comparable(left valueType, right valueType) bool {
+	return left.null || right.null || left.class() == right.class()
+}
+
+// arithmeticType widens integers to the larger integer type, and any integer
+// or decimal meeting a DOUBLE to DOUBLE. Decimal results keep enough scale
+// for the operator, capped at the maximum precision.
+func arithmeticType(op sql.Operator, left valueType, right valueType) (valueType, bool) {
+	if !left.accepts(classNumeric) || !right.accepts(classNumeric) {
+		return valueType{}, false
+	}
+
+	if left.null {
+		left = right
+	}
+	if right.null {
+		right = left
+	}
+	if left.null {
+		return nullType, true
+	}
+
+	if op == sql.OpMod && (!left.isInteger() || !right.isInteger()) {
+		return valueType{}, false
+	}
+
+	switch {
+	case left.column == catalog.TypeDouble || right.column == catalog.TypeDouble:
+		return valueType{column: catalog.TypeDouble}, true
+	case left.column == catalog.TypeDecimal || right.column == catalog.TypeDecimal:
+		scale := max(left.scale, right.scale)
+		switch op {
+		case sql.OpMul:
+			scale = left.scale + right.scale
+		case sql.OpDiv:
+			scale = max(scale, DECIMAL_DIVISION_SCALE)
+		}
+		return valueType{column: catalog.TypeDecimal, scale: min(scale, catalog.MAX_DECIMAL_PRECISION)}, true
+	case left.column == catalog.TypeBigInt || right.column == catalog.TypeBigInt:
+		return valueType{column: catalog.TypeBigInt}, true
+	}
+
+	return valueType{column: catalog.TypeInt}, true
+}
+
+func caseType(current valueType, result valueType) (valueType, error) {
+	switch {
+	case result.null:
+		return current, nil
+	case current.null:
+		if result.column == catalog.TypeSmallInt {
+			result.column = catalog.TypeInt
+		}
+		return result, nil
+	case current.class() != result.class():
+		return valueType{}, typeError("CASE types %s and %s cannot be matched", current, result)
+	case current.class() == classNumeric:
+		typ, _ := arithmeticType(sql.OpAdd, current, result)
+		return typ, nil
+	case current.column != result.column:
+		return valueType{column: catalog.TypeTimestamp}, nil
+	}
+
+	return current, nil
+}
@@ -0,0 +1,5 @@
+package expression
+
+const (
+	DECIMAL_DIVISION_SCALE uint8 = 6
+)
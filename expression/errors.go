@@ -0,0 +1,21 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTypeMismatch          = errors.New("type mismatch")
+	ErrUnsupportedExpression = errors.New("unsupported expression")
+	ErrDivisionByZero        = errors.New("division by zero")
+	ErrOutOfRange            = errors.New("numeric value out of range")
+)
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrTypeMismatch, e.Message)
+}
+
+func (e *TypeError) Unwrap() error {
+	return ErrTypeMismatch
+}
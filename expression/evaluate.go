@@ -0,0 +1,261 @@
+package expression
+
+import (
+	"math"
+
+	"gobase/catalog"
+	"gobase/sql"
+)
+
+func (n *columnNode) eval(values []any) (any, error) {
+	return values[n.index], nil
+}
+
+func (n *columnNode) resultType() valueType {
+	return n.typ
+}
+
+func (n *literalNode) eval(values []any) (any, error) {
+	return n.value, nil
+}
+
+func (n *literalNode) resultType() valueType {
+	return n.typ
+}
+
+func (n *negateNode) eval(values []any) (any, error) {
+	value, err := n.operand.eval(values)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	switch val := value.(type) {
+	case float64:
+		return -val, nil
+	case catalog.Decimal:
+		return catalog.NewDecimal(-val.Unscaled, val.Scale), nil
+	}
+
+	return integerArithmetic(sql.OpSub, 0, toInt64(value), n.typ)
+}
+
+func (n *negateNode) resultType() valueType {
+	return n.typ
+}
+
+func (n *arithmeticNode) eval(values []any) (any, error) {
+	left, err := n.left.eval(values)
+	if err != nil || left == nil {
+		return nil, err
+	}
+
+	right, err := n.right.eval(values)
+	if err != nil || right == nil {
+		return nil, err
+	}
+
+	switch n.typ.column {
+	case catalog.TypeDouble:
+		return floatArithmetic(n.op, catalog.ToFloat64(left), catalog.ToFloat64(right))
+	case catalog.TypeDecimal:
+		return decimalArithmetic(n.op, toDecimal(left), toDecimal(right), n.typ.scale)
+	}
+
+	return integerArithmetic(n.op, toInt64(left), toInt64(right), n.typ)
+}
+
+func (n *arithmeticNode) resultType() valueType {
+	return n.typ
+}
+
+func (n *comparisonNode) eval(values []any) (any, error) {
+	left, err := n.left.eval(values)
+	if err != nil || left == nil {
+		return nil, err
+	}
+
+	right, err := n.right.eval(values)
+	if err != nil || right == nil {
+		return nil, err
+	}
+
+	cmp := compareValues(left, right)
+	switch n.op {
+	case sql.OpEq:
+		return cmp == 0, nil
+	case sql.OpNotEq:
+		return cmp != 0, nil
+	case sql.OpLt:
+		return cmp < 0, nil
+	case sql.OpLtEq:
+		return cmp <= 0, nil
+	case sql.OpGt:
+		return cmp > 0, nil
+	}
+
+	return cmp >= 0, nil
+}
+
+func (n *comparisonNode) resultType() valueType {
+	return booleanType
+}
+
+// AND is false as soon as one side is false and OR true as soon as one side
+// is true, whatever the other side holds; otherwise NULL wins.
+func (n *logicalNode) eval(values []any) (any, error) {
+	decisive := n.op == sql.OpOr
+
+	left, err := n.left.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	if left == decisive {
+		return decisive, nil
+	}
+
+	right, err := n.right.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	if right == decisive {
+		return decisive, nil
+	}
+
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	return !decisive, nil
+}
+
+func (n *logicalNode) resultType() valueType {
+	return booleanType
+}
+
+func (n *notNode) eval(values []any) (any, error) {
+	value, err := n.operand.eval(values)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return !value.(bool), nil
+}
+
+func (n *notNode) resultType() valueType {
+	return booleanType
+}
+
+func (n *likeNode) eval(values []any) (any, error) {
+	value, err := n.expr.eval(values)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	pattern, err := n.pattern.eval(values)
+	if err != nil || pattern == nil {
+		return nil, err
+	}
+
+	return matchLike([]rune(value.(string)), []rune(pattern.(string))) != n.not, nil
+}
+
+func (n *likeNode) resultType() valueType {
+	return booleanType
+}
+
+// x IN (...) is true on any equal item, NULL if no item is equal but one of
+// them is NULL, and false otherwise. NOT IN negates that.
+func (n *inNode) eval(values []any) (any, error) {
+	value, err := n.expr.eval(values)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	sawNull := false
+	for _, item := range n.list {
+		candidate, err := item.eval(values)
+		if err != nil {
+			return nil, err
+		}
+
+		if candidate == nil {
+			sawNull = true
+			continue
+		}
+
+		if compareValues(value, candidate) == 0 {
+			return !n.not, nil
+		}
+	}
+
+	if sawNull {
+		return nil, nil
+	}
+
+	return n.not, nil
+}
+
+func (n *inNode) resultType() valueType {
+	return booleanType
+}
+
+func (n *isNullNode) eval(values []any) (any, error) {
+	value, err := n.expr.eval(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return (value == nil) != n.not, nil
+}
+
+func (n *isNullNode) resultType() valueType {
+	return booleanType
+}
+
+func (n *caseNode) eval(values []any) (any, error) {
+	for _, when := range n.whens {
+		cond, err := when.cond.eval(values)
+		if err != nil {
+			return nil, err
+		}
+
+		if cond == true {
+			return n.evalResult(when.result, values)
+		}
+	}
+
+	if n.elseNode == nil {
+		return nil, nil
+	}
+
+	return n.evalResult(n.elseNode, values)
+}
+
+func (n *caseNode) resultType() valueType {
+	return n.typ
+}
+
+// evalResult converts a branch result to the type shared by all branches.
+func (n *caseNode) evalResult(result node, values []any) (any, error) {
+	value, err := result.eval(values)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	switch n.typ.column {
+	case catalog.TypeBigInt:
+		return toInt64(value), nil
+	case catalog.TypeDouble:
+		return catalog.ToFloat64(value), nil
+	case catalog.TypeDecimal:
+		return decimalArithmetic(sql.OpAdd, toDecimal(value), catalog.NewDecimal(0, 0), n.typ.scale)
+	case catalog.TypeInt:
+		val := toInt64(value)
+		if val < math.MinInt32 || val > math.MaxInt32 {
+			return nil, ErrOutOfRange
+		}
+		return int(val), nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,48 @@
+package expression
+
+import (
+	"gobase/catalog"
+	"gobase/executor"
+	"gobase/sql"
+)
+
+// Compile resolves the column references of expr against schema and checks
+// the operand types of every operator, so evaluation cannot meet a value of
+// the wrong type. A qualified reference only matches the columns of that
+// table; an unqualified one must not match columns of several tables.
+func Compile(expr sql.Expr, schema *catalog.Schema) (*Expression, error) {
+	root, err := compile(expr, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{root: root}, nil
+}
+
+func Parse(text string, schema *catalog.Schema) (*Expression, error) {
+	expr, err := sql.ParseExpr(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(expr, schema)
+}
+
+// Evaluate returns nil for NULL, otherwise a value of the Go type
+// catalog.DecodeTuple uses for the result type.
+func (e *Expression) Evaluate(values []any) (any, error) {
+	return e.root.eval(values)
+}
+
+// Predicate adapts a boolean expression for executor.Filter; a NULL result
+// rejects the row, as in a WHERE clause.
+func (e *Expression) Predicate() (executor.Predicate, error) {
+	if !e.root.resultType().accepts(classBoolean) {
+		return nil, typeError("predicate must be BOOLEAN, not %s", e.root.resultType())
+	}
+
+	return func(values []any) (bool, error) {
+		value, err := e.root.eval(values)
+		return value == true, err
+	}, nil
+}
@@ -0,0 +1,289 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"gobase/catalog"
+	"gobase/executor"
+	"gobase/sql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate_Arithmetic(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{"age + 1", 31},
+		{"-age * 2", -60},
+		{"id / 2", 3},
+		{"id % 4", 3},
+		{"visits + id", int64(1)<<40 + 7},
+		{"score * 2", 5.0},
+		{"age + score", 32.5},
+		{"balance + 1", catalog.NewDecimal(12650, 2)},
+		{"balance * 2.5", catalog.NewDecimal(313750, 3)},
+		{"balance / 4", catalog.NewDecimal(31375000, 6)},
+		{"1.00 / 3", catalog.NewDecimal(333333, 6)},
+		{"-2.5 / 3", catalog.NewDecimal(-833333, 6)},
+		{"balance - 0.005", catalog.NewDecimal(125495, 3)},
+		{"age + NULL", nil},
+		{"NULL * NULL", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, evaluate(t, tt.expr, newTestRow()), tt.expr)
+	}
+}
+
+func TestEvaluate_ArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  error
+	}{
+		{"id / 0", ErrDivisionByZero},
+		{"id % (age - 30)", ErrDivisionByZero},
+		{"score / 0", ErrDivisionByZero},
+		{"balance / 0.0", ErrDivisionByZero},
+		{"age * 100000000", ErrOutOfRange},
+		{"visits * visits", ErrOutOfRange},
+		{"balance * 100000000000000", ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr, newTestSchema())
+		require.NoError(t, err, tt.expr)
+
+		_, err = expr.Evaluate(newTestRow())
+		assert.ErrorIs(t, err, tt.err, tt.expr)
+	}
+}
+
+func TestEvaluate_Comparisons(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{"age > 25", true},
+		{"age <= 29", false},
+		{"age <> 30", false},
+		{"name = 'Alice'", true},
+		{"name < 'Bob'", true},
+		{"balance = 125.5", true},
+		{"balance > 125.49", true},
+		{"score = 2.5", true},
+		{"age < score", false},
+		{"visits > 2147483647", true},
+		{"joined = DATE '2024-03-01'", true},
+		{"joined < TIMESTAMP '2024-03-01 00:00:01'", true},
+		{"active = TRUE", nil},
+		{"age = NULL", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, evaluate(t, tt.expr, newTestRow()), tt.expr)
+	}
+}
+
+func TestEvaluate_ThreeValuedLogic(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{"active AND FALSE", false},
+		{"active AND TRUE", nil},
+		{"active OR TRUE", true},
+		{"active OR FALSE", nil},
+		{"NOT active", nil},
+		{"NOT (age > 25)", false},
+		{"active IS NULL", true},
+		{"active IS NOT NULL", false},
+		{"age IS NULL OR active", nil},
+		{"age > 25 AND name LIKE 'A%'", true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, evaluate(t, tt.expr, newTestRow()), tt.expr)
+	}
+}
+
+func TestEvaluate_LikeAndIn(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{"name LIKE 'A%'", true},
+		{"name LIKE '_lic_'", true},
+		{"name LIKE '%ic%'", true},
+		{"name LIKE 'a%'", false},
+		{"name LIKE 'Al'", false},
+		{"name LIKE '%'", true},
+		{"name NOT LIKE '%e'", false},
+		{"'ab%c' LIKE 'a%%c'", true},
+		{"'été' LIKE '_t_'", true},
+		{"name LIKE NULL", nil},
+		{"age IN (1, 30, 5)", true},
+		{"age IN (1, 2)", false},
+		{"age IN (1, NULL)", nil},
+		{"age NOT IN (1, 2)", true},
+		{"age NOT IN (1, NULL)", nil},
+		{"age NOT IN (30, NULL)", false},
+		{"balance IN (125.50, 1)", true},
+		{"NULL IN (1)", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, evaluate(t, tt.expr, newTestRow()), tt.expr)
+	}
+}
+
+func TestEvaluate_Case(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{"CASE WHEN age >= 18 THEN 'adult' ELSE 'minor' END", "adult"},
+		{"CASE WHEN active THEN 1 WHEN age > 40 THEN 2 END", nil},
+		{"CASE id WHEN 1 THEN 'one' WHEN 7 THEN 'seven' ELSE 'many' END", "seven"},
+		{"CASE WHEN age > 18 THEN 1 ELSE 2.5 END", catalog.NewDecimal(10, 1)},
+		{"CASE WHEN age > 18 THEN visits ELSE 0 END", int64(1) << 40},
+		{"CASE WHEN age > 18 THEN NULL ELSE 1 END", nil},
+		{"CASE WHEN age < 18 THEN joined ELSE TIMESTAMP '2024-01-01 12:00:00' END", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, evaluate(t, tt.expr, newTestRow()), tt.expr)
+	}
+}
+
+func TestCompile_TypeErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		message string
+	}{
+		{"name + 1", "operator + cannot be applied to VARCHAR and INT"},
+		{"age = 'thirty'", "operator = cannot be applied to INT and VARCHAR"},
+		{"score % 2", "operator % cannot be applied to DOUBLE and INT"},
+		{"-name", "operator - cannot be applied to VARCHAR"},
+		{"age AND active", "argument of AND must be BOOLEAN, not INT"},
+		{"NOT name", "argument of NOT must be BOOLEAN, not VARCHAR"},
+		{"age LIKE '3%'", "operator LIKE cannot be applied to INT and VARCHAR"},
+		{"age IN (1, 'two')", "operator IN cannot be applied to INT and VARCHAR"},
+		{"joined > 5", "operator > cannot be applied to DATE and INT"},
+		{"CASE WHEN age THEN 1 END", "argument of WHEN must be BOOLEAN, not INT"},
+		{"CASE WHEN active THEN 1 ELSE 'x' END", "CASE types INT and VARCHAR cannot be matched"},
+		{"CASE id WHEN 'x' THEN 1 END", "operator = cannot be applied to INT and VARCHAR"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.expr, newTestSchema())
+		require.ErrorIs(t, err, ErrTypeMismatch, tt.expr)
+
+		var typeErr *TypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.Equal(t, tt.message, typeErr.Message, tt.expr)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	_, err := Parse("missing + 1", newTestSchema())
+	assert.ErrorIs(t, err, catalog.ErrColumnNotFound)
+
+	_, err = Parse("age >", newTestSchema())
+	assert.ErrorIs(t, err, sql.ErrSyntax)
+
+	_, err = Compile(&sql.Star{}, newTestSchema())
+	assert.ErrorIs(t, err, ErrUnsupportedExpression)
+
+	schema := newTestSchema()
+	for i := range schema.Columns {
+		schema.Columns[i].Table = "u"
+	}
+	expr, err := Parse("u.age + 1", schema)
+	require.NoError(t, err)
+	value, err := expr.Evaluate(newTestRow())
+	require.NoError(t, err)
+	assert.Equal(t, 31, value)
+}
+
+func TestCompile_TableQualifiers(t *testing.T) {
+	schema := catalog.NewSchema([]catalog.Column{
+		{Table: "a", Name: "id", Type: catalog.TypeInt},
+		{Table: "a", Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Table: "b", Name: "id", Type: catalog.TypeInt},
+	})
+	row := []any{1, "Alice", 2}
+
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{expr: "a.id = b.id", expected: false},
+		{expr: "a.id", expected: 1},
+		{expr: "b.id", expected: 2},
+		{expr: "name", expected: "Alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Parse(tt.expr, schema)
+			require.NoError(t, err)
+			value, err := expr.Evaluate(row)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+
+	errorTests := []struct {
+		expr string
+		err  error
+	}{
+		{expr: "id = 1", err: catalog.ErrAmbiguousColumn},
+		{expr: "nosuch.id = 1", err: catalog.ErrUnknownTable},
+		{expr: "b.name = 'Alice'", err: catalog.ErrColumnNotFound},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, schema)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestPredicate(t *testing.T) {
+	expr, err := Parse("age > 25 AND name LIKE 'A%'", newTestSchema())
+	require.NoError(t, err)
+
+	predicate, err := expr.Predicate()
+	require.NoError(t, err)
+
+	row := newTestRow()
+	keep, err := predicate(row)
+	require.NoError(t, err)
+	assert.True(t, keep)
+
+	row[2] = nil
+	keep, err = predicate(row)
+	require.NoError(t, err)
+	assert.False(t, keep)
+
+	expr, err = Parse("age + 1", newTestSchema())
+	require.NoError(t, err)
+	_, err = expr.Predicate()
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+
+	schema := newTestSchema()
+	values := executor.NewValues(schema, newTestRow(), []any{8, "Bob", 20, nil, 1.0, true, time.Now(), int64(0)})
+	expr, err = Parse("active OR age > 25", schema)
+	require.NoError(t, err)
+	predicate, err = expr.Predicate()
+	require.NoError(t, err)
+
+	rows, err := executor.Collect(executor.NewFilter(values, predicate))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+}
@@ -0,0 +1,267 @@
+package expression
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"gobase/catalog"
+	"gobase/sql"
+)
+
+func typeError(format string, args ...any) error {
+	return &TypeError{Message: fmt.Sprintf(format, args...)}
+}
+
+func operatorError(op string, left valueType, right valueType) error {
+	return typeError("operator %s cannot be applied to %s and %s", op, left, right)
+}
+
+func toInt64(value any) int64 {
+	switch val := value.(type) {
+	case int:
+		return int64(val)
+	case int64:
+		return val
+	}
+
+	return 0
+}
+
+func toDecimal(value any) catalog.Decimal {
+	switch val := value.(type) {
+	case int:
+		return catalog.NewDecimal(int64(val), 0)
+	case int64:
+		return catalog.NewDecimal(val, 0)
+	case catalog.Decimal:
+		return val
+	}
+
+	return catalog.Decimal{}
+}
+
+// integerArithmetic computes in int64 and then checks the result against the
+// range of typ.
+func integerArithmetic(op sql.Operator, left int64, right int64, typ valueType) (any, error) {
+	var result int64
+
+	switch op {
+	case sql.OpAdd:
+		result = left + right
+		if right > 0 && result < left || right < 0 && result > left {
+			return nil, ErrOutOfRange
+		}
+	case sql.OpSub:
+		result = left - right
+		if right < 0 && result < left || right > 0 && result > left {
+			return nil, ErrOutOfRange
+		}
+	case sql.OpMul:
+		result = left * right
+		if left != 0 && (result/left != right || left == -1 && right == math.MinInt64) {
+			return nil, ErrOutOfRange
+		}
+	case sql.OpDiv, sql.OpMod:
+		if right == 0 {
+			return nil, ErrDivisionByZero
+		}
+		if right == -1 {
+			if op == sql.OpMod {
+				return normalizeInteger(0, typ)
+			}
+			if left == math.MinInt64 {
+				return nil, ErrOutOfRange
+			}
+		}
+		if op == sql.OpDiv {
+			result = left / right
+		} else {
+			result = left % right
+		}
+	}
+
+	return normalizeInteger(result, typ)
+}
+
+func normalizeInteger(value int64, typ valueType) (any, error) {
+	if typ.column == catalog.TypeBigInt {
+		return value, nil
+	}
+
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return nil, ErrOutOfRange
+	}
+
+	return int(value), nil
+}
+
+func floatArithmetic(op sql.Operator, left float64, right float64) (any, error) {
+	switch op {
+	case sql.OpAdd:
+		return left + right, nil
+	case sql.OpSub:
+		return left - right, nil
+	case sql.OpMul:
+		return left * right, nil
+	}
+
+	if right == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	return left / right, nil
+}
+
+// decimalArithmetic computes exactly on big integers and rounds the result
+// half away from zero to scale.
+func decimalArithmetic(op sql.Operator, left catalog.Decimal, right catalog.Decimal, scale uint8) (any, error) {
+	x := big.NewInt(left.Unscaled)
+	y := big.NewInt(right.Unscaled)
+
+	var result *big.Int
+	var resultScale uint8
+
+	switch op {
+	case sql.OpAdd, sql.OpSub:
+		resultScale = max(left.Scale, right.Scale)
+		x.Mul(x, pow10(int(resultScale-left.Scale)))
+		y.Mul(y, pow10(int(resultScale-right.Scale)))
+		if op == sql.OpAdd {
+			result = x.Add(x, y)
+		} else {
+			result = x.Sub(x, y)
+		}
+	case sql.OpMul:
+		result = x.Mul(x, y)
+		resultScale = left.Scale + right.Scale
+	case sql.OpDiv:
+		if y.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		shift := int(scale) + int(right.Scale) - int(left.Scale)
+		if shift >= 0 {
+			x.Mul(x, pow10(shift))
+		} else {
+			y.Mul(y, pow10(-shift))
+		}
+		result = roundQuotient(x, y)
+		resultScale = scale
+	}
+
+	if resultScale > scale {
+		result = roundQuotient(result, pow10(int(resultScale-scale)))
+	} else {
+		result.Mul(result, pow10(int(scale-resultScale)))
+	}
+
+	if result.CmpAbs(pow10(int(catalog.MAX_DECIMAL_PRECISION))) >= 0 {
+		return nil, ErrOutOfRange
+	}
+
+	return catalog.NewDecimal(result.Int64(), scale), nil
+}
+
+func roundQuotient(numerator *big.Int, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.CmpAbs(denominator) >= 0 {
+		if numerator.Sign() == denominator.Sign() {
+			quotient.Add(quotient, big.NewInt(1))
+		} else {
+			quotient.Sub(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// compareValues orders two non-NULL values that passed type checking, mixing
+// numeric representations as needed.
+func compareValues(left any, right any) int {
+	switch val := left.(type) {
+	case string:
+		return strings.Compare(val, right.(string))
+	case bool:
+		return cmp.Compare(boolRank(val), boolRank(right.(bool)))
+	case time.Time:
+		return val.Compare(right.(time.Time))
+	case catalog.UUID:
+		other := right.(catalog.UUID)
+		return bytes.Compare(val[:], other[:])
+	case []byte:
+		return bytes.Compare(val, right.([]byte))
+	}
+
+	return compareNumbers(left, right)
+}
+
+func compareNumbers(left any, right any) int {
+	_, leftFloat := left.(float64)
+	_, rightFloat := right.(float64)
+	if leftFloat || rightFloat {
+		return cmp.Compare(catalog.ToFloat64(left), catalog.ToFloat64(right))
+	}
+
+	_, leftDecimal := left.(catalog.Decimal)
+	_, rightDecimal := right.(catalog.Decimal)
+	if leftDecimal || rightDecimal {
+		x := toDecimal(left)
+		y := toDecimal(right)
+		scale := max(x.Scale, y.Scale)
+
+		a := new(big.Int).Mul(big.NewInt(x.Unscaled), pow10(int(scale-x.Scale)))
+		b := new(big.Int).Mul(big.NewInt(y.Unscaled), pow10(int(scale-y.Scale)))
+		return a.Cmp(b)
+	}
+
+	return cmp.Compare(toInt64(left), toInt64(right))
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+// matchLike implements LIKE with % for any run of characters and _ for
+// exactly one, backtracking only to the last %.
+func matchLike(value []rune, pattern []rune) bool {
+	v, p := 0, 0
+	starPattern, starValue := -1, 0
+
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '_' || pattern[p] == value[v]) && pattern[p] != '%':
+			v++
+			p++
+		case p < len(pattern) && pattern[p] == '%':
+			starPattern = p
+			starValue = v
+			p++
+		case starPattern != -1:
+			starValue++
+			v = starValue
+			p = starPattern + 1
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '%' {
+		p++
+	}
+
+	return p == len(pattern)
+}
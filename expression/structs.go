@@ -0,0 +1,84 @@
+package expression
+
+import (
+	"gobase/sql"
+)
+
+type Expression struct {
+	root node
+}
+
+type TypeError struct {
+	Message string
+}
+
+type node interface {
+	eval(values []any) (any, error)
+	resultType() valueType
+}
+
+type columnNode struct {
+	index int
+	typ   valueType
+}
+
+type literalNode struct {
+	value any
+	typ   valueType
+}
+
+type negateNode struct {
+	operand node
+	typ     valueType
+}
+
+type arithmeticNode struct {
+	op    sql.Operator
+	left  node
+	right node
+	typ   valueType
+}
+
+type comparisonNode struct {
+	op    sql.Operator
+	left  node
+	right node
+}
+
+type logicalNode struct {
+	op    sql.Operator
+	left  node
+	right node
+}
+
+type notNode struct {
+	operand node
+}
+
+type likeNode struct {
+	expr    node
+	pattern node
+	not     bool
+}
+
+type inNode struct {
+	expr node
+	list []node
+	not  bool
+}
+
+type isNullNode struct {
+	expr node
+	not  bool
+}
+
+type caseNode struct {
+	whens    []whenNode
+	elseNode node
+	typ      valueType
+}
+
+type whenNode struct {
+	cond   node
+	result node
+}
@@ -0,0 +1,48 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"gobase/catalog"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchema() *catalog.Schema {
+	return catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "age", Type: catalog.TypeInt, Nullable: true},
+		{Name: "balance", Type: catalog.TypeDecimal, Precision: 10, Scale: 2, Nullable: true},
+		{Name: "score", Type: catalog.TypeDouble},
+		{Name: "active", Type: catalog.TypeBoolean, Nullable: true},
+		{Name: "joined", Type: catalog.TypeDate},
+		{Name: "visits", Type: catalog.TypeBigInt},
+	})
+}
+
+func newTestRow() []any {
+	return []any{
+		7,
+		"Alice",
+		30,
+		catalog.NewDecimal(12550, 2),
+		2.5,
+		nil,
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		int64(1) << 40,
+	}
+}
+
+func evaluate(t *testing.T, text string, values []any) any {
+	t.Helper()
+
+	expr, err := Parse(text, newTestSchema())
+	require.NoError(t, err, text)
+
+	value, err := expr.Evaluate(values)
+	require.NoError(t, err, text)
+
+	return value
+}
@@ -0,0 +1,72 @@
+package expression
+
+import "gobase/catalog"
+
+// A NULL literal has no column type of its own: it takes the type of
+// whatever it is combined with.
+type valueType struct {
+	column catalog.ColumnType
+	scale  uint8
+	null   bool
+}
+
+type typeClass uint8
+
+const (
+	classNull typeClass = iota
+	classNumeric
+	classString
+	classBoolean
+	classTemporal
+	classUUID
+	classBlob
+)
+
+var (
+	nullType    = valueType{null: true}
+	booleanType = valueType{column: catalog.TypeBoolean}
+)
+
+func (t valueType) String() string {
+	if t.null {
+		return "NULL"
+	}
+
+	return t.column.String()
+}
+
+func (t valueType) class() typeClass {
+	if t.null {
+		return classNull
+	}
+
+	switch t.column {
+	case catalog.TypeInt, catalog.TypeSmallInt, catalog.TypeBigInt, catalog.TypeDouble, catalog.TypeDecimal:
+		return classNumeric
+	case catalog.TypeVarchar:
+		return classString
+	case catalog.TypeBoolean:
+		return classBoolean
+	case catalog.TypeTimestamp, catalog.TypeDate:
+		return classTemporal
+	case catalog.TypeUUID:
+		return classUUID
+	}
+
+	return classBlob
+}
+
+func (t valueType) isInteger() bool {
+	switch t.column {
+	case catalog.TypeInt, catalog.TypeSmallInt, catalog.TypeBigInt:
+		return !t.null
+	}
+
+	return false
+}
+
+// accepts reports whether a value of type t can stand where class is
+// expected; NULL fits anywhere.
+func (t valueType) accepts(class typeClass) bool {
+	return t.null || t.class() == class
+}
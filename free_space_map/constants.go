@@ -0,0 +1,13 @@
+package free_space_map
+
+const (
+	INVALID_PAGE_ID = uint32(0xFFFFFFFF)
+
+	NUM_ENTRIES_OFFSET  uint32 = 12
+	NEXT_PAGE_ID_OFFSET uint32 = 16
+	HEADER_SIZE         uint32 = 20
+
+	ENTRY_SIZE      uint32 = 6
+	FREE_SPACE_SIZE uint32 = 2
+	ENTRY_PAGE_SIZE uint32 = 4
+)
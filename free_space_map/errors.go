@@ -0,0 +1,7 @@
+package free_space_map
+
+import "errors"
+
+var (
+	ErrPageNotTracked = errors.New("page not tracked by free space map")
+)
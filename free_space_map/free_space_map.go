@@ -0,0 +1,180 @@
+package free_space_map
+
+func (fsm *FreeSpaceMap) Update(pageID uint32, freeSpace uint16) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	loc, exists := fsm.locations[pageID]
+	if !exists {
+		return fsm.append(pageID, freeSpace)
+	}
+
+	return fsm.setFreeSpace(loc, pageID, freeSpace)
+}
+
+func (fsm *FreeSpaceMap) Remove(pageID uint32) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	loc, exists := fsm.locations[pageID]
+	if !exists {
+		return nil
+	}
+
+	return fsm.setFreeSpace(loc, pageID, 0)
+}
+
+func (fsm *FreeSpaceMap) FindPage(spaceNeeded uint16) (uint32, bool, error) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	for _, summary := range fsm.pages {
+		if summary.maxFreeSpace < spaceNeeded {
+			continue
+		}
+
+		frame, err := fsm.bpm.FetchPage(summary.pageID)
+		if err != nil {
+			return 0, false, err
+		}
+
+		frame.RLatch()
+		numEntries := getNumEntries(frame.Data)
+		for i := uint32(0); i < numEntries; i++ {
+			heapPageID, freeSpace := getEntry(frame.Data, i)
+			if freeSpace >= spaceNeeded {
+				frame.RUnlatch()
+				fsm.bpm.UnpinPage(summary.pageID, false)
+				return heapPageID, true, nil
+			}
+		}
+		frame.RUnlatch()
+		fsm.bpm.UnpinPage(summary.pageID, false)
+	}
+
+	return 0, false, nil
+}
+
+func (fsm *FreeSpaceMap) GetFreeSpace(pageID uint32) (uint16, error) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	loc, exists := fsm.locations[pageID]
+	if !exists {
+		return 0, ErrPageNotTracked
+	}
+
+	frame, err := fsm.bpm.FetchPage(loc.pageID)
+	if err != nil {
+		return 0, err
+	}
+
+	frame.RLatch()
+	_, freeSpace := getEntry(frame.Data, loc.index)
+	frame.RUnlatch()
+	fsm.bpm.UnpinPage(loc.pageID, false)
+
+	return freeSpace, nil
+}
+
+func (fsm *FreeSpaceMap) Drop() error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	pageID := fsm.rootPageID
+	for pageID != INVALID_PAGE_ID {
+		frame, err := fsm.bpm.FetchPage(pageID)
+		if err != nil {
+			return err
+		}
+
+		frame.RLatch()
+		nextPageID := getNextPageID(frame.Data)
+		frame.RUnlatch()
+		fsm.bpm.UnpinPage(pageID, false)
+
+		err = fsm.bpm.DeletePage(pageID)
+		if err != nil {
+			return err
+		}
+
+		pageID = nextPageID
+	}
+
+	fsm.locations = make(map[uint32]location)
+	fsm.pages = nil
+	return nil
+}
+
+func (fsm *FreeSpaceMap) append(pageID uint32, freeSpace uint16) error {
+	frame, err := fsm.bpm.FetchPage(fsm.lastPageID)
+	if err != nil {
+		return err
+	}
+
+	frame.WLatch()
+	numEntries := getNumEntries(frame.Data)
+	if numEntries < pageCapacity(fsm.bpm.GetPageSize()) {
+		setEntry(frame.Data, numEntries, pageID, freeSpace)
+		setNumEntries(frame.Data, numEntries+1)
+		err = fsm.logPageImage(fsm.lastPageID, frame.Data)
+		frame.WUnlatch()
+		fsm.bpm.UnpinPage(fsm.lastPageID, true)
+		if err != nil {
+			return err
+		}
+
+		summary := len(fsm.pages) - 1
+		fsm.locations[pageID] = location{pageID: fsm.lastPageID, index: numEntries, summary: summary}
+		fsm.pages[summary].maxFreeSpace = max(fsm.pages[summary].maxFreeSpace, freeSpace)
+		return nil
+	}
+
+	newPageID, err := fsm.allocatePage()
+	if err != nil {
+		frame.WUnlatch()
+		fsm.bpm.UnpinPage(fsm.lastPageID, false)
+		return err
+	}
+
+	setNextPageID(frame.Data, newPageID)
+	err = fsm.logPageImage(fsm.lastPageID, frame.Data)
+	frame.WUnlatch()
+	fsm.bpm.UnpinPage(fsm.lastPageID, true)
+	if err != nil {
+		return err
+	}
+
+	fsm.lastPageID = newPageID
+	fsm.pages = append(fsm.pages, pageSummary{pageID: newPageID})
+	return fsm.append(pageID, freeSpace)
+}
+
+func (fsm *FreeSpaceMap) setFreeSpace(loc location, pageID uint32, freeSpace uint16) error {
+	frame, err := fsm.bpm.FetchPage(loc.pageID)
+	if err != nil {
+		return err
+	}
+
+	frame.WLatch()
+	_, oldFreeSpace := getEntry(frame.Data, loc.index)
+	if oldFreeSpace == freeSpace {
+		frame.WUnlatch()
+		fsm.bpm.UnpinPage(loc.pageID, false)
+		return nil
+	}
+
+	setEntry(frame.Data, loc.index, pageID, freeSpace)
+	err = fsm.logPageImage(loc.pageID, frame.Data)
+
+	summary := &fsm.pages[loc.summary]
+	if freeSpace > summary.maxFreeSpace {
+		summary.maxFreeSpace = freeSpace
+	} else if oldFreeSpace == summary.maxFreeSpace {
+		summary.maxFreeSpace = maxFreeSpace(frame.Data)
+	}
+	frame.WUnlatch()
+	fsm.bpm.UnpinPage(loc.pageID, true)
+
+	return err
+}
@@ -0,0 +1,184 @@
+package free_space_map
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeSpaceMap_UpdateAndFindPage(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	require.NoError(t, fsm.Update(10, 100))
+	require.NoError(t, fsm.Update(11, 2000))
+	require.NoError(t, fsm.Update(12, 500))
+
+	pageID, found, err := fsm.FindPage(400)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint32(11), pageID)
+
+	require.NoError(t, fsm.Update(11, 0))
+
+	pageID, found, err = fsm.FindPage(400)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint32(12), pageID)
+
+	_, found, err = fsm.FindPage(3000)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestFreeSpaceMap_GetFreeSpace(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	require.NoError(t, fsm.Update(7, 1234))
+
+	freeSpace, err := fsm.GetFreeSpace(7)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1234), freeSpace)
+
+	_, err = fsm.GetFreeSpace(8)
+	require.ErrorIs(t, err, ErrPageNotTracked)
+}
+
+func TestFreeSpaceMap_SpansMultiplePages(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	numEntries := int(pageCapacity(fsm.bpm.GetPageSize()))*2 + 10
+	for i := 0; i < numEntries; i++ {
+		require.NoError(t, fsm.Update(uint32(1000+i), 0))
+	}
+	require.NoError(t, fsm.Update(uint32(1000+numEntries-1), 800))
+
+	assert.NotEqual(t, fsm.GetRootPageID(), fsm.lastPageID)
+
+	pageID, found, err := fsm.FindPage(800)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint32(1000+numEntries-1), pageID)
+}
+
+func TestFreeSpaceMap_Reopen(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	numEntries := int(pageCapacity(fsm.bpm.GetPageSize())) + 5
+	for i := 0; i < numEntries; i++ {
+		require.NoError(t, fsm.Update(uint32(i), uint16(i)))
+	}
+
+	reopened, err := OpenFreeSpaceMap(fsm.bpm, fsm.GetRootPageID())
+	require.NoError(t, err)
+	assert.Equal(t, fsm.lastPageID, reopened.lastPageID)
+
+	freeSpace, err := reopened.GetFreeSpace(uint32(numEntries - 1))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(numEntries-1), freeSpace)
+
+	require.NoError(t, reopened.Update(uint32(numEntries), 3000))
+
+	pageID, found, err := reopened.FindPage(3000)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint32(numEntries), pageID)
+}
+
+func TestFreeSpaceMap_Remove(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	require.NoError(t, fsm.Update(3, 2000))
+	require.NoError(t, fsm.Remove(3))
+	require.NoError(t, fsm.Remove(4))
+
+	_, found, err := fsm.FindPage(100)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	reopened, err := OpenFreeSpaceMap(fsm.bpm, fsm.GetRootPageID())
+	require.NoError(t, err)
+
+	freeSpace, err := reopened.GetFreeSpace(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0), freeSpace)
+}
+
+func TestFreeSpaceMap_PageSummaries(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	capacity := int(pageCapacity(fsm.bpm.GetPageSize()))
+	for i := 0; i < capacity+10; i++ {
+		require.NoError(t, fsm.Update(uint32(i), 100))
+	}
+	require.NoError(t, fsm.Update(5, 500))
+	require.NoError(t, fsm.Update(uint32(capacity+5), 1000))
+
+	require.Len(t, fsm.pages, 2)
+	assert.Equal(t, uint16(500), fsm.pages[0].maxFreeSpace)
+	assert.Equal(t, uint16(1000), fsm.pages[1].maxFreeSpace)
+
+	tests := []struct {
+		name         string
+		pageID       uint32
+		freeSpace    uint16
+		spaceNeeded  uint16
+		expected     uint32
+		found        bool
+		firstMaxFree uint16
+	}{
+		{name: "first page has room", pageID: 5, freeSpace: 500, spaceNeeded: 400, expected: 5, found: true, firstMaxFree: 500},
+		{name: "only the second page has room", pageID: 5, freeSpace: 50, spaceNeeded: 400, expected: uint32(capacity + 5), found: true, firstMaxFree: 100},
+		{name: "no page has room", pageID: 5, freeSpace: 50, spaceNeeded: 2000, found: false, firstMaxFree: 100},
+		{name: "raised again", pageID: 7, freeSpace: 3000, spaceNeeded: 2000, expected: 7, found: true, firstMaxFree: 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, fsm.Update(tt.pageID, tt.freeSpace))
+			assert.Equal(t, tt.firstMaxFree, fsm.pages[0].maxFreeSpace)
+
+			pageID, found, err := fsm.FindPage(tt.spaceNeeded)
+			require.NoError(t, err)
+			require.Equal(t, tt.found, found)
+			if found {
+				assert.Equal(t, tt.expected, pageID)
+			}
+		})
+	}
+
+	reopened, err := OpenFreeSpaceMap(fsm.bpm, fsm.GetRootPageID())
+	require.NoError(t, err)
+	assert.Equal(t, fsm.pages, reopened.pages)
+}
+
+func TestFreeSpaceMap_UpdatesSurviveCrash(t *testing.T) {
+	fsm, cleanup := newTestFreeSpaceMap(t, 10)
+	defer cleanup()
+
+	capacity := int(pageCapacity(fsm.bpm.GetPageSize()))
+	for i := 0; i < capacity+10; i++ {
+		require.NoError(t, fsm.Update(uint32(i), uint16(i)))
+	}
+	require.NoError(t, fsm.bpm.FlushAllPages())
+
+	require.NoError(t, fsm.Update(3, 2500))
+	require.NoError(t, fsm.Update(uint32(capacity+20), 1500))
+
+	reopened, closeReopened := reopenTestFreeSpaceMap(t, fsm)
+	defer closeReopened()
+
+	freeSpace, err := reopened.GetFreeSpace(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2500), freeSpace)
+
+	freeSpace, err = reopened.GetFreeSpace(uint32(capacity + 20))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1500), freeSpace)
+}
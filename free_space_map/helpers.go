@@ -0,0 +1,100 @@
+package free_space_map
+
+import (
+	"encoding/binary"
+
+	"gobase/log_manager"
+	"gobase/shared"
+)
+
+func (fsm *FreeSpaceMap) GetRootPageID() uint32 {
+	return fsm.rootPageID
+}
+
+func (fsm *FreeSpaceMap) allocatePage() (uint32, error) {
+	pageID, frame, err := fsm.bpm.NewPage()
+	if err != nil {
+		return 0, err
+	}
+
+	frame.WLatch()
+	setNumEntries(frame.Data, 0)
+	setNextPageID(frame.Data, INVALID_PAGE_ID)
+	err = fsm.logPageImage(pageID, frame.Data)
+	frame.WUnlatch()
+	fsm.bpm.UnpinPage(pageID, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return pageID, nil
+}
+
+func (fsm *FreeSpaceMap) logPageImage(pageID uint32, data []byte) error {
+	logManager := fsm.bpm.GetLogManager()
+	if logManager == nil {
+		return nil
+	}
+
+	lsn, err := logManager.AppendRecord(&log_manager.LogRecord{
+		Type:   log_manager.RecordPageImage,
+		PageID: pageID,
+		Data:   data,
+	})
+	if err != nil {
+		return err
+	}
+
+	shared.SetPageLSN(data, lsn)
+	return nil
+}
+
+func pageCapacity(pageSize uint32) uint32 {
+	return (pageSize - HEADER_SIZE) / ENTRY_SIZE
+}
+
+func maxFreeSpace(data []byte) uint16 {
+	var maxFree uint16
+
+	numEntries := getNumEntries(data)
+	for i := uint32(0); i < numEntries; i++ {
+		_, freeSpace := getEntry(data, i)
+		maxFree = max(maxFree, freeSpace)
+	}
+
+	return maxFree
+}
+
+func getNumEntries(data []byte) uint32 {
+	numEntries := uint32(binary.LittleEndian.Uint16(data[NUM_ENTRIES_OFFSET:]))
+	return min(numEntries, pageCapacity(uint32(len(data))))
+}
+
+func setNumEntries(data []byte, numEntries uint32) {
+	binary.LittleEndian.PutUint16(data[NUM_ENTRIES_OFFSET:], uint16(numEntries))
+}
+
+func getNextPageID(data []byte) uint32 {
+	return binary.LittleEndian.Uint32(data[NEXT_PAGE_ID_OFFSET:])
+}
+
+func setNextPageID(data []byte, pageID uint32) {
+	binary.LittleEndian.PutUint32(data[NEXT_PAGE_ID_OFFSET:], pageID)
+}
+
+func entryOffset(index uint32) uint32 {
+	return HEADER_SIZE + index*ENTRY_SIZE
+}
+
+func getEntry(data []byte, index uint32) (pageID uint32, freeSpace uint16) {
+	offset := entryOffset(index)
+	pageID = binary.LittleEndian.Uint32(data[offset:])
+	freeSpace = binary.LittleEndian.Uint16(data[offset+ENTRY_PAGE_SIZE:])
+	return pageID, freeSpace
+}
+
+func setEntry(data []byte, index uint32, pageID uint32, freeSpace uint16) {
+	offset := entryOffset(index)
+	binary.LittleEndian.PutUint32(data[offset:], pageID)
+	binary.LittleEndian.PutUint16(data[offset+ENTRY_PAGE_SIZE:], freeSpace)
+}
@@ -0,0 +1,80 @@
+package free_space_map
+
+import (
+	"sync"
+
+	"gobase/buffer_pool_manager"
+)
+
+type FreeSpaceMap struct {
+	mu         sync.Mutex
+	bpm        *buffer_pool_manager.BufferPoolManager
+	rootPageID uint32
+	lastPageID uint32
+	locations  map[uint32]location
+	pages      []pageSummary
+}
+
+type location struct {
+	pageID  uint32
+	index   uint32
+	summary int
+}
+
+// pageSummary keeps the largest free space recorded on an FSM page, so that
+// FindPage only reads the pages that can satisfy a request.
+type pageSummary struct {
+	pageID       uint32
+	maxFreeSpace uint16
+}
+
+func NewFreeSpaceMap(bpm *buffer_pool_manager.BufferPoolManager) (*FreeSpaceMap, error) {
+	fsm := &FreeSpaceMap{
+		bpm:       bpm,
+		locations: make(map[uint32]location),
+	}
+
+	rootPageID, err := fsm.allocatePage()
+	if err != nil {
+		return nil, err
+	}
+
+	fsm.rootPageID = rootPageID
+	fsm.lastPageID = rootPageID
+	fsm.pages = []pageSummary{{pageID: rootPageID}}
+
+	return fsm, nil
+}
+
+func OpenFreeSpaceMap(bpm *buffer_pool_manager.BufferPoolManager, rootPageID uint32) (*FreeSpaceMap, error) {
+	fsm := &FreeSpaceMap{
+		bpm:        bpm,
+		rootPageID: rootPageID,
+		lastPageID: rootPageID,
+		locations:  make(map[uint32]location),
+	}
+
+	pageID := rootPageID
+	for pageID != INVALID_PAGE_ID {
+		frame, err := bpm.FetchPage(pageID)
+		if err != nil {
+			return nil, err
+		}
+
+		frame.RLatch()
+		numEntries := getNumEntries(frame.Data)
+		for i := uint32(0); i < numEntries; i++ {
+			heapPageID, _ := getEntry(frame.Data, i)
+			fsm.locations[heapPageID] = location{pageID: pageID, index: i, summary: len(fsm.pages)}
+		}
+		fsm.pages = append(fsm.pages, pageSummary{pageID: pageID, maxFreeSpace: maxFreeSpace(frame.Data)})
+		nextPageID := getNextPageID(frame.Data)
+		frame.RUnlatch()
+		bpm.UnpinPage(pageID, false)
+
+		fsm.lastPageID = pageID
+		pageID = nextPageID
+	}
+
+	return fsm, nil
+}
@@ -0,0 +1,51 @@
+package free_space_map
+
+import (
+	"os"
+	"testing"
+
+	"gobase/buffer_pool_manager"
+	"gobase/disk_manager"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFreeSpaceMap(t *testing.T, poolSize int) (*FreeSpaceMap, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "free_space_map_test")
+	require.NoError(t, err)
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, poolSize)
+
+	fsm, err := NewFreeSpaceMap(bpm)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return fsm, cleanup
+}
+
+// reopenTestFreeSpaceMap simulates a crash: dirty pages still in the buffer
+// pool are lost and the map is read back from what recovery restores.
+func reopenTestFreeSpaceMap(t *testing.T, fsm *FreeSpaceMap) (*FreeSpaceMap, func()) {
+	t.Helper()
+
+	filePath := fsm.bpm.GetDiskManager().File.Name()
+	fsm.bpm.GetDiskManager().Close()
+
+	dm, err := disk_manager.NewDiskManager(filePath)
+	require.NoError(t, err)
+
+	reopened, err := OpenFreeSpaceMap(buffer_pool_manager.NewBufferPoolManager(dm, 10), fsm.GetRootPageID())
+	require.NoError(t, err)
+
+	return reopened, func() { dm.Close() }
+}
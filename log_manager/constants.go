@@ -0,0 +1,25 @@
+package log_manager
+
+type RecordType uint8
+
+const (
+	RecordBegin RecordType = iota + 1
+	RecordCommit
+	RecordAbort
+	RecordInsertTuple
+	RecordDeleteTuple
+	RecordNewPage
+	RecordPageImage
+	RecordCompensation
+	RecordCheckpoint
+	RecordUpdateTuple
+)
+
+const (
+	INVALID_LSN    = uint64(0)
+	INVALID_TXN_ID = uint64(0)
+
+	RECORD_HEADER_SIZE uint32 = 55
+	UPDATE_LENGTH_SIZE uint32 = 4
+	LOG_BUFFER_SIZE    int    = 64 * 1024
+)
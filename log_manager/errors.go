@@ -0,0 +1,10 @@
+package log_manager
+
+import "errors"
+
+var (
+	ErrOpenLogFailed  = errors.New("failed to open log file")
+	ErrLogWriteFailed = errors.New("log write failed")
+	ErrLogReadFailed  = errors.New("log read failed")
+	ErrCorruptRecord  = errors.New("corrupt log record")
+)
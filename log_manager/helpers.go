@@ -0,0 +1,131 @@
+package log_manager
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+func (lm *LogManager) GetNextLSN() uint64 {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.nextLSN
+}
+
+func (lm *LogManager) GetFlushedLSN() uint64 {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.flushedLSN
+}
+
+func (rec *LogRecord) IsUndoable() bool {
+	return rec.Type == RecordInsertTuple || rec.Type == RecordDeleteTuple || rec.Type == RecordUpdateTuple
+}
+
+func EncodeUpdate(oldTuple, newTuple []byte) []byte {
+	data := make([]byte, UPDATE_LENGTH_SIZE, int(UPDATE_LENGTH_SIZE)+len(oldTuple)+len(newTuple))
+	binary.LittleEndian.PutUint32(data, uint32(len(oldTuple)))
+	data = append(data, oldTuple...)
+	return append(data, newTuple...)
+}
+
+func DecodeUpdate(data []byte) (oldTuple, newTuple []byte, err error) {
+	if uint32(len(data)) < UPDATE_LENGTH_SIZE {
+		return nil, nil, ErrCorruptRecord
+	}
+
+	oldLength := binary.LittleEndian.Uint32(data)
+	if oldLength > uint32(len(data))-UPDATE_LENGTH_SIZE {
+		return nil, nil, ErrCorruptRecord
+	}
+
+	oldEnd := UPDATE_LENGTH_SIZE + oldLength
+	return data[UPDATE_LENGTH_SIZE:oldEnd], data[oldEnd:], nil
+}
+
+func encodeRecord(rec *LogRecord) []byte {
+	size := RECORD_HEADER_SIZE + uint32(len(rec.Data))
+	data := make([]byte, size)
+
+	binary.LittleEndian.PutUint32(data[0:], size)
+	binary.LittleEndian.PutUint64(data[8:], rec.LSN)
+	binary.LittleEndian.PutUint64(data[16:], rec.PrevLSN)
+	binary.LittleEndian.PutUint64(data[24:], rec.TxnID)
+	data[32] = byte(rec.Type)
+	binary.LittleEndian.PutUint32(data[33:], rec.PageID)
+	binary.LittleEndian.PutUint16(data[37:], rec.SlotID)
+	binary.LittleEndian.PutUint32(data[39:], rec.PrevPageID)
+	binary.LittleEndian.PutUint64(data[43:], rec.UndoNextLSN)
+	binary.LittleEndian.PutUint32(data[51:], uint32(len(rec.Data)))
+	copy(data[RECORD_HEADER_SIZE:], rec.Data)
+
+	binary.LittleEndian.PutUint32(data[4:], crc32.ChecksumIEEE(data[8:]))
+
+	return data
+}
+
+func decodeRecord(data []byte) (*LogRecord, error) {
+	if crc32.ChecksumIEEE(data[8:]) != binary.LittleEndian.Uint32(data[4:]) {
+		return nil, ErrCorruptRecord
+	}
+
+	dataLength := binary.LittleEndian.Uint32(data[51:])
+	if RECORD_HEADER_SIZE+dataLength != uint32(len(data)) {
+		return nil, ErrCorruptRecord
+	}
+
+	rec := &LogRecord{
+		LSN:         binary.LittleEndian.Uint64(data[8:]),
+		PrevLSN:     binary.LittleEndian.Uint64(data[16:]),
+		TxnID:       binary.LittleEndian.Uint64(data[24:]),
+		Type:        RecordType(data[32]),
+		PageID:      binary.LittleEndian.Uint32(data[33:]),
+		SlotID:      binary.LittleEndian.Uint16(data[37:]),
+		PrevPageID:  binary.LittleEndian.Uint32(data[39:]),
+		UndoNextLSN: binary.LittleEndian.Uint64(data[43:]),
+		Data:        make([]byte, dataLength),
+	}
+	copy(rec.Data, data[RECORD_HEADER_SIZE:])
+
+	return rec, nil
+}
+
+func (lm *LogManager) readRecords() ([]*LogRecord, int64, error) {
+	records := []*LogRecord{}
+	offset := int64(0)
+	sizeBuffer := make([]byte, 4)
+
+	for {
+		_, err := lm.file.ReadAt(sizeBuffer, offset)
+		if err == io.EOF {
+			return records, offset, nil
+		}
+		if err != nil {
+			return nil, 0, ErrLogReadFailed
+		}
+
+		size := binary.LittleEndian.Uint32(sizeBuffer)
+		if size < RECORD_HEADER_SIZE {
+			return records, offset, nil
+		}
+
+		data := make([]byte, size)
+		_, err = lm.file.ReadAt(data, offset)
+		if err == io.EOF {
+			return records, offset, nil
+		}
+		if err != nil {
+			return nil, 0, ErrLogReadFailed
+		}
+
+		rec, err := decodeRecord(data)
+		if err != nil {
+			return records, offset, nil
+		}
+
+		records = append(records, rec)
+		offset += int64(size)
+	}
+}
@@ -0,0 +1,99 @@
+package log_manager
+
+func (lm *LogManager) AppendRecord(rec *LogRecord) (uint64, error) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.appendRecord(rec)
+}
+
+func (lm *LogManager) Flush(lsn uint64) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	return lm.flush(lsn)
+}
+
+func (lm *LogManager) ReadAll() ([]*LogRecord, error) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	err := lm.flush(lm.nextLSN - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	records, _, err := lm.readRecords()
+	return records, err
+}
+
+func (lm *LogManager) Checkpoint() error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	err := lm.file.Truncate(0)
+	if err != nil {
+		return ErrLogWriteFailed
+	}
+
+	lm.fileSize = 0
+	lm.buffer = lm.buffer[:0]
+	lm.flushedLSN = lm.nextLSN - 1
+
+	lsn, err := lm.appendRecord(&LogRecord{Type: RecordCheckpoint})
+	if err != nil {
+		return err
+	}
+
+	return lm.flush(lsn)
+}
+
+func (lm *LogManager) Close() error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	err := lm.flush(lm.nextLSN - 1)
+	if err != nil {
+		return err
+	}
+
+	return lm.file.Close()
+}
+
+func (lm *LogManager) appendRecord(rec *LogRecord) (uint64, error) {
+	rec.LSN = lm.nextLSN
+	lm.nextLSN++
+
+	lm.buffer = append(lm.buffer, encodeRecord(rec)...)
+
+	if len(lm.buffer) >= LOG_BUFFER_SIZE {
+		err := lm.flush(rec.LSN)
+		if err != nil {
+			return INVALID_LSN, err
+		}
+	}
+
+	return rec.LSN, nil
+}
+
+func (lm *LogManager) flush(lsn uint64) error {
+	if lsn <= lm.flushedLSN || len(lm.buffer) == 0 {
+		return nil
+	}
+
+	_, err := lm.file.WriteAt(lm.buffer, lm.fileSize)
+	if err != nil {
+		return ErrLogWriteFailed
+	}
+
+	err = lm.file.Sync()
+	if err != nil {
+		return ErrLogWriteFailed
+	}
+
+	lm.fileSize += int64(len(lm.buffer))
+	lm.buffer = lm.buffer[:0]
+	lm.flushedLSN = lm.nextLSN - 1
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package log_manager
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogManager(t *testing.T) (*LogManager, string, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "log_manager_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	lm, err := NewLogManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	cleanup := func() {
+		lm.file.Close()
+		os.Remove(tmpFile.Name())
+	}
+
+	return lm, tmpFile.Name(), cleanup
+}
+
+func TestAppendRecord(t *testing.T) {
+	lm, _, cleanup := newTestLogManager(t)
+	defer cleanup()
+
+	lsn1, err := lm.AppendRecord(&LogRecord{Type: RecordBegin, TxnID: 1})
+	require.NoError(t, err)
+	lsn2, err := lm.AppendRecord(&LogRecord{Type: RecordCommit, TxnID: 1, PrevLSN: lsn1})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), lsn1)
+	assert.Equal(t, uint64(2), lsn2)
+	assert.Equal(t, uint64(3), lm.GetNextLSN())
+	assert.Equal(t, INVALID_LSN, lm.GetFlushedLSN())
+}
+
+func TestFlush(t *testing.T) {
+	lm, filePath, cleanup := newTestLogManager(t)
+	defer cleanup()
+
+	lsn, err := lm.AppendRecord(&LogRecord{Type: RecordInsertTuple, PageID: 3, SlotID: 2, Data: []byte("data")})
+	require.NoError(t, err)
+
+	require.NoError(t, lm.Flush(lsn))
+	assert.Equal(t, lsn, lm.GetFlushedLSN())
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(RECORD_HEADER_SIZE)+4, info.Size())
+}
+
+func TestReadAll(t *testing.T) {
+	lm, _, cleanup := newTestLogManager(t)
+	defer cleanup()
+
+	expected := &LogRecord{
+		PrevLSN:     7,
+		TxnID:       2,
+		Type:        RecordDeleteTuple,
+		PageID:      3,
+		SlotID:      4,
+		PrevPageID:  5,
+		UndoNextLSN: 6,
+		Data:        []byte("deleted tuple"),
+	}
+	_, err := lm.AppendRecord(expected)
+	require.NoError(t, err)
+
+	records, err := lm.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, expected, records[0])
+}
+
+func TestNewLogManager_ResumesLSN(t *testing.T) {
+	lm, filePath, cleanup := newTestLogManager(t)
+	defer cleanup()
+
+	for i := 0; i < 3; i++ {
+		_, err := lm.AppendRecord(&LogRecord{Type: RecordBegin, TxnID: uint64(i + 1)})
+		require.NoError(t, err)
+	}
+	require.NoError(t, lm.Close())
+
+	reopened, err := NewLogManager(filePath)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	assert.Equal(t, uint64(4), reopened.GetNextLSN())
+	assert.Equal(t, uint64(3), reopened.GetFlushedLSN())
+}
+
+func TestNewLogManager_IgnoresTornRecord(t *testing.T) {
+	lm, filePath, cleanup := newTestLogManager(t)
+	defer cleanup()
+
+	_, err := lm.AppendRecord(&LogRecord{Type: RecordBegin, TxnID: 1})
+	require.NoError(t, err)
+	require.NoError(t, lm.Close())
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	torn := encodeRecord(&LogRecord{LSN: 2, Type: RecordCommit, TxnID: 1})
+	_, err = file.Write(torn[:len(torn)-3])
+	require.NoError(t, err)
+	file.Close()
+
+	reopened, err := NewLogManager(filePath)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	records, err := reopened.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, uint64(2), reopened.GetNextLSN())
+}
+
+func TestCheckpoint(t *testing.T) {
+	lm, _, cleanup := newTestLogManager(t)
+	defer cleanup()
+
+	for i := 0; i < 3; i++ {
+		_, err := lm.AppendRecord(&LogRecord{Type: RecordBegin, TxnID: uint64(i + 1)})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, lm.Checkpoint())
+
+	records, err := lm.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, RecordCheckpoint, records[0].Type)
+	assert.Equal(t, uint64(4), records[0].LSN)
+	assert.Equal(t, uint64(5), lm.GetNextLSN())
+}
+
+func TestEncodeUpdate(t *testing.T) {
+	data := EncodeUpdate([]byte("old"), []byte("new tuple"))
+
+	oldTuple, newTuple, err := DecodeUpdate(data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old"), oldTuple)
+	assert.Equal(t, []byte("new tuple"), newTuple)
+
+	_, _, err = DecodeUpdate(data[:2])
+	require.ErrorIs(t, err, ErrCorruptRecord)
+}
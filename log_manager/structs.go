@@ -0,0 +1,59 @@
+package log_manager
+
+import (
+	"os"
+	"sync"
+)
+
+type LogRecord struct {
+	LSN         uint64
+	PrevLSN     uint64
+	TxnID       uint64
+	Type        RecordType
+	PageID      uint32
+	SlotID      uint16
+	PrevPageID  uint32
+	UndoNextLSN uint64
+	Data        []byte
+}
+
+type LogManager struct {
+	mu         sync.Mutex
+	file       *os.File
+	fileSize   int64
+	buffer     []byte
+	nextLSN    uint64
+	flushedLSN uint64
+}
+
+func NewLogManager(filePath string) (*LogManager, error) {
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, ErrOpenLogFailed
+	}
+
+	lm := &LogManager{
+		file:    file,
+		nextLSN: INVALID_LSN + 1,
+	}
+
+	records, validSize, err := lm.readRecords()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if len(records) > 0 {
+		lm.nextLSN = records[len(records)-1].LSN + 1
+	}
+	lm.flushedLSN = lm.nextLSN - 1
+
+	err = file.Truncate(validSize)
+	if err != nil {
+		file.Close()
+		return nil, ErrLogWriteFailed
+	}
+	lm.fileSize = validSize
+
+	return lm, nil
+}
@@ -3,19 +3,26 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"gobase/buffer_pool_manager"
 	"gobase/catalog"
 	"gobase/disk_manager"
+	"gobase/executor"
+	"gobase/expression"
 	"gobase/shared"
 	"gobase/slotted_page"
+	"gobase/sql"
+	"gobase/system_catalog"
 	"gobase/table"
 	"gobase/table_heap"
+	"gobase/transaction_manager"
 )
 
 func main() {
 	// Supprimer le fichier de test s'il existe
-	os.Remove("test.db")
+	removeTestDatabase()
 
 	fmt.Println("=== TEST DISK MANAGER ===")
 	testDiskManager()
@@ -38,11 +45,52 @@ func main() {
 	fmt.Println("\n=== TEST LINKED PAGES ===")
 	testLinkedPages()
 
+	fmt.Println("\n=== TEST INDEX ===")
+	testIndexes()
+
+	fmt.Println("\n=== TEST TRANSACTIONS ===")
+	testTransactions()
+
+	fmt.Println("\n=== TEST UPDATE ===")
+	testUpdate()
+
+	fmt.Println("\n=== TEST NULL ===")
+	testNull()
+
+	fmt.Println("\n=== TEST TYPES ===")
+	testTypes()
+
+	fmt.Println("\n=== TEST SYSTEM CATALOG ===")
+	testSystemCatalog()
+
+	fmt.Println("\n=== TEST OVERFLOW ===")
+	testOverflow()
+
+	fmt.Println("\n=== TEST PAGE SIZE ===")
+	testPageSize()
+
+	fmt.Println("\n=== TEST SQL PARSER ===")
+	testSQLParser()
+
+	fmt.Println("\n=== TEST EXECUTOR ===")
+	testExecutor()
+
+	fmt.Println("\n=== TEST JOINS ===")
+	testJoins()
+
+	fmt.Println("\n=== TEST EXPRESSIONS ===")
+	testExpressions()
+
 	// Nettoyage
-	os.Remove("test.db")
+	removeTestDatabase()
 	fmt.Println("\nTous les tests sont terminés!")
 }
 
+func removeTestDatabase() {
+	os.Remove("test.db")
+	os.Remove(disk_manager.LogFilePath("test.db"))
+}
+
 func testDiskManager() {
 	dm, err := disk_manager.NewDiskManager("test.db")
 	if err != nil {
@@ -58,8 +106,8 @@ func testDiskManager() {
 	}
 	fmt.Printf("2. Page allouée avec ID: %d\n", pageID)
 
-	data := make([]byte, shared.PAGE_SIZE)
-	copy(data, []byte("Hello DiskManager!"))
+	data := make([]byte, shared.DEFAULT_PAGE_SIZE)
+	copy(data[shared.PAGE_HEADER_SIZE:], []byte("Hello DiskManager!"))
 	err = dm.WritePage(pageID, data)
 	if err != nil {
 		fmt.Printf("ERREUR écriture: %v\n", err)
@@ -82,12 +130,12 @@ func testDiskManager() {
 		return
 	}
 
-	fmt.Printf("5. Données relues après réouverture: %s\n", string(readData[:18]))
+	fmt.Printf("5. Données relues après réouverture: %s\n", string(readData[shared.PAGE_HEADER_SIZE:shared.PAGE_HEADER_SIZE+18]))
 	dm2.Close()
 }
 
 func testBufferPoolManager() {
-	os.Remove("test.db")
+	removeTestDatabase()
 
 	dm, _ := disk_manager.NewDiskManager("test.db")
 	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 3)
@@ -98,7 +146,7 @@ func testBufferPoolManager() {
 		fmt.Printf("ERREUR NewPage: %v\n", err)
 		return
 	}
-	copy(page1.Data, []byte("Page 1 - Bonjour!"))
+	copy(page1.Data[shared.PAGE_HEADER_SIZE:], []byte("Page 1 - Bonjour!"))
 	fmt.Printf("2. Nouvelle page créée avec ID: %d\n", pageID1)
 
 	bpm.UnpinPage(pageID1, true)
@@ -108,13 +156,13 @@ func testBufferPoolManager() {
 	dm.Close()
 	dm2, _ := disk_manager.NewDiskManager("test.db")
 	readData, _ := dm2.ReadPage(pageID1)
-	fmt.Printf("4. Vérification persistance: %s\n", string(readData[:17]))
+	fmt.Printf("4. Vérification persistance: %s\n", string(readData[shared.PAGE_HEADER_SIZE:shared.PAGE_HEADER_SIZE+17]))
 	dm2.Close()
 }
 
 func testSlottedPageStandalone() {
 	// Test SlottedPage sans BufferPool (en mémoire seulement)
-	sp := slotted_page.NewSlottedPage()
+	sp := slotted_page.NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 	fmt.Printf("1. SlottedPage créée, espace libre: %d octets\n", sp.GetFreeSpace())
 
 	// Insérer des tuples
@@ -160,7 +208,7 @@ func testSlottedPageStandalone() {
 
 func testIntegration() {
 	// Test complet : SlottedPage + BufferPool + DiskManager
-	os.Remove("test.db")
+	removeTestDatabase()
 
 	// === PHASE 1 : Créer et remplir une page ===
 	fmt.Println("--- Phase 1 : Création et insertion ---")
@@ -242,7 +290,11 @@ func testCatalog() {
 
 	// 3. Encoder un tuple
 	values := []any{1, "Alice", 30, true}
-	encoded := catalog.EncodeTuple(schema, values)
+	encoded, err := catalog.EncodeTuple(schema, values)
+	if err != nil {
+		fmt.Printf("ERREUR EncodeTuple: %v\n", err)
+		return
+	}
 	fmt.Printf("3. Tuple encodé: %v (%d bytes)\n", encoded, len(encoded))
 
 	// 4. Décoder le tuple
@@ -256,10 +308,20 @@ func testCatalog() {
 	} else {
 		fmt.Println("5. ERREUR: valeurs différentes après decode")
 	}
+
+	// 6. Encoder des valeurs invalides
+	_, err = catalog.EncodeTuple(schema, []any{1, "Alice", 40000, true})
+	fmt.Printf("6. SMALLINT hors limites: %v (attendu)\n", err)
+
+	_, err = catalog.EncodeTuple(schema, []any{int64(1), "Alice", 30, true})
+	fmt.Printf("7. Mauvais type: %v (attendu)\n", err)
+
+	_, err = catalog.EncodeTuple(schema, []any{1, "Alice"})
+	fmt.Printf("8. Nombre de valeurs incorrect: %v (attendu)\n", err)
 }
 
 func testTable() {
-	os.Remove("test.db")
+	removeTestDatabase()
 
 	// 1. Setup: DiskManager + BufferPoolManager + TableHeap
 	dm, err := disk_manager.NewDiskManager("test.db")
@@ -284,25 +346,29 @@ func testTable() {
 	})
 
 	// 3. Créer la table
-	usersTable := table.NewTable("users", schema, heap)
+	usersTable, err := table.NewTable("users", schema, heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
 	fmt.Println("2. Table 'users' créée avec schema (id, name, age)")
 
 	// 4. Insérer des données
-	rid1, err := usersTable.Insert(1, "Alice", 30)
+	rid1, err := usersTable.Insert(nil, 1, "Alice", 30)
 	if err != nil {
 		fmt.Printf("ERREUR Insert: %v\n", err)
 		return
 	}
 	fmt.Printf("3. Inséré: (1, 'Alice', 30) → RID(%d, %d)\n", rid1.GetPageID(), rid1.GetSlotID())
 
-	rid2, err := usersTable.Insert(2, "Bob", 25)
+	rid2, err := usersTable.Insert(nil, 2, "Bob", 25)
 	if err != nil {
 		fmt.Printf("ERREUR Insert: %v\n", err)
 		return
 	}
 	fmt.Printf("4. Inséré: (2, 'Bob', 25) → RID(%d, %d)\n", rid2.GetPageID(), rid2.GetSlotID())
 
-	rid3, err := usersTable.Insert(3, "Charlie", 35)
+	rid3, err := usersTable.Insert(nil, 3, "Charlie", 35)
 	if err != nil {
 		fmt.Printf("ERREUR Insert: %v\n", err)
 		return
@@ -331,7 +397,7 @@ func testTable() {
 	}
 
 	// 7. Supprimer une ligne
-	err = usersTable.Delete(*rid2)
+	err = usersTable.Delete(nil, *rid2)
 	if err != nil {
 		fmt.Printf("ERREUR Delete: %v\n", err)
 		return
@@ -356,7 +422,7 @@ func testTable() {
 }
 
 func testLinkedPages() {
-	os.Remove("test.db")
+	removeTestDatabase()
 
 	dm, err := disk_manager.NewDiskManager("test.db")
 	if err != nil {
@@ -376,7 +442,11 @@ func testLinkedPages() {
 		{Name: "data", Type: catalog.TypeVarchar, Size: 200},
 	})
 
-	t := table.NewTable("test_linked", schema, heap)
+	t, err := table.NewTable("test_linked", schema, heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
 	fmt.Println("1. Table créée avec schema (id INT, data VARCHAR(200))")
 
 	// Insérer suffisamment de tuples pour remplir plusieurs pages
@@ -386,7 +456,7 @@ func testLinkedPages() {
 
 	for i := 0; i < numRows; i++ {
 		data := fmt.Sprintf("Row-%d-padding-to-make-this-tuple-larger-%s", i, "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX")
-		_, err := t.Insert(i, data)
+		_, err := t.Insert(nil, i, data)
 		if err != nil {
 			fmt.Printf("ERREUR Insert row %d: %v\n", i, err)
 			return
@@ -415,3 +485,682 @@ func testLinkedPages() {
 
 	dm.Close()
 }
+
+func testIndexes() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	heap, err := table_heap.NewTableHeap(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR TableHeap: %v\n", err)
+		return
+	}
+
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "age", Type: catalog.TypeSmallInt},
+	})
+
+	usersTable, err := table.NewTable("users", schema, heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
+	usersTable.Insert(nil, 1, "Alice", 30)
+	usersTable.Insert(nil, 2, "Bob", 25)
+	fmt.Println("1. Table 'users' créée avec 2 lignes")
+
+	// L'index est rempli à partir des lignes déjà présentes
+	err = usersTable.CreateIndex("users_age", "age")
+	if err != nil {
+		fmt.Printf("ERREUR CreateIndex: %v\n", err)
+		return
+	}
+	fmt.Println("2. Index 'users_age' créé sur la colonne age")
+
+	rid, _ := usersTable.Insert(nil, 3, "Charlie", 30)
+	fmt.Println("3. Inséré: (3, 'Charlie', 30)")
+
+	rows, err := usersTable.LookupByIndex("users_age", 30)
+	if err != nil {
+		fmt.Printf("ERREUR LookupByIndex: %v\n", err)
+		return
+	}
+	fmt.Printf("4. Lignes avec age=30: %v\n", rows)
+
+	usersTable.Delete(nil, *rid)
+	rows, _ = usersTable.LookupByIndex("users_age", 30)
+	fmt.Printf("5. Lignes avec age=30 après suppression de Charlie: %v\n", rows)
+
+	_, err = usersTable.Insert(nil, 1, "Alice bis", 40)
+	fmt.Printf("6. Insertion d'un id déjà existant: %v (attendu)\n", err)
+
+	dm.Close()
+}
+
+func testTransactions() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+	tm := transaction_manager.NewTransactionManager(dm.Log)
+
+	heap, err := table_heap.NewTableHeap(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR TableHeap: %v\n", err)
+		return
+	}
+
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+	})
+
+	usersTable, err := table.NewTable("users", schema, heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
+
+	// Transaction validée
+	txn1, _ := tm.Begin()
+	usersTable.Insert(txn1, 1, "Alice")
+	usersTable.Insert(txn1, 2, "Bob")
+	tm.Commit(txn1)
+	fmt.Println("1. Transaction 1 validée: Alice et Bob insérés")
+
+	// Transaction annulée: l'insertion et la suppression sont défaites
+	txn2, _ := tm.Begin()
+	usersTable.Insert(txn2, 3, "Charlie")
+	rows, _ := usersTable.LookupByIndex("users_pkey", 1)
+	rid, _ := usersTable.Insert(txn2, 4, "Dave")
+	usersTable.Delete(txn2, *rid)
+	fmt.Printf("2. Transaction 2 en cours, lookup id=1: %v\n", rows)
+	tm.Abort(txn2)
+	fmt.Println("3. Transaction 2 annulée")
+
+	fmt.Println("4. Scan après annulation:")
+	scanner := usersTable.Scan()
+	for {
+		values, ok := scanner.Next()
+		if !ok {
+			break
+		}
+		fmt.Printf("   - id=%v, name=%v\n", values[0], values[1])
+	}
+
+	rows, _ = usersTable.LookupByIndex("users_pkey", 3)
+	fmt.Printf("5. Lookup id=3 après annulation: %v\n", rows)
+
+	dm.Close()
+}
+
+func testUpdate() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	heap, err := table_heap.NewTableHeap(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR TableHeap: %v\n", err)
+		return
+	}
+
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "bio", Type: catalog.TypeVarchar, Size: 2000},
+	})
+
+	usersTable, err := table.NewTable("users", schema, heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
+
+	rid, _ := usersTable.Insert(nil, 1, "Alice")
+	usersTable.Insert(nil, 2, strings.Repeat("b", 1900))
+	usersTable.Insert(nil, 3, strings.Repeat("c", 1900))
+	fmt.Printf("1. Inséré: (1, 'Alice') → RID(%d, %d)\n", rid.GetPageID(), rid.GetSlotID())
+
+	usersTable.Update(nil, *rid, 1, "Alicia")
+	row, _ := usersTable.GetByRID(*rid)
+	fmt.Printf("2. Mise à jour sur place: %v\n", row)
+
+	// La page est pleine: la ligne est déplacée avec un pointeur de redirection
+	usersTable.Update(nil, *rid, 1, strings.Repeat("a", 1000))
+	row, _ = usersTable.GetByRID(*rid)
+	fmt.Printf("3. Mise à jour avec déplacement, même RID(%d, %d): %d caractères\n", rid.GetPageID(), rid.GetSlotID(), len(row[1].(string)))
+
+	err = usersTable.Update(nil, *rid, 2, "Alice")
+	fmt.Printf("4. Mise à jour vers un id existant: %v (attendu)\n", err)
+
+	rows, _ := usersTable.LookupByIndex("users_pkey", 1)
+	fmt.Printf("5. Lookup id=1: %d ligne(s)\n", len(rows))
+
+	dm.Close()
+}
+
+func testNull() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	heap, err := table_heap.NewTableHeap(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR TableHeap: %v\n", err)
+		return
+	}
+
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "email", Type: catalog.TypeVarchar, Size: 100, Nullable: true, Unique: true},
+	})
+
+	usersTable, err := table.NewTable("users", schema, heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
+
+	rid, _ := usersTable.Insert(nil, 1, "Alice", nil)
+	row, _ := usersTable.GetByRID(*rid)
+	fmt.Printf("1. Inséré avec email NULL: %v\n", row)
+
+	_, err = usersTable.Insert(nil, 2, "Bob", nil)
+	fmt.Printf("2. Deuxième email NULL accepté malgré la contrainte unique: %v\n", err == nil)
+
+	_, err = usersTable.Insert(nil, 3, nil, "charlie@example.com")
+	fmt.Printf("3. Nom NULL: %v (attendu)\n", err)
+
+	rows, _ := usersTable.LookupByIndex("users_email_key", nil)
+	fmt.Printf("4. Lignes avec email NULL: %v\n", rows)
+
+	dm.Close()
+}
+
+func testTypes() {
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeBigInt},
+		{Name: "price", Type: catalog.TypeDecimal, Precision: 10, Scale: 2},
+		{Name: "ratio", Type: catalog.TypeDouble},
+		{Name: "created_at", Type: catalog.TypeTimestamp},
+		{Name: "birthday", Type: catalog.TypeDate},
+		{Name: "uuid", Type: catalog.TypeUUID},
+		{Name: "payload", Type: catalog.TypeBlob, Size: 16},
+	})
+
+	price, _ := catalog.ParseDecimal("19.99")
+	uuid, _ := catalog.ParseUUID("123e4567-e89b-12d3-a456-426614174000")
+	createdAt := time.Date(2024, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	values := []any{int64(1) << 40, price, 0.25, createdAt, createdAt, uuid, []byte{0xCA, 0xFE}}
+	encoded, err := catalog.EncodeTuple(schema, values)
+	if err != nil {
+		fmt.Printf("ERREUR EncodeTuple: %v\n", err)
+		return
+	}
+	fmt.Printf("1. Tuple encodé: %d bytes\n", len(encoded))
+
+	decoded := catalog.DecodeTuple(schema, encoded)
+	fmt.Printf("2. id=%v, price=%v, ratio=%v\n", decoded[0], decoded[1], decoded[2])
+	fmt.Printf("3. created_at=%v, birthday=%v\n", decoded[3], decoded[4])
+	fmt.Printf("4. uuid=%v, payload=%x\n", decoded[5], decoded[6])
+
+	values[1] = catalog.NewDecimal(123456789012, 2)
+	_, err = catalog.EncodeTuple(schema, values)
+	fmt.Printf("5. DECIMAL trop grand: %v (attendu)\n", err)
+}
+
+func testSystemCatalog() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	sysCatalog, err := system_catalog.NewCatalog(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR NewCatalog: %v\n", err)
+		return
+	}
+
+	usersTable, err := sysCatalog.CreateTable("users", catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+	}))
+	if err != nil {
+		fmt.Printf("ERREUR CreateTable: %v\n", err)
+		return
+	}
+	usersTable.Insert(nil, 1, "Alice")
+	usersTable.Insert(nil, 2, "Bob")
+	fmt.Println("1. Table 'users' créée via le catalogue avec 2 lignes")
+
+	bpm.FlushAllPages()
+	dm.Close()
+	fmt.Println("2. Base de données fermée")
+
+	// Réouverture: le catalogue reconstruit la table depuis le fichier
+	dm, err = disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm = buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	// La racine du catalogue est lue depuis l'en-tête de la base (page 0)
+	sysCatalog, err = system_catalog.LoadCatalog(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR LoadCatalog: %v\n", err)
+		return
+	}
+
+	usersTable, err = sysCatalog.GetTable("users")
+	if err != nil {
+		fmt.Printf("ERREUR GetTable: %v\n", err)
+		return
+	}
+
+	fmt.Println("3. Scan après réouverture:")
+	scanner := usersTable.Scan()
+	for {
+		values, ok := scanner.Next()
+		if !ok {
+			break
+		}
+		fmt.Printf("   - id=%v, name=%v\n", values[0], values[1])
+	}
+
+	rows, _ := usersTable.LookupByIndex("users_pkey", 2)
+	fmt.Printf("4. Lookup id=2 via l'index persisté: %v\n", rows)
+
+	sysCatalog.DropTable("users")
+	_, err = sysCatalog.GetTable("users")
+	fmt.Printf("5. Après DropTable: %v (attendu)\n", err)
+
+	dm.Close()
+}
+
+func testOverflow() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	defer dm.Close()
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	heap, err := table_heap.NewTableHeap(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR NewTableHeap: %v\n", err)
+		return
+	}
+
+	docs, err := table.NewTable("docs", catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "body", Type: catalog.TypeVarchar, Size: 60000},
+		{Name: "attachment", Type: catalog.TypeBlob, Size: 20000, Nullable: true},
+	}), heap)
+	if err != nil {
+		fmt.Printf("ERREUR NewTable: %v\n", err)
+		return
+	}
+
+	// Un tuple de ~30 KB ne tient dans aucune page: il est stocké hors ligne
+	body := strings.Repeat("gobase ", 4000)
+	rid, err := docs.Insert(nil, 1, body, make([]byte, 2000))
+	if err != nil {
+		fmt.Printf("ERREUR Insert: %v\n", err)
+		return
+	}
+	fmt.Printf("1. Document de %d octets inséré (%d pages dans le fichier)\n", len(body), dm.NumPages)
+
+	row, err := docs.GetByRID(*rid)
+	if err != nil {
+		fmt.Printf("ERREUR GetByRID: %v\n", err)
+		return
+	}
+	fmt.Printf("2. Relu: body identique=%v, attachment=%d octets\n", row[1] == body, len(row[2].([]byte)))
+
+	err = docs.Update(nil, *rid, 1, "court", nil)
+	if err != nil {
+		fmt.Printf("ERREUR Update: %v\n", err)
+		return
+	}
+	fmt.Printf("3. Après réduction, pages libérées réutilisables (tête de liste: %d)\n", dm.GetFreeListHead())
+
+	scanner := docs.Scan()
+	for {
+		values, ok := scanner.Next()
+		if !ok {
+			break
+		}
+		fmt.Printf("4. Scan: id=%v, body=%v\n", values[0], values[1])
+	}
+}
+
+func testPageSize() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db", disk_manager.WithPageSize(shared.MAX_PAGE_SIZE))
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	heap, err := table_heap.NewTableHeap(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR NewTableHeap: %v\n", err)
+		dm.Close()
+		return
+	}
+
+	for i := 0; i < 1000; i++ {
+		_, err = heap.Insert(nil, shared.Tuple(strings.Repeat("x", 200)))
+		if err != nil {
+			fmt.Printf("ERREUR Insert: %v\n", err)
+			dm.Close()
+			return
+		}
+	}
+	fmt.Printf("1. 1000 tuples de 200 octets dans des pages de %d octets: %d pages\n", dm.PageSize, dm.NumPages)
+
+	bpm.FlushAllPages()
+	dm.Close()
+
+	// La taille de page est lue dans l'en-tête, inutile de la repréciser
+	dm2, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR réouverture: %v\n", err)
+		return
+	}
+	defer dm2.Close()
+	fmt.Printf("2. Réouverture: taille de page=%d\n", dm2.PageSize)
+
+	_, err = disk_manager.NewDiskManager("test.db", disk_manager.WithPageSize(shared.DEFAULT_PAGE_SIZE))
+	fmt.Printf("3. Ouverture avec une autre taille refusée: %v\n", err)
+}
+
+func testSQLParser() {
+	statements, err := sql.ParseAll(`
+		CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50) NOT NULL, age INT);
+		INSERT INTO users VALUES (1, 'Alice', 30), (2, 'Bob', 25);
+		SELECT name FROM users WHERE age > 25 AND name LIKE 'A%' ORDER BY name LIMIT 10;
+	`)
+	if err != nil {
+		fmt.Printf("ERREUR ParseAll: %v\n", err)
+		return
+	}
+
+	create := statements[0].(*sql.CreateTableStatement)
+	fmt.Printf("1. CREATE TABLE %s: %d colonnes, id de type %v\n", create.Table, len(create.Columns), create.Columns[0].Type)
+
+	insert := statements[1].(*sql.InsertStatement)
+	fmt.Printf("2. INSERT INTO %s: %d lignes\n", insert.Table, len(insert.Rows))
+
+	sel := statements[2].(*sql.SelectStatement)
+	fmt.Printf("3. SELECT depuis %s, limite %d, condition %T\n", sel.From.Name, *sel.Limit, sel.Where)
+
+	// Les erreurs de syntaxe indiquent la ligne et la colonne fautives
+	_, err = sql.Parse("SELECT * FROM users WHERE")
+	fmt.Printf("4. Requête incomplète: %v\n", err)
+}
+
+func testExecutor() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	defer dm.Close()
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 10)
+
+	sysCatalog, err := system_catalog.NewCatalog(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR NewCatalog: %v\n", err)
+		return
+	}
+
+	usersTable, err := sysCatalog.CreateTable("users", catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "city", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "age", Type: catalog.TypeInt},
+	}))
+	if err != nil {
+		fmt.Printf("ERREUR CreateTable: %v\n", err)
+		return
+	}
+
+	// INSERT INTO users VALUES ...
+	insert := executor.NewInsert(nil, usersTable, executor.NewValues(usersTable.Schema,
+		[]any{1, "Alice", "Paris", 30},
+		[]any{2, "Bob", "Lyon", 25},
+		[]any{3, "Carol", "Paris", 41},
+		[]any{4, "Dave", "Lyon", 19},
+	))
+	rows, err := executor.Collect(insert)
+	if err != nil {
+		fmt.Printf("ERREUR Insert: %v\n", err)
+		return
+	}
+	fmt.Printf("1. %v lignes insérées\n", rows[0].Values[0])
+
+	// SELECT name, age FROM users WHERE age > 20 ORDER BY age DESC LIMIT 2
+	adults := executor.NewFilter(executor.NewSeqScan(usersTable), func(values []any) (bool, error) {
+		return values[3].(int) > 20, nil
+	})
+	sorted, _ := executor.NewSort(adults, executor.SortKey{Column: "age", Desc: true})
+	projection, _ := executor.NewProjection(sorted, "name", "age")
+	rows, err = executor.Collect(executor.NewLimit(projection, 2, 0))
+	if err != nil {
+		fmt.Printf("ERREUR Select: %v\n", err)
+		return
+	}
+	fmt.Println("2. Les deux adultes les plus âgés:")
+	for _, row := range rows {
+		fmt.Printf("   - %v (%v ans)\n", row.Values[0], row.Values[1])
+	}
+
+	// SELECT city, COUNT(*), AVG(age) FROM users GROUP BY city
+	aggregate, _ := executor.NewHashAggregate(executor.NewSeqScan(usersTable), []string{"city"},
+		executor.Aggregate{Func: executor.AggregateCount},
+		executor.Aggregate{Func: executor.AggregateAvg, Column: "age"},
+	)
+	rows, err = executor.Collect(aggregate)
+	if err != nil {
+		fmt.Printf("ERREUR HashAggregate: %v\n", err)
+		return
+	}
+	fmt.Println("3. Regroupement par ville:")
+	for _, row := range rows {
+		fmt.Printf("   - %v: %v utilisateurs, âge moyen %.1f\n", row.Values[0], row.Values[1], row.Values[2])
+	}
+
+	// Parcours de l'index de clé primaire entre 2 et 3 inclus
+	rows, err = executor.Collect(executor.NewIndexScan(usersTable, "users_pkey", []any{2}, []any{3}))
+	if err != nil {
+		fmt.Printf("ERREUR IndexScan: %v\n", err)
+		return
+	}
+	fmt.Printf("4. IndexScan id entre 2 et 3: %d lignes\n", len(rows))
+
+	// DELETE FROM users WHERE city = 'Lyon'
+	lyon := executor.NewFilter(executor.NewSeqScan(usersTable), func(values []any) (bool, error) {
+		return values[2] == "Lyon", nil
+	})
+	rows, err = executor.Collect(executor.NewDelete(nil, usersTable, lyon))
+	if err != nil {
+		fmt.Printf("ERREUR Delete: %v\n", err)
+		return
+	}
+	remaining, _ := executor.Collect(executor.NewSeqScan(usersTable))
+	fmt.Printf("5. %v lignes supprimées, %d restantes\n", rows[0].Values[0], len(remaining))
+}
+
+func testJoins() {
+	removeTestDatabase()
+
+	dm, err := disk_manager.NewDiskManager("test.db")
+	if err != nil {
+		fmt.Printf("ERREUR DiskManager: %v\n", err)
+		return
+	}
+	defer dm.Close()
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 20)
+
+	sysCatalog, err := system_catalog.NewCatalog(bpm)
+	if err != nil {
+		fmt.Printf("ERREUR NewCatalog: %v\n", err)
+		return
+	}
+
+	usersTable, err := sysCatalog.CreateTable("users", catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+	}))
+	if err != nil {
+		fmt.Printf("ERREUR CreateTable users: %v\n", err)
+		return
+	}
+	ordersTable, err := sysCatalog.CreateTable("orders", catalog.NewSchema([]catalog.Column{
+		{Name: "order_id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "user_id", Type: catalog.TypeInt},
+		{Name: "amount", Type: catalog.TypeInt},
+	}))
+	if err != nil {
+		fmt.Printf("ERREUR CreateTable orders: %v\n", err)
+		return
+	}
+	sysCatalog.CreateIndex("orders", "orders_user", false, "user_id")
+
+	usersTable.Insert(nil, 1, "Alice")
+	usersTable.Insert(nil, 2, "Bob")
+	usersTable.Insert(nil, 3, "Carol")
+	ordersTable.Insert(nil, 10, 1, 100)
+	ordersTable.Insert(nil, 11, 1, 50)
+	ordersTable.Insert(nil, 12, 2, 70)
+	fmt.Println("1. Tables 'users' (3 lignes) et 'orders' (3 lignes) créées")
+
+	hashJoin, err := executor.NewHashJoin(executor.NewSeqScan(usersTable), executor.NewSeqScan(ordersTable),
+		[]string{"id"}, []string{"user_id"}, executor.JoinInner, nil, bpm)
+	if err != nil {
+		fmt.Printf("ERREUR NewHashJoin: %v\n", err)
+		return
+	}
+	rows, err := executor.Collect(hashJoin)
+	if err != nil {
+		fmt.Printf("ERREUR HashJoin: %v\n", err)
+		return
+	}
+	fmt.Println("2. Hash join users/orders:")
+	for _, row := range rows {
+		fmt.Printf("   - %v: commande %v de %v\n", row.Values[1], row.Values[2], row.Values[4])
+	}
+
+	// Left join par l'index: Carol n'a pas de commande
+	indexJoin, err := executor.NewIndexNestedLoopJoin(executor.NewSeqScan(usersTable), ordersTable,
+		"orders_user", []string{"id"}, executor.JoinLeft, nil)
+	if err != nil {
+		fmt.Printf("ERREUR NewIndexNestedLoopJoin: %v\n", err)
+		return
+	}
+	rows, _ = executor.Collect(indexJoin)
+	fmt.Printf("3. Left join via l'index: %d lignes, dernière %v\n", len(rows), rows[len(rows)-1].Values)
+
+	// Semi join par tri-fusion: utilisateurs ayant au moins une commande
+	mergeJoin, err := executor.NewSortMergeJoin(executor.NewSeqScan(usersTable), executor.NewSeqScan(ordersTable),
+		[]string{"id"}, []string{"user_id"}, executor.JoinSemi, nil)
+	if err != nil {
+		fmt.Printf("ERREUR NewSortMergeJoin: %v\n", err)
+		return
+	}
+	rows, _ = executor.Collect(mergeJoin)
+	fmt.Printf("4. Semi join tri-fusion: %d utilisateurs avec commandes\n", len(rows))
+
+	// Une limite mémoire minuscule force le hash join à déborder sur des pages temporaires
+	spilled, _ := executor.NewHashJoin(executor.NewSeqScan(usersTable), executor.NewSeqScan(ordersTable),
+		[]string{"id"}, []string{"user_id"}, executor.JoinInner, nil, bpm, executor.WithMemoryLimit(1))
+	rows, err = executor.Collect(spilled)
+	if err != nil {
+		fmt.Printf("ERREUR HashJoin avec débordement: %v\n", err)
+		return
+	}
+	fmt.Printf("5. Hash join avec débordement: %d lignes\n", len(rows))
+}
+
+func testExpressions() {
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "age", Type: catalog.TypeInt, Nullable: true},
+	})
+	rows := executor.NewValues(schema,
+		[]any{1, "Alice", 30},
+		[]any{2, "Albert", nil},
+		[]any{3, "Bob", 40},
+	)
+
+	where, err := expression.Parse("age > 25 AND name LIKE 'A%'", schema)
+	if err != nil {
+		fmt.Printf("ERREUR Parse: %v\n", err)
+		return
+	}
+	predicate, err := where.Predicate()
+	if err != nil {
+		fmt.Printf("ERREUR Predicate: %v\n", err)
+		return
+	}
+
+	// Albert a un âge NULL: la condition vaut NULL et la ligne est rejetée
+	result, err := executor.Collect(executor.NewFilter(rows, predicate))
+	if err != nil {
+		fmt.Printf("ERREUR Filter: %v\n", err)
+		return
+	}
+	fmt.Printf("1. WHERE age > 25 AND name LIKE 'A%%': %d ligne(s), %v\n", len(result), result[0].Values[1])
+
+	label, _ := expression.Parse("CASE WHEN age IS NULL THEN 'inconnu' WHEN age >= 35 THEN 'senior' ELSE 'adulte' END", schema)
+	for _, values := range [][]any{{1, "Alice", 30}, {2, "Albert", nil}, {3, "Bob", 40}} {
+		value, _ := label.Evaluate(values)
+		fmt.Printf("2. CASE pour %v: %v\n", values[1], value)
+	}
+
+	total, _ := expression.Parse("age * 2 + 0.5", schema)
+	value, _ := total.Evaluate([]any{1, "Alice", 30})
+	fmt.Printf("3. age * 2 + 0.5 = %v\n", value)
+
+	// Les types sont vérifiés avant toute exécution
+	_, err = expression.Parse("name + 1", schema)
+	fmt.Printf("4. Expression mal typée: %v (attendu)\n", err)
+}
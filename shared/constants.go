@@ -1,3 +1,17 @@
 package shared
 
-const PAGE_SIZE uint32 = 4096
+const (
+	DEFAULT_PAGE_SIZE uint32 = 4096
+	MIN_PAGE_SIZE     uint32 = 4096
+	MAX_PAGE_SIZE     uint32 = 65536
+)
+
+const (
+	PAGE_LSN_OFFSET uint32 = 0
+	PAGE_LSN_SIZE   uint32 = 8
+
+	PAGE_CHECKSUM_OFFSET uint32 = 8
+	PAGE_CHECKSUM_SIZE   uint32 = 4
+
+	PAGE_HEADER_SIZE uint32 = 12
+)
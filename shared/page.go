@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+func GetPageLSN(data []byte) uint64 {
+	return binary.LittleEndian.Uint64(data[PAGE_LSN_OFFSET:])
+}
+
+func SetPageLSN(data []byte, lsn uint64) {
+	binary.LittleEndian.PutUint64(data[PAGE_LSN_OFFSET:], lsn)
+}
+
+func GetPageChecksum(data []byte) uint32 {
+	return binary.LittleEndian.Uint32(data[PAGE_CHECKSUM_OFFSET:])
+}
+
+func SetPageChecksum(data []byte, checksum uint32) {
+	binary.LittleEndian.PutUint32(data[PAGE_CHECKSUM_OFFSET:], checksum)
+}
+
+func ComputePageChecksum(data []byte) uint32 {
+	checksum := crc32.Update(0, checksumTable, data[:PAGE_CHECKSUM_OFFSET])
+	return crc32.Update(checksum, checksumTable, data[PAGE_CHECKSUM_OFFSET+PAGE_CHECKSUM_SIZE:])
+}
+
+func IsValidPageSize(pageSize uint32) bool {
+	return pageSize >= MIN_PAGE_SIZE && pageSize <= MAX_PAGE_SIZE && pageSize&(pageSize-1) == 0
+}
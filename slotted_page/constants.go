@@ -1,13 +1,16 @@
 package slotted_page
 
 const (
-	HEADER_SIZE uint16 = 8
+	HEADER_SIZE uint16 = 24
 	SLOT_SIZE   uint16 = 4
 
-	NEXT_PAGE_ID_OFFSET uint16 = 4
-	PREV_PAGE_ID_OFFSET uint16 = 6
-	NULL_PAGE_ID = uint16(0xFFFF)
+	TUPLE_DELETED_FLAG uint16 = 0x8000
+	MAX_TUPLE_SIZE     uint16 = TUPLE_DELETED_FLAG - 1
 
-	NUM_SLOTS_OFFSET      uint16 = 0
-	FREE_SPACE_END_OFFSET uint16 = 2
+	NEXT_PAGE_ID_OFFSET uint16 = 16
+	PREV_PAGE_ID_OFFSET uint16 = 20
+	NULL_PAGE_ID = uint32(0xFFFFFFFF)
+
+	NUM_SLOTS_OFFSET      uint16 = 12
+	FREE_SPACE_END_OFFSET uint16 = 14
 )
@@ -6,4 +6,8 @@ var (
 	ErrNotEnoughSpace = errors.New("not enought space")
 	ErrorSlotDidntExists = errors.New("slot didn't exists")
 	ErrTupleHasBeenDeleted = errors.New("tuple has been deleted")
+	ErrSlotInUse = errors.New("slot is already in use")
+	ErrInvalidPageLayout = errors.New("invalid page layout")
+	ErrTupleTooLarge = errors.New("tuple is too large for a slot")
+	ErrTupleNotMarked = errors.New("tuple is not marked as deleted")
 )
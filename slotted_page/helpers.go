@@ -2,6 +2,7 @@ package slotted_page
 
 import (
 	"encoding/binary"
+	"sort"
 
 	"gobase/shared"
 )
@@ -14,11 +15,37 @@ func (sp *SlottedPage) setNumSlots(n uint16) {
 	binary.LittleEndian.PutUint16(sp.data[NUM_SLOTS_OFFSET:], n)
 }
 
-func (sp *SlottedPage) getFreeSpaceEnd() uint16 {
-	return binary.LittleEndian.Uint16(sp.data[FREE_SPACE_END_OFFSET:])
+// Offsets inside a page of at most 64 KiB fit in 16 bits; the only exception
+// is the free space end of an empty 64 KiB page, which wraps around to 0.
+func (sp *SlottedPage) getFreeSpaceEnd() int {
+	end := int(binary.LittleEndian.Uint16(sp.data[FREE_SPACE_END_OFFSET:]))
+	if end == 0 {
+		return len(sp.data)
+	}
+
+	return end
+}
+
+func (sp *SlottedPage) setFreeSpaceEnd(offset int) {
+	binary.LittleEndian.PutUint16(sp.data[FREE_SPACE_END_OFFSET:], uint16(offset))
+}
+
+// A page that is not a slotted page, such as a freed page, has a slot
+// count that cannot fit in front of its tuples.
+func (sp *SlottedPage) hasValidLayout() bool {
+	return int(HEADER_SIZE)+int(sp.GetNumSlots())*int(SLOT_SIZE) <= sp.getFreeSpaceEnd()
 }
-func (sp *SlottedPage) setFreeSpaceEnd(offset uint16) {
-	binary.LittleEndian.PutUint16(sp.data[FREE_SPACE_END_OFFSET:], offset)
+
+func (sp *SlottedPage) checkSlot(slotID uint16) error {
+	if !sp.hasValidLayout() {
+		return ErrInvalidPageLayout
+	}
+
+	if slotID >= sp.GetNumSlots() {
+		return ErrorSlotDidntExists
+	}
+
+	return nil
 }
 
 func (sp *SlottedPage) getSlot(slotID uint16) (offset uint16, length uint16) {
@@ -34,6 +61,74 @@ func (sp *SlottedPage) setSlot(slotID uint16, offset uint16, length uint16) {
 	binary.LittleEndian.PutUint16(sp.data[slotPos+2:], length)
 }
 
+func (sp *SlottedPage) getLiveBytes() int {
+	liveBytes := 0
+	for slotID := uint16(0); slotID < sp.GetNumSlots(); slotID++ {
+		_, length := sp.getSlot(slotID)
+		liveBytes += int(length &^ TUPLE_DELETED_FLAG)
+	}
+
+	return liveBytes
+}
+
+func (sp *SlottedPage) findFreeSlot() (uint16, bool) {
+	for slotID := uint16(0); slotID < sp.GetNumSlots(); slotID++ {
+		if _, length := sp.getSlot(slotID); length == 0 {
+			return slotID, true
+		}
+	}
+
+	return 0, false
+}
+
+func (sp *SlottedPage) reserveSpace(tupleSize int, numSlots uint16) error {
+	if tupleSize > int(MAX_TUPLE_SIZE) {
+		return ErrTupleTooLarge
+	}
+
+	slotsEnd := int(HEADER_SIZE) + int(numSlots)*int(SLOT_SIZE)
+	if slotsEnd+tupleSize <= sp.getFreeSpaceEnd() {
+		return nil
+	}
+
+	newSlots := int(numSlots) - int(sp.GetNumSlots())
+	if tupleSize+newSlots*int(SLOT_SIZE) > int(sp.GetFreeSpace()) {
+		return ErrNotEnoughSpace
+	}
+
+	sp.Compact()
+	return nil
+}
+
+// The tuple of skipSlotID, if any, is dropped rather than moved.
+func (sp *SlottedPage) compact(skipSlotID uint16) {
+	numSlots := sp.GetNumSlots()
+
+	live := make([]uint16, 0, numSlots)
+	for slotID := uint16(0); slotID < numSlots; slotID++ {
+		if _, length := sp.getSlot(slotID); length != 0 && slotID != skipSlotID {
+			live = append(live, slotID)
+		}
+	}
+
+	sort.Slice(live, func(i, j int) bool {
+		offsetI, _ := sp.getSlot(live[i])
+		offsetJ, _ := sp.getSlot(live[j])
+		return offsetI > offsetJ
+	})
+
+	freeSpaceEnd := len(sp.data)
+	for _, slotID := range live {
+		offset, length := sp.getSlot(slotID)
+		size := int(length &^ TUPLE_DELETED_FLAG)
+		freeSpaceEnd -= size
+		copy(sp.data[freeSpaceEnd:], sp.data[int(offset):int(offset)+size])
+		sp.setSlot(slotID, uint16(freeSpaceEnd), length)
+	}
+
+	sp.setFreeSpaceEnd(freeSpaceEnd)
+}
+
 func FromData(data []byte) *SlottedPage {
 	return &SlottedPage{data: data}
 }
@@ -44,23 +139,31 @@ func (sp *SlottedPage) GetData() []byte {
 
 func InitSlottedPage(data []byte) {
 	binary.LittleEndian.PutUint16(data[NUM_SLOTS_OFFSET:], 0)
-	binary.LittleEndian.PutUint16(data[FREE_SPACE_END_OFFSET:], uint16(shared.PAGE_SIZE))
-	binary.LittleEndian.PutUint16(data[NEXT_PAGE_ID_OFFSET:], NULL_PAGE_ID)
-	binary.LittleEndian.PutUint16(data[PREV_PAGE_ID_OFFSET:], NULL_PAGE_ID)
+	binary.LittleEndian.PutUint16(data[FREE_SPACE_END_OFFSET:], uint16(len(data)))
+	binary.LittleEndian.PutUint32(data[NEXT_PAGE_ID_OFFSET:], NULL_PAGE_ID)
+	binary.LittleEndian.PutUint32(data[PREV_PAGE_ID_OFFSET:], NULL_PAGE_ID)
+}
+
+func (sp *SlottedPage) GetNextPageID() uint32 {
+	return binary.LittleEndian.Uint32(sp.data[NEXT_PAGE_ID_OFFSET:])
+}
+
+func (sp *SlottedPage) SetNextPageID(pageID uint32) {
+	binary.LittleEndian.PutUint32(sp.data[NEXT_PAGE_ID_OFFSET:], pageID)
 }
 
-func (sp *SlottedPage) GetNextPageID() uint16 {
-	return binary.LittleEndian.Uint16(sp.data[NEXT_PAGE_ID_OFFSET:])
+func (sp *SlottedPage) GetPrevPageID() uint32 {
+	return binary.LittleEndian.Uint32(sp.data[PREV_PAGE_ID_OFFSET:])
 }
 
-func (sp *SlottedPage) SetNextPageID(pageID uint16) {
-	binary.LittleEndian.PutUint16(sp.data[NEXT_PAGE_ID_OFFSET:], pageID)
+func (sp *SlottedPage) SetPrevPageID(pageID uint32) {
+	binary.LittleEndian.PutUint32(sp.data[PREV_PAGE_ID_OFFSET:], pageID)
 }
 
-func (sp *SlottedPage) GetPrevPageID() uint16 {
-	return binary.LittleEndian.Uint16(sp.data[PREV_PAGE_ID_OFFSET:])
+func (sp *SlottedPage) GetLSN() uint64 {
+	return shared.GetPageLSN(sp.data)
 }
 
-func (sp *SlottedPage) SetPrevPageID(pageID uint16) {
-	binary.LittleEndian.PutUint16(sp.data[PREV_PAGE_ID_OFFSET:], pageID)
+func (sp *SlottedPage) SetLSN(lsn uint64) {
+	shared.SetPageLSN(sp.data, lsn)
 }
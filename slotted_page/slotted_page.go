@@ -1,58 +1,203 @@
 package slotted_page
 
 import (
+	"errors"
+
 	"gobase/shared"
 )
 
 func (sp *SlottedPage) GetFreeSpace() uint16 {
-	return sp.getFreeSpaceEnd() - (HEADER_SIZE + sp.GetNumSlots()*SLOT_SIZE)
+	return uint16(len(sp.data) - int(HEADER_SIZE) - int(sp.GetNumSlots())*int(SLOT_SIZE) - sp.getLiveBytes())
+}
+
+func (sp *SlottedPage) IsEmpty() bool {
+	for slotID := uint16(0); slotID < sp.GetNumSlots(); slotID++ {
+		if _, length := sp.getSlot(slotID); length != 0 {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (sp *SlottedPage) InsertTuple(tuple shared.Tuple) (slotID uint16, err error) {
-	spaceRequired := len(tuple) + int(SLOT_SIZE)
-	if spaceRequired > int(sp.GetFreeSpace()) {
-		return 0, ErrNotEnoughSpace
+	if !sp.hasValidLayout() {
+		return 0, ErrInvalidPageLayout
 	}
 
-	newTupleOffset := sp.getFreeSpaceEnd() - uint16(len(tuple))
-	copy(sp.data[newTupleOffset:], tuple)
+	numSlots := sp.GetNumSlots()
+
+	slotID, reused := sp.findFreeSlot()
+	if !reused {
+		slotID = numSlots
+		numSlots++
+	}
+
+	err = sp.reserveSpace(len(tuple), numSlots)
+	if err != nil {
+		return 0, err
+	}
 
-	slotID = sp.GetNumSlots()
+	newTupleOffset := sp.getFreeSpaceEnd() - len(tuple)
+	copy(sp.data[newTupleOffset:], tuple)
 
-	sp.setSlot(slotID, newTupleOffset, uint16(len(tuple)))
-	sp.setNumSlots(sp.GetNumSlots() + 1)
+	sp.setSlot(slotID, uint16(newTupleOffset), uint16(len(tuple)))
+	sp.setNumSlots(numSlots)
 	sp.setFreeSpaceEnd(newTupleOffset)
 
 	return slotID, nil
 }
 
 func (sp *SlottedPage) GetTuple(slotID uint16) (shared.Tuple, error) {
-	if slotID >= sp.GetNumSlots() {
-		return nil, ErrorSlotDidntExists
+	err := sp.checkSlot(slotID)
+	if err != nil {
+		return nil, err
 	}
 
 	offset, length := sp.getSlot(slotID)
-	if length == 0 {
+	if length == 0 || length&TUPLE_DELETED_FLAG != 0 {
 		return nil, ErrTupleHasBeenDeleted
 	}
 
 	tuple := make(shared.Tuple, length)
-	copy(tuple, sp.data[offset:offset+length])
+	copy(tuple, sp.data[int(offset):int(offset)+int(length)])
 
 	return tuple, nil
 }
 
 func (sp *SlottedPage) DeleteTuple(slotID uint16) error {
-	if slotID >= sp.GetNumSlots() {
-		return ErrorSlotDidntExists
+	err := sp.checkSlot(slotID)
+	if err != nil {
+		return err
+	}
+
+	_, length := sp.getSlot(slotID)
+	if length == 0 {
+		return ErrTupleHasBeenDeleted
+	}
+
+	sp.setSlot(slotID, 0, 0)
+
+	return nil
+}
+
+func (sp *SlottedPage) MarkDeleteTuple(slotID uint16) error {
+	err := sp.checkSlot(slotID)
+	if err != nil {
+		return err
+	}
+
+	offset, length := sp.getSlot(slotID)
+	if length == 0 || length&TUPLE_DELETED_FLAG != 0 {
+		return ErrTupleHasBeenDeleted
+	}
+
+	sp.setSlot(slotID, offset, length|TUPLE_DELETED_FLAG)
+
+	return nil
+}
+
+// GetMarkedTuple reads a tuple whose deletion has not been applied yet.
+func (sp *SlottedPage) GetMarkedTuple(slotID uint16) (shared.Tuple, error) {
+	err := sp.checkSlot(slotID)
+	if err != nil {
+		return nil, err
 	}
 
 	offset, length := sp.getSlot(slotID)
 	if length == 0 {
+		return nil, ErrTupleHasBeenDeleted
+	}
+	if length&TUPLE_DELETED_FLAG == 0 {
+		return nil, ErrTupleNotMarked
+	}
+	length &^= TUPLE_DELETED_FLAG
+
+	tuple := make(shared.Tuple, length)
+	copy(tuple, sp.data[int(offset):int(offset)+int(length)])
+
+	return tuple, nil
+}
+
+func (sp *SlottedPage) RestoreTuple(slotID uint16, tuple shared.Tuple) error {
+	numSlots := sp.GetNumSlots()
+
+	if slotID < numSlots {
+		offset, length := sp.getSlot(slotID)
+		if length&TUPLE_DELETED_FLAG != 0 {
+			sp.setSlot(slotID, offset, length&^TUPLE_DELETED_FLAG)
+			return nil
+		}
+		if length != 0 {
+			return ErrSlotInUse
+		}
+	}
+
+	newNumSlots := numSlots
+	if slotID >= numSlots {
+		newNumSlots = slotID + 1
+	}
+
+	err := sp.reserveSpace(len(tuple), newNumSlots)
+	if err != nil {
+		return err
+	}
+
+	for i := numSlots; i < newNumSlots; i++ {
+		sp.setSlot(i, 0, 0)
+	}
+
+	newTupleOffset := sp.getFreeSpaceEnd() - len(tuple)
+	copy(sp.data[newTupleOffset:], tuple)
+
+	sp.setSlot(slotID, uint16(newTupleOffset), uint16(len(tuple)))
+	sp.setNumSlots(newNumSlots)
+	sp.setFreeSpaceEnd(newTupleOffset)
+
+	return nil
+}
+
+func (sp *SlottedPage) Compact() {
+	sp.compact(sp.GetNumSlots())
+}
+
+func (sp *SlottedPage) UpdateTuple(slotID uint16, tuple shared.Tuple) error {
+	err := sp.checkSlot(slotID)
+	if err != nil {
+		return err
+	}
+
+	offset, length := sp.getSlot(slotID)
+	if length == 0 || length&TUPLE_DELETED_FLAG != 0 {
 		return ErrTupleHasBeenDeleted
 	}
 
-	sp.setSlot(slotID, offset, 0)
+	if len(tuple) <= int(length) {
+		copy(sp.data[offset:], tuple)
+		sp.setSlot(slotID, offset, uint16(len(tuple)))
+		return nil
+	}
+
+	if len(tuple)-int(length) > int(sp.GetFreeSpace()) {
+		return ErrNotEnoughSpace
+	}
+
+	err = sp.reserveSpace(len(tuple), sp.GetNumSlots())
+	if errors.Is(err, ErrNotEnoughSpace) {
+		// The new tuple only fits once the space of the old one is reclaimed;
+		// the slot keeps pointing at the old tuple until it is overwritten.
+		sp.compact(slotID)
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	newTupleOffset := sp.getFreeSpaceEnd() - len(tuple)
+	copy(sp.data[newTupleOffset:], tuple)
+
+	sp.setSlot(slotID, uint16(newTupleOffset), uint16(len(tuple)))
+	sp.setFreeSpaceEnd(newTupleOffset)
 
 	return nil
 }
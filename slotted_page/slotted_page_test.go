@@ -1,6 +1,7 @@
 package slotted_page
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -11,7 +12,7 @@ import (
 )
 
 func TestInsertTuple(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	newTuple := shared.NewTuple("data_test")
 
@@ -27,16 +28,16 @@ func TestInsertTuple(t *testing.T) {
 }
 
 func TestInsertTuple_ErrNotEnoughSpace(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
-	bigTuple := make(shared.Tuple, shared.PAGE_SIZE+1)
+	bigTuple := make(shared.Tuple, shared.DEFAULT_PAGE_SIZE+1)
 
 	_, err := sp.InsertTuple(bigTuple)
 	require.ErrorIs(t, err, ErrNotEnoughSpace)
 }
 
 func TestInsertTuple_MultipleInserts(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	for i := 0; i < 3; i++ {
 		tuple := shared.NewTuple(fmt.Sprintf("data_%d", i))
@@ -48,14 +49,14 @@ func TestInsertTuple_MultipleInserts(t *testing.T) {
   }
 
 func TestGetTuple(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	tupleData := []byte("data_test")
-	offset := uint16(shared.PAGE_SIZE) - uint16(len(tupleData))
+	offset := uint16(shared.DEFAULT_PAGE_SIZE) - uint16(len(tupleData))
 	copy(sp.data[offset:], tupleData)
 	sp.setSlot(0, offset, uint16(len(tupleData)))
 	sp.setNumSlots(1)
-	sp.setFreeSpaceEnd(offset)
+	sp.setFreeSpaceEnd(int(offset))
 
 	tuple, err := sp.GetTuple(0)
 	require.NoError(t, err)
@@ -63,35 +64,48 @@ func TestGetTuple(t *testing.T) {
 }
 
 func TestGetTuple_ErrSlotDidntExists(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	_, err := sp.GetTuple(0)
 	require.ErrorIs(t, err, ErrorSlotDidntExists)
 }
 
+func TestGetTuple_ErrInvalidPageLayout(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+	sp.setNumSlots(0xFFFF)
+
+	_, err := sp.GetTuple(0)
+	require.ErrorIs(t, err, ErrInvalidPageLayout)
+
+	_, err = sp.InsertTuple(shared.NewTuple("data_test"))
+	require.ErrorIs(t, err, ErrInvalidPageLayout)
+
+	require.ErrorIs(t, sp.DeleteTuple(0), ErrInvalidPageLayout)
+}
+
 func TestGetTuple_ErrTupleHasBeenDelete(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	tupleData := []byte("data_test")
-	offset := uint16(shared.PAGE_SIZE) - uint16(len(tupleData))
+	offset := uint16(shared.DEFAULT_PAGE_SIZE) - uint16(len(tupleData))
 	copy(sp.data[offset:], tupleData)
 	sp.setSlot(0, offset, 0)
 	sp.setNumSlots(1)
-	sp.setFreeSpaceEnd(offset)
+	sp.setFreeSpaceEnd(int(offset))
 
 	_, err := sp.GetTuple(0)
 	require.ErrorIs(t, err, ErrTupleHasBeenDeleted)
 }
 
 func TestDeleteTuple(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	tupleData := []byte("data_test")
-	offset := uint16(shared.PAGE_SIZE) - uint16(len(tupleData))
+	offset := uint16(shared.DEFAULT_PAGE_SIZE) - uint16(len(tupleData))
 	copy(sp.data[offset:], tupleData)
 	sp.setSlot(0, offset, uint16(len(tupleData)))
 	sp.setNumSlots(1)
-	sp.setFreeSpaceEnd(offset)
+	sp.setFreeSpaceEnd(int(offset))
 
 	err := sp.DeleteTuple(0)
 	require.NoError(t, err)
@@ -101,22 +115,323 @@ func TestDeleteTuple(t *testing.T) {
 }
 
 func TestDeleteTuple_ErrSlotDidntExists(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	err := sp.DeleteTuple(0)
 	require.ErrorIs(t, err, ErrorSlotDidntExists)
 }
 
 func TestDeleteTuple_ErrTupleHasBeenDeleted(t *testing.T) {
-	sp := NewSlottedPage()
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
 
 	tupleData := []byte("data_test")
-	offset := uint16(shared.PAGE_SIZE) - uint16(len(tupleData))
+	offset := uint16(shared.DEFAULT_PAGE_SIZE) - uint16(len(tupleData))
 	copy(sp.data[offset:], tupleData)
 	sp.setSlot(0, offset, 0)
 	sp.setNumSlots(1)
-	sp.setFreeSpaceEnd(offset)
+	sp.setFreeSpaceEnd(int(offset))
 
 	err := sp.DeleteTuple(0)
 	require.ErrorIs(t, err, ErrTupleHasBeenDeleted)
 }
+
+func TestRestoreTuple_DeletedSlot(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("data_test"))
+	require.NoError(t, err)
+	require.NoError(t, sp.DeleteTuple(slotID))
+
+	err = sp.RestoreTuple(slotID, shared.NewTuple("restored"))
+	require.NoError(t, err)
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("restored"), tuple)
+}
+
+func TestRestoreTuple_BeyondLastSlot(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	err := sp.RestoreTuple(2, shared.NewTuple("data_test"))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(3), sp.GetNumSlots())
+
+	_, err = sp.GetTuple(0)
+	require.ErrorIs(t, err, ErrTupleHasBeenDeleted)
+
+	tuple, err := sp.GetTuple(2)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("data_test"), tuple)
+}
+
+func TestRestoreTuple_ErrSlotInUse(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("data_test"))
+	require.NoError(t, err)
+
+	err = sp.RestoreTuple(slotID, shared.NewTuple("restored"))
+	require.ErrorIs(t, err, ErrSlotInUse)
+}
+
+func TestGetFreeSpace_CountsDeletedTuples(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(make(shared.Tuple, 100))
+	require.NoError(t, err)
+	freeSpace := sp.GetFreeSpace()
+
+	require.NoError(t, sp.DeleteTuple(slotID))
+	assert.Equal(t, freeSpace+100, sp.GetFreeSpace())
+}
+
+func TestCompact(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	for i := 0; i < 4; i++ {
+		_, err := sp.InsertTuple(shared.NewTuple(fmt.Sprintf("tuple_%d", i)))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sp.DeleteTuple(0))
+	require.NoError(t, sp.DeleteTuple(2))
+	freeSpace := sp.GetFreeSpace()
+
+	sp.Compact()
+
+	assert.Equal(t, freeSpace, sp.GetFreeSpace())
+	assert.Equal(t, int(shared.DEFAULT_PAGE_SIZE)-14, sp.getFreeSpaceEnd())
+
+	tuple, err := sp.GetTuple(1)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("tuple_1"), tuple)
+
+	tuple, err = sp.GetTuple(3)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("tuple_3"), tuple)
+
+	_, err = sp.GetTuple(2)
+	require.ErrorIs(t, err, ErrTupleHasBeenDeleted)
+}
+
+func TestInsertTuple_CompactsFragmentedPage(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	tupleSize := 1000
+	for i := 0; i < 4; i++ {
+		_, err := sp.InsertTuple(make(shared.Tuple, tupleSize))
+		require.NoError(t, err)
+	}
+
+	_, err := sp.InsertTuple(make(shared.Tuple, tupleSize))
+	require.ErrorIs(t, err, ErrNotEnoughSpace)
+
+	require.NoError(t, sp.DeleteTuple(1))
+
+	bigTuple := make(shared.Tuple, tupleSize+40)
+	for i := range bigTuple {
+		bigTuple[i] = 0xAB
+	}
+
+	slotID, err := sp.InsertTuple(bigTuple)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), slotID)
+	assert.Equal(t, uint16(4), sp.GetNumSlots())
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, bigTuple, tuple)
+}
+
+func TestInsertTuple_ReusesDeletedSlot(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	for i := 0; i < 3; i++ {
+		_, err := sp.InsertTuple(shared.NewTuple(fmt.Sprintf("data_%d", i)))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sp.DeleteTuple(1))
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("reused"))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), slotID)
+	assert.Equal(t, uint16(3), sp.GetNumSlots())
+
+	tuple, err := sp.GetTuple(2)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("data_2"), tuple)
+}
+
+func TestMarkDeleteTuple_KeepsSlotAndSpace(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("data_test"))
+	require.NoError(t, err)
+	freeSpace := sp.GetFreeSpace()
+
+	require.NoError(t, sp.MarkDeleteTuple(slotID))
+
+	_, err = sp.GetTuple(slotID)
+	require.ErrorIs(t, err, ErrTupleHasBeenDeleted)
+	assert.Equal(t, freeSpace, sp.GetFreeSpace())
+
+	newSlotID, err := sp.InsertTuple(shared.NewTuple("other"))
+	require.NoError(t, err)
+	assert.NotEqual(t, slotID, newSlotID)
+
+	sp.Compact()
+
+	require.NoError(t, sp.RestoreTuple(slotID, shared.NewTuple("data_test")))
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("data_test"), tuple)
+}
+
+func TestIsEmpty(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+	assert.True(t, sp.IsEmpty())
+
+	first, err := sp.InsertTuple(shared.NewTuple("first"))
+	require.NoError(t, err)
+	second, err := sp.InsertTuple(shared.NewTuple("second"))
+	require.NoError(t, err)
+
+	require.NoError(t, sp.DeleteTuple(first))
+	require.NoError(t, sp.MarkDeleteTuple(second))
+	assert.False(t, sp.IsEmpty())
+
+	require.NoError(t, sp.DeleteTuple(second))
+	assert.True(t, sp.IsEmpty())
+}
+
+func TestUpdateTuple_InPlace(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("data_test"))
+	require.NoError(t, err)
+	offset, _ := sp.getSlot(slotID)
+
+	err = sp.UpdateTuple(slotID, shared.NewTuple("new"))
+	require.NoError(t, err)
+
+	newOffset, length := sp.getSlot(slotID)
+	assert.Equal(t, offset, newOffset)
+	assert.Equal(t, uint16(3), length)
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("new"), tuple)
+}
+
+func TestUpdateTuple_GrowsWithinPage(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	_, err := sp.InsertTuple(make(shared.Tuple, 2000))
+	require.NoError(t, err)
+	slotID, err := sp.InsertTuple(shared.NewTuple("small"))
+	require.NoError(t, err)
+	require.NoError(t, sp.DeleteTuple(0))
+
+	bigTuple := make(shared.Tuple, 3000)
+	bigTuple[0] = 0xAB
+
+	err = sp.UpdateTuple(slotID, bigTuple)
+	require.NoError(t, err)
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, bigTuple, tuple)
+}
+
+func TestUpdateTuple_ErrNotEnoughSpace(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	_, err := sp.InsertTuple(make(shared.Tuple, 3000))
+	require.NoError(t, err)
+	slotID, err := sp.InsertTuple(shared.NewTuple("small"))
+	require.NoError(t, err)
+
+	err = sp.UpdateTuple(slotID, make(shared.Tuple, 2000))
+	require.ErrorIs(t, err, ErrNotEnoughSpace)
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("small"), tuple)
+}
+
+func TestUpdateTuple_ReusesOwnSpace(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	_, err := sp.InsertTuple(shared.NewTuple("first"))
+	require.NoError(t, err)
+	slotID, err := sp.InsertTuple(make(shared.Tuple, 3000))
+	require.NoError(t, err)
+	_, err = sp.InsertTuple(shared.NewTuple("last"))
+	require.NoError(t, err)
+
+	bigTuple := make(shared.Tuple, 3500)
+	bigTuple[0] = 0xAB
+	require.Less(t, int(sp.GetFreeSpace()), len(bigTuple))
+
+	require.NoError(t, sp.UpdateTuple(slotID, bigTuple))
+
+	for id, expected := range []shared.Tuple{shared.NewTuple("first"), bigTuple, shared.NewTuple("last")} {
+		tuple, err := sp.GetTuple(uint16(id))
+		require.NoError(t, err)
+		assert.Equal(t, expected, tuple)
+	}
+}
+
+func TestUpdateTuple_ErrTupleTooLarge(t *testing.T) {
+	sp := NewSlottedPage(shared.MAX_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("small"))
+	require.NoError(t, err)
+
+	err = sp.UpdateTuple(slotID, make(shared.Tuple, int(MAX_TUPLE_SIZE)+1))
+	require.ErrorIs(t, err, ErrTupleTooLarge)
+
+	tuple, err := sp.GetTuple(slotID)
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewTuple("small"), tuple)
+}
+
+func TestUpdateTuple_ErrTupleHasBeenDeleted(t *testing.T) {
+	sp := NewSlottedPage(shared.DEFAULT_PAGE_SIZE)
+
+	slotID, err := sp.InsertTuple(shared.NewTuple("data_test"))
+	require.NoError(t, err)
+	require.NoError(t, sp.DeleteTuple(slotID))
+
+	err = sp.UpdateTuple(slotID, shared.NewTuple("new"))
+	require.ErrorIs(t, err, ErrTupleHasBeenDeleted)
+}
+
+func TestSlottedPage_MaxPageSize(t *testing.T) {
+	sp := NewSlottedPage(shared.MAX_PAGE_SIZE)
+	assert.Equal(t, int(shared.MAX_PAGE_SIZE), sp.getFreeSpaceEnd())
+	assert.Equal(t, uint16(shared.MAX_PAGE_SIZE-uint32(HEADER_SIZE)), sp.GetFreeSpace())
+
+	_, err := sp.InsertTuple(make(shared.Tuple, MAX_TUPLE_SIZE+1))
+	require.ErrorIs(t, err, ErrTupleTooLarge)
+
+	tuples := make([]shared.Tuple, 3)
+	for i := range tuples {
+		tuples[i] = shared.Tuple(bytes.Repeat([]byte{byte('a' + i)}, 20000))
+		_, err = sp.InsertTuple(tuples[i])
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sp.DeleteTuple(0))
+	sp.Compact()
+	assert.Equal(t, int(shared.MAX_PAGE_SIZE)-40000, sp.getFreeSpaceEnd())
+
+	for i := 1; i < len(tuples); i++ {
+		tuple, err := sp.GetTuple(uint16(i))
+		require.NoError(t, err)
+		assert.Equal(t, tuples[i], tuple)
+	}
+}
@@ -1,11 +1,5 @@
 package slotted_page
 
-import (
-	"encoding/binary"
-
-	"gobase/shared"
-)
-
 type SlottedPage struct {
 	data []byte
 }
@@ -15,11 +9,9 @@ type Slot struct {
 	Length uint16
 }
 
-func NewSlottedPage() *SlottedPage {
-	data := make([]byte, shared.PAGE_SIZE)
-
-	binary.LittleEndian.PutUint16(data[NUM_SLOTS_OFFSET:], 0)
-	binary.LittleEndian.PutUint16(data[FREE_SPACE_END_OFFSET:], uint16(shared.PAGE_SIZE))
+func NewSlottedPage(pageSize uint32) *SlottedPage {
+	data := make([]byte, pageSize)
+	InitSlottedPage(data)
 
 	return &SlottedPage{data: data}
 }
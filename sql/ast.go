@@ -0,0 +1,153 @@
+package sql
+
+import "gobase/catalog"
+
+type Statement interface {
+	statementNode()
+}
+
+type Expr interface {
+	exprNode()
+}
+
+type CreateTableStatement struct {
+	Table   string
+	Columns []catalog.Column
+}
+
+type DropTableStatement struct {
+	Table string
+}
+
+type CreateIndexStatement struct {
+	Name    string
+	Table   string
+	Unique  bool
+	Columns []string
+}
+
+type InsertStatement struct {
+	Table   string
+	Columns []string
+	Rows    [][]Expr
+}
+
+type SelectStatement struct {
+	Items   []SelectItem
+	From    *TableRef
+	Joins   []Join
+	Where   Expr
+	OrderBy []OrderItem
+	Limit   *int
+	Offset  *int
+}
+
+type UpdateStatement struct {
+	Table       string
+	Assignments []Assignment
+	Where       Expr
+}
+
+type DeleteStatement struct {
+	Table string
+	Where Expr
+}
+
+type SelectItem struct {
+	Expr  Expr
+	Alias string
+}
+
+type TableRef struct {
+	Name  string
+	Alias string
+}
+
+type Join struct {
+	Type  JoinType
+	Table TableRef
+	On    Expr
+}
+
+type OrderItem struct {
+	Expr Expr
+	Desc bool
+}
+
+type Assignment struct {
+	Column string
+	Value  Expr
+}
+
+type Star struct {
+	Table string
+}
+
+type ColumnRef struct {
+	Table  string
+	Column string
+}
+
+// Literal values use the Go types catalog.EncodeTuple accepts: int for
+// integers, catalog.Decimal for exact numbers, float64 for numbers written
+// with an exponent, and nil for NULL.
+type Literal struct {
+	Value any
+}
+
+type UnaryExpr struct {
+	Op      Operator
+	Operand Expr
+}
+
+type BinaryExpr struct {
+	Op    Operator
+	Left  Expr
+	Right Expr
+}
+
+type LikeExpr struct {
+	Expr    Expr
+	Pattern Expr
+	Not     bool
+}
+
+type InExpr struct {
+	Expr Expr
+	List []Expr
+	Not  bool
+}
+
+type IsNullExpr struct {
+	Expr Expr
+	Not  bool
+}
+
+type CaseExpr struct {
+	Operand Expr
+	Whens   []WhenClause
+	Else    Expr
+}
+
+type WhenClause struct {
+	Cond   Expr
+	Result Expr
+}
+
+func (*CreateTableStatement) statementNode() {}
+func (*DropTableStatement) statementNode()   {}
+func (*CreateIndexStatement) statementNode() {}
+func (*InsertStatement) statementNode()      {}
+func (*SelectStatement) statementNode()      {}
+func (*UpdateStatement) statementNode()      {}
+func (*DeleteStatement) statementNode()      {}
+
+func (*Star) exprNode()       {}
+func (*ColumnRef) exprNode()  {}
+func (*Literal) exprNode()    {}
+func (*UnaryExpr) exprNode()  {}
+func (*BinaryExpr) exprNode() {}
+func (*LikeExpr) exprNode()   {}
+func (*InExpr) exprNode()     {}
+func (*IsNullExpr) exprNode() {}
+func (*CaseExpr) exprNode()   {}
@@ -0,0 +1,18 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSyntax = errors.New("syntax error")
+)
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at line %d, column %d: %s", ErrSyntax, e.Pos.Line, e.Pos.Column, e.Message)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
@@ -0,0 +1,399 @@
+package sql
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"gobase/catalog"
+)
+
+var comparisonOperators = map[string]Operator{
+	"=":  OpEq,
+	"<>": OpNotEq,
+	"!=": OpNotEq,
+	"<":  OpLt,
+	"<=": OpLtEq,
+	">":  OpGt,
+	">=": OpGtEq,
+}
+
+var typedLiterals = map[string]catalog.ColumnType{
+	"DATE":      catalog.TypeDate,
+	"TIMESTAMP": catalog.TypeTimestamp,
+	"UUID":      catalog.TypeUUID,
+}
+
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006-01-02",
+}
+
+// Precedence from loosest to tightest: OR, AND, NOT, comparisons (including
+// LIKE, IN and IS NULL), + and -, then *, / and %.
+func (p *parser) parseExpr() (Expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: OpOr, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: OpAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if !p.acceptKeyword("NOT") {
+		return p.parseComparison()
+	}
+
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnaryExpr{Op: OpNot, Operand: operand}, nil
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.Type == TokenSymbol {
+		op, ok := comparisonOperators[tok.Text]
+		if !ok {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &BinaryExpr{Op: op, Left: left, Right: right}, nil
+	}
+
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		err = p.expectKeyword("NULL")
+		if err != nil {
+			return nil, err
+		}
+		return &IsNullExpr{Expr: left, Not: not}, nil
+	}
+
+	not := false
+	if next := p.peekAt(1); p.isKeyword("NOT") && next.Type == TokenKeyword && (next.Text == "LIKE" || next.Text == "IN") {
+		p.next()
+		not = true
+	}
+
+	switch {
+	case p.acceptKeyword("LIKE"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &LikeExpr{Expr: left, Pattern: pattern, Not: not}, nil
+	case p.acceptKeyword("IN"):
+		list, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		return &InExpr{Expr: left, List: list, Not: not}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op Operator
+		switch {
+		case p.acceptSymbol("+"):
+			op = OpAdd
+		case p.acceptSymbol("-"):
+			op = OpSub
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: op, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op Operator
+		switch {
+		case p.acceptSymbol("*"):
+			op = OpMul
+		case p.acceptSymbol("/"):
+			op = OpDiv
+		case p.acceptSymbol("%"):
+			op = OpMod
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: op, Left: left, Right: right}
+	}
+}
+
+// A minus sign directly in front of a number is folded into the literal, so
+// that INSERT values like -5 reach the executor as plain constants.
+func (p *parser) parseUnary() (Expr, error) {
+	if p.acceptSymbol("+") {
+		return p.parseUnary()
+	}
+
+	if !p.isSymbol("-") {
+		return p.parsePrimary()
+	}
+	p.next()
+
+	if tok := p.peek(); tok.Type == TokenInteger || tok.Type == TokenNumber {
+		p.next()
+		return parseNumber(Token{Type: tok.Type, Text: "-" + tok.Text, Pos: tok.Pos})
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnaryExpr{Op: OpNeg, Operand: operand}, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.peek()
+
+	switch tok.Type {
+	case TokenInteger, TokenNumber:
+		p.next()
+		return parseNumber(tok)
+	case TokenString:
+		p.next()
+		return &Literal{Value: tok.Text}, nil
+	case TokenBlob:
+		p.next()
+		value, err := hex.DecodeString(tok.Text)
+		if err != nil {
+			return nil, syntaxError(tok.Pos, "invalid blob literal")
+		}
+		return &Literal{Value: value}, nil
+	case TokenIdent:
+		if _, ok := typedLiterals[strings.ToUpper(tok.Text)]; ok && p.peekAt(1).Type == TokenString {
+			return p.parseTypedLiteral()
+		}
+		return p.parseColumnRef()
+	case TokenKeyword:
+		switch tok.Text {
+		case "NULL":
+			p.next()
+			return &Literal{Value: nil}, nil
+		case "TRUE", "FALSE":
+			p.next()
+			return &Literal{Value: tok.Text == "TRUE"}, nil
+		case "CASE":
+			return p.parseCase()
+		}
+	case TokenSymbol:
+		if tok.Text == "(" {
+			p.next()
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectSymbol(")")
+		}
+	}
+
+	return nil, p.unexpected("expression")
+}
+
+func (p *parser) parseColumnRef() (Expr, error) {
+	name := p.next().Text
+	if !p.acceptSymbol(".") {
+		return &ColumnRef{Column: name}, nil
+	}
+
+	column, err := p.expectIdent("column name")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ColumnRef{Table: name, Column: column}, nil
+}
+
+func (p *parser) parseTypedLiteral() (Expr, error) {
+	typeTok := p.next()
+	tok := p.next()
+
+	switch typedLiterals[strings.ToUpper(typeTok.Text)] {
+	case catalog.TypeDate:
+		value, err := time.Parse("2006-01-02", tok.Text)
+		if err != nil {
+			return nil, syntaxError(tok.Pos, "invalid date "+quote(tok.Text))
+		}
+		return &Literal{Value: value}, nil
+	case catalog.TypeTimestamp:
+		for _, layout := range timestampLayouts {
+			value, err := time.Parse(layout, tok.Text)
+			if err == nil {
+				return &Literal{Value: value.UTC()}, nil
+			}
+		}
+		return nil, syntaxError(tok.Pos, "invalid timestamp "+quote(tok.Text))
+	case catalog.TypeUUID:
+		value, err := catalog.ParseUUID(tok.Text)
+		if err != nil {
+			return nil, syntaxError(tok.Pos, "invalid uuid "+quote(tok.Text))
+		}
+		return &Literal{Value: value}, nil
+	}
+
+	return nil, syntaxError(typeTok.Pos, "unknown literal type "+quote(typeTok.Text))
+}
+
+func (p *parser) parseCase() (Expr, error) {
+	p.next()
+
+	expr := &CaseExpr{}
+	if !p.isKeyword("WHEN") && !p.isKeyword("END") {
+		operand, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Operand = operand
+	}
+
+	for p.acceptKeyword("WHEN") {
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expectKeyword("THEN")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Whens = append(expr.Whens, WhenClause{Cond: cond, Result: result})
+	}
+
+	if len(expr.Whens) == 0 {
+		return nil, p.unexpected("WHEN")
+	}
+
+	if p.acceptKeyword("ELSE") {
+		elseExpr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Else = elseExpr
+	}
+
+	return expr, p.expectKeyword("END")
+}
+
+func (p *parser) parseExprList() ([]Expr, error) {
+	err := p.expectSymbol("(")
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	return exprs, p.expectSymbol(")")
+}
+
+func parseNumber(tok Token) (Expr, error) {
+	if tok.Type == TokenInteger {
+		value, err := strconv.ParseInt(tok.Text, 10, 64)
+		if err != nil {
+			return nil, syntaxError(tok.Pos, "integer "+tok.Text+" out of range")
+		}
+		return &Literal{Value: int(value)}, nil
+	}
+
+	if strings.ContainsAny(tok.Text, "eE") {
+		value, err := strconv.ParseFloat(tok.Text, 64)
+		if err != nil {
+			return nil, syntaxError(tok.Pos, "number "+tok.Text+" out of range")
+		}
+		return &Literal{Value: value}, nil
+	}
+
+	value, err := catalog.ParseDecimal(tok.Text)
+	if err != nil {
+		return nil, syntaxError(tok.Pos, "number "+tok.Text+" out of range")
+	}
+
+	return &Literal{Value: value}, nil
+}
@@ -0,0 +1,144 @@
+package sql
+
+import "strconv"
+
+func syntaxError(pos Position, message string) error {
+	return &SyntaxError{Pos: pos, Message: message}
+}
+
+func quote(text string) string {
+	return strconv.Quote(text)
+}
+
+func describe(tok Token) string {
+	switch tok.Type {
+	case TokenEOF:
+		return "end of input"
+	case TokenString:
+		return "string " + quote(tok.Text)
+	case TokenBlob:
+		return "blob literal"
+	}
+
+	return quote(tok.Text)
+}
+
+func (p *parser) peek() Token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(n int) Token {
+	if p.index+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.index+n]
+}
+
+func (p *parser) next() Token {
+	tok := p.peek()
+	if tok.Type != TokenEOF {
+		p.index++
+	}
+
+	return tok
+}
+
+func (p *parser) unexpected(expected string) error {
+	tok := p.peek()
+	return syntaxError(tok.Pos, "expected "+expected+", got "+describe(tok))
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.Type == TokenKeyword && tok.Text == keyword
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if !p.isKeyword(keyword) {
+		return false
+	}
+
+	p.next()
+	return true
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.unexpected(keyword)
+	}
+
+	return nil
+}
+
+func (p *parser) isSymbol(symbol string) bool {
+	return isSymbolToken(p.peek(), symbol)
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if !p.isSymbol(symbol) {
+		return false
+	}
+
+	p.next()
+	return true
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected(quote(symbol))
+	}
+
+	return nil
+}
+
+func (p *parser) expectIdent(what string) (string, error) {
+	tok := p.peek()
+	if tok.Type != TokenIdent {
+		return "", p.unexpected(what)
+	}
+
+	p.next()
+	return tok.Text, nil
+}
+
+func (p *parser) parseIdentList(what string) ([]string, error) {
+	err := p.expectSymbol("(")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for {
+		name, err := p.expectIdent(what)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	return names, p.expectSymbol(")")
+}
+
+func (p *parser) parseCount(what string) (int, error) {
+	tok := p.peek()
+	if tok.Type != TokenInteger {
+		return 0, p.unexpected(what)
+	}
+
+	value, err := strconv.Atoi(tok.Text)
+	if err != nil {
+		return 0, syntaxError(tok.Pos, what+" out of range")
+	}
+
+	p.next()
+	return value, nil
+}
+
+func isSymbolToken(tok Token, symbol string) bool {
+	return tok.Type == TokenSymbol && tok.Text == symbol
+}
@@ -0,0 +1,228 @@
+package sql
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var keywords = map[string]bool{
+	"AND": true, "AS": true, "ASC": true, "BY": true, "CASE": true, "CREATE": true,
+	"CROSS": true, "DELETE": true, "DESC": true, "DROP": true, "ELSE": true, "END": true,
+	"FALSE": true, "FROM": true, "IN": true, "INDEX": true, "INNER": true, "INSERT": true,
+	"INTO": true, "IS": true, "JOIN": true, "KEY": true, "LEFT": true, "LIKE": true,
+	"LIMIT": true, "NOT": true, "NULL": true, "OFFSET": true, "ON": true, "OR": true,
+	"ORDER": true, "OUTER": true, "PRIMARY": true, "SELECT": true, "SET": true, "TABLE": true,
+	"THEN": true, "TRUE": true, "UNIQUE": true, "UPDATE": true, "VALUES": true, "WHEN": true,
+	"WHERE": true,
+}
+
+var symbols = []string{"<=", ">=", "<>", "!=", "(", ")", ",", ";", ".", "*", "+", "-", "/", "%", "=", "<", ">"}
+
+func tokenize(input string) ([]Token, error) {
+	l := newLexer(input)
+
+	for {
+		err := l.skipSpaceAndComments()
+		if err != nil {
+			return nil, err
+		}
+
+		if l.done() {
+			l.emit(TokenEOF, "", l.pos)
+			return l.tokens, nil
+		}
+
+		err = l.lexToken()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (l *lexer) lexToken() error {
+	start := l.pos
+	r := l.peek()
+
+	switch {
+	case (r == 'x' || r == 'X') && l.peekAt(1) == '\'':
+		l.advance()
+		text, err := l.lexQuoted('\'', "blob literal")
+		if err != nil {
+			return err
+		}
+		l.emit(TokenBlob, text, start)
+	case unicode.IsLetter(r) || r == '_':
+		text := l.consumeWhile(func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+		})
+		if upper := strings.ToUpper(text); keywords[upper] {
+			l.emit(TokenKeyword, upper, start)
+		} else {
+			l.emit(TokenIdent, text, start)
+		}
+	case isDigit(r) || r == '.' && isDigit(l.peekAt(1)):
+		return l.lexNumber(start)
+	case r == '\'':
+		text, err := l.lexQuoted('\'', "string")
+		if err != nil {
+			return err
+		}
+		l.emit(TokenString, text, start)
+	case r == '"':
+		text, err := l.lexQuoted('"', "quoted identifier")
+		if err != nil {
+			return err
+		}
+		if text == "" {
+			return syntaxError(start, "empty quoted identifier")
+		}
+		l.emit(TokenIdent, text, start)
+	default:
+		for _, symbol := range symbols {
+			if strings.HasPrefix(l.input[l.pos.Offset:], symbol) {
+				for range symbol {
+					l.advance()
+				}
+				l.emit(TokenSymbol, symbol, start)
+				return nil
+			}
+		}
+		return syntaxError(start, "unexpected character "+quote(string(r)))
+	}
+
+	return nil
+}
+
+func (l *lexer) lexNumber(start Position) error {
+	tokenType := TokenInteger
+	l.consumeWhile(isDigit)
+
+	if l.peek() == '.' {
+		tokenType = TokenNumber
+		l.advance()
+		l.consumeWhile(isDigit)
+	}
+
+	if r := l.peek(); r == 'e' || r == 'E' {
+		tokenType = TokenNumber
+		l.advance()
+		if r := l.peek(); r == '+' || r == '-' {
+			l.advance()
+		}
+		if !isDigit(l.peek()) {
+			return syntaxError(start, "malformed number")
+		}
+		l.consumeWhile(isDigit)
+	}
+
+	if r := l.peek(); unicode.IsLetter(r) || r == '_' {
+		return syntaxError(start, "malformed number")
+	}
+
+	l.emit(tokenType, l.input[start.Offset:l.pos.Offset], start)
+	return nil
+}
+
+// A doubled quote inside the literal stands for the quote character itself.
+func (l *lexer) lexQuoted(quoteChar rune, what string) (string, error) {
+	start := l.pos
+	l.advance()
+
+	var text strings.Builder
+	for {
+		if l.done() {
+			return "", syntaxError(start, "unterminated "+what)
+		}
+
+		r := l.advance()
+		if r == quoteChar {
+			if l.peek() != quoteChar {
+				return text.String(), nil
+			}
+			l.advance()
+		}
+		text.WriteRune(r)
+	}
+}
+
+func (l *lexer) skipSpaceAndComments() error {
+	for !l.done() {
+		switch r := l.peek(); {
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '-' && l.peekAt(1) == '-':
+			l.consumeWhile(func(r rune) bool { return r != '\n' })
+		case r == '/' && l.peekAt(1) == '*':
+			start := l.pos
+			l.advance()
+			l.advance()
+			for !(l.peek() == '*' && l.peekAt(1) == '/') {
+				if l.done() {
+					return syntaxError(start, "unterminated comment")
+				}
+				l.advance()
+			}
+			l.advance()
+			l.advance()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (l *lexer) emit(tokenType TokenType, text string, pos Position) {
+	l.tokens = append(l.tokens, Token{Type: tokenType, Text: text, Pos: pos})
+}
+
+func (l *lexer) done() bool {
+	return l.pos.Offset >= len(l.input)
+}
+
+func (l *lexer) peek() rune {
+	return l.peekAt(0)
+}
+
+func (l *lexer) peekAt(n int) rune {
+	offset := l.pos.Offset
+	for ; n > 0 && offset < len(l.input); n-- {
+		_, size := utf8.DecodeRuneInString(l.input[offset:])
+		offset += size
+	}
+
+	if offset >= len(l.input) {
+		return 0
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.input[offset:])
+	return r
+}
+
+func (l *lexer) advance() rune {
+	r, size := utf8.DecodeRuneInString(l.input[l.pos.Offset:])
+	l.pos.Offset += size
+
+	if r == '\n' {
+		l.pos.Line++
+		l.pos.Column = 1
+	} else {
+		l.pos.Column++
+	}
+
+	return r
+}
+
+func (l *lexer) consumeWhile(accept func(r rune) bool) string {
+	start := l.pos.Offset
+	for !l.done() && accept(l.peek()) {
+		l.advance()
+	}
+
+	return l.input[start:l.pos.Offset]
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
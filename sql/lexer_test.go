@@ -0,0 +1,58 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize("select \"Name\", x'0aFF' FROM t -- comment\nWHERE a<>'it''s' /* block */ AND b >= 1.5e3;")
+	require.NoError(t, err)
+
+	expected := []Token{
+		{Type: TokenKeyword, Text: "SELECT", Pos: Position{Offset: 0, Line: 1, Column: 1}},
+		{Type: TokenIdent, Text: "Name", Pos: Position{Offset: 7, Line: 1, Column: 8}},
+		{Type: TokenSymbol, Text: ",", Pos: Position{Offset: 13, Line: 1, Column: 14}},
+		{Type: TokenBlob, Text: "0aFF", Pos: Position{Offset: 15, Line: 1, Column: 16}},
+		{Type: TokenKeyword, Text: "FROM", Pos: Position{Offset: 23, Line: 1, Column: 24}},
+		{Type: TokenIdent, Text: "t", Pos: Position{Offset: 28, Line: 1, Column: 29}},
+		{Type: TokenKeyword, Text: "WHERE", Pos: Position{Offset: 41, Line: 2, Column: 1}},
+		{Type: TokenIdent, Text: "a", Pos: Position{Offset: 47, Line: 2, Column: 7}},
+		{Type: TokenSymbol, Text: "<>", Pos: Position{Offset: 48, Line: 2, Column: 8}},
+		{Type: TokenString, Text: "it's", Pos: Position{Offset: 50, Line: 2, Column: 10}},
+		{Type: TokenKeyword, Text: "AND", Pos: Position{Offset: 70, Line: 2, Column: 30}},
+		{Type: TokenIdent, Text: "b", Pos: Position{Offset: 74, Line: 2, Column: 34}},
+		{Type: TokenSymbol, Text: ">=", Pos: Position{Offset: 76, Line: 2, Column: 36}},
+		{Type: TokenNumber, Text: "1.5e3", Pos: Position{Offset: 79, Line: 2, Column: 39}},
+		{Type: TokenSymbol, Text: ";", Pos: Position{Offset: 84, Line: 2, Column: 44}},
+		{Type: TokenEOF, Pos: Position{Offset: 85, Line: 2, Column: 45}},
+	}
+	assert.Equal(t, expected, tokens)
+}
+
+func TestTokenize_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		pos     Position
+	}{
+		{"SELECT 'abc", "unterminated string", Position{Offset: 7, Line: 1, Column: 8}},
+		{"SELECT \"abc", "unterminated quoted identifier", Position{Offset: 7, Line: 1, Column: 8}},
+		{"SELECT 1\n  /* open", "unterminated comment", Position{Offset: 11, Line: 2, Column: 3}},
+		{"SELECT 12abc", "malformed number", Position{Offset: 7, Line: 1, Column: 8}},
+		{"SELECT 1e+", "malformed number", Position{Offset: 7, Line: 1, Column: 8}},
+		{"SELECT a # b", `unexpected character "#"`, Position{Offset: 9, Line: 1, Column: 10}},
+	}
+
+	for _, tt := range tests {
+		_, err := tokenize(tt.input)
+		require.ErrorIs(t, err, ErrSyntax, tt.input)
+
+		var syntaxErr *SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		assert.Equal(t, tt.message, syntaxErr.Message, tt.input)
+		assert.Equal(t, tt.pos, syntaxErr.Pos, tt.input)
+	}
+}
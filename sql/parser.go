@@ -0,0 +1,657 @@
+package sql
+
+import (
+	"math"
+	"strings"
+
+	"gobase/catalog"
+)
+
+func Parse(query string) (Statement, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(tokens)
+	stmt, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	p.acceptSymbol(";")
+	if p.peek().Type != TokenEOF {
+		return nil, p.unexpected("end of statement")
+	}
+
+	return stmt, nil
+}
+
+func ParseAll(script string) ([]Statement, error) {
+	tokens, err := tokenize(script)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(tokens)
+	statements := []Statement{}
+
+	for {
+		for p.acceptSymbol(";") {
+		}
+		if p.peek().Type == TokenEOF {
+			return statements, nil
+		}
+
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+
+		if !p.isSymbol(";") && p.peek().Type != TokenEOF {
+			return nil, p.unexpected(`";"`)
+		}
+	}
+}
+
+func ParseExpr(text string) (Expr, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(tokens)
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().Type != TokenEOF {
+		return nil, p.unexpected("end of expression")
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseStatement() (Statement, error) {
+	switch {
+	case p.isKeyword("SELECT"):
+		return p.parseSelect()
+	case p.isKeyword("INSERT"):
+		return p.parseInsert()
+	case p.isKeyword("UPDATE"):
+		return p.parseUpdate()
+	case p.isKeyword("DELETE"):
+		return p.parseDelete()
+	case p.isKeyword("CREATE"):
+		p.next()
+		if p.isKeyword("TABLE") {
+			return p.parseCreateTable()
+		}
+		return p.parseCreateIndex()
+	case p.isKeyword("DROP"):
+		return p.parseDropTable()
+	}
+
+	return nil, p.unexpected("statement")
+}
+
+func (p *parser) parseCreateTable() (Statement, error) {
+	p.next()
+
+	name, err := p.expectIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.expectSymbol("(")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &CreateTableStatement{Table: name, Columns: []catalog.Column{}}
+	for {
+		if p.isKeyword("PRIMARY") {
+			err = p.parsePrimaryKeyConstraint(stmt)
+		} else {
+			err = p.parseColumnDefinition(stmt)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	return stmt, p.expectSymbol(")")
+}
+
+func (p *parser) parseColumnDefinition(stmt *CreateTableStatement) error {
+	tok := p.peek()
+	name, err := p.expectIdent("column name")
+	if err != nil {
+		return err
+	}
+
+	if findColumn(stmt.Columns, name) != -1 {
+		return syntaxError(tok.Pos, "duplicate column "+quote(name))
+	}
+
+	col := catalog.Column{Name: name, Nullable: true}
+	err = p.parseColumnType(&col)
+	if err != nil {
+		return err
+	}
+
+	for {
+		switch {
+		case p.acceptKeyword("NOT"):
+			err = p.expectKeyword("NULL")
+			if err != nil {
+				return err
+			}
+			col.Nullable = false
+		case p.acceptKeyword("NULL"):
+			if col.PrimaryKey {
+				return syntaxError(tok.Pos, "primary key column "+quote(name)+" cannot be nullable")
+			}
+			col.Nullable = true
+		case p.acceptKeyword("PRIMARY"):
+			err = p.expectKeyword("KEY")
+			if err != nil {
+				return err
+			}
+			col.PrimaryKey = true
+			col.Nullable = false
+		case p.acceptKeyword("UNIQUE"):
+			col.Unique = true
+		default:
+			stmt.Columns = append(stmt.Columns, col)
+			return nil
+		}
+	}
+}
+
+func (p *parser) parsePrimaryKeyConstraint(stmt *CreateTableStatement) error {
+	p.next()
+
+	err := p.expectKeyword("KEY")
+	if err != nil {
+		return err
+	}
+
+	tok := p.peek()
+	names, err := p.parseIdentList("column name")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		i := findColumn(stmt.Columns, name)
+		if i == -1 {
+			return syntaxError(tok.Pos, "unknown column "+quote(name)+" in primary key")
+		}
+		stmt.Columns[i].PrimaryKey = true
+		stmt.Columns[i].Nullable = false
+	}
+
+	return nil
+}
+
+func (p *parser) parseColumnType(col *catalog.Column) error {
+	tok := p.peek()
+	if tok.Type != TokenIdent {
+		return p.unexpected("column type")
+	}
+	p.next()
+
+	var err error
+	switch strings.ToUpper(tok.Text) {
+	case "INT", "INTEGER":
+		col.Type = catalog.TypeInt
+	case "SMALLINT":
+		col.Type = catalog.TypeSmallInt
+	case "BOOLEAN", "BOOL":
+		col.Type = catalog.TypeBoolean
+	case "VARCHAR":
+		col.Type = catalog.TypeVarchar
+		col.Size, err = p.parseSize()
+	case "TEXT":
+		col.Type = catalog.TypeVarchar
+	case "BIGINT":
+		col.Type = catalog.TypeBigInt
+	case "DOUBLE":
+		col.Type = catalog.TypeDouble
+		if next := p.peek(); next.Type == TokenIdent && strings.EqualFold(next.Text, "PRECISION") {
+			p.next()
+		}
+	case "FLOAT", "REAL":
+		col.Type = catalog.TypeDouble
+	case "DECIMAL", "NUMERIC":
+		col.Type = catalog.TypeDecimal
+		err = p.parsePrecision(col)
+	case "TIMESTAMP":
+		col.Type = catalog.TypeTimestamp
+	case "DATE":
+		col.Type = catalog.TypeDate
+	case "UUID":
+		col.Type = catalog.TypeUUID
+	case "BLOB":
+		col.Type = catalog.TypeBlob
+		col.Size, err = p.parseSize()
+	case "BYTEA":
+		col.Type = catalog.TypeBlob
+	default:
+		return syntaxError(tok.Pos, "unknown column type "+quote(tok.Text))
+	}
+
+	return err
+}
+
+func (p *parser) parseSize() (uint16, error) {
+	if !p.acceptSymbol("(") {
+		return 0, nil
+	}
+
+	tok := p.peek()
+	size, err := p.parseCount("size")
+	if err != nil {
+		return 0, err
+	}
+	if size == 0 || size > math.MaxUint16 {
+		return 0, syntaxError(tok.Pos, "size must be between 1 and 65535")
+	}
+
+	return uint16(size), p.expectSymbol(")")
+}
+
+func (p *parser) parsePrecision(col *catalog.Column) error {
+	if !p.acceptSymbol("(") {
+		return nil
+	}
+
+	tok := p.peek()
+	precision, err := p.parseCount("precision")
+	if err != nil {
+		return err
+	}
+	if precision == 0 || precision > int(catalog.MAX_DECIMAL_PRECISION) {
+		return syntaxError(tok.Pos, "precision must be between 1 and 18")
+	}
+	col.Precision = uint8(precision)
+
+	if p.acceptSymbol(",") {
+		tok = p.peek()
+		scale, err := p.parseCount("scale")
+		if err != nil {
+			return err
+		}
+		if scale > precision {
+			return syntaxError(tok.Pos, "scale cannot exceed precision")
+		}
+		col.Scale = uint8(scale)
+	}
+
+	return p.expectSymbol(")")
+}
+
+func (p *parser) parseDropTable() (Statement, error) {
+	p.next()
+
+	err := p.expectKeyword("TABLE")
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+
+	return &DropTableStatement{Table: name}, nil
+}
+
+func (p *parser) parseCreateIndex() (Statement, error) {
+	stmt := &CreateIndexStatement{Unique: p.acceptKeyword("UNIQUE")}
+
+	err := p.expectKeyword("INDEX")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Name, err = p.expectIdent("index name")
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.expectKeyword("ON")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Table, err = p.expectIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Columns, err = p.parseIdentList("column name")
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseInsert() (Statement, error) {
+	p.next()
+
+	err := p.expectKeyword("INTO")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &InsertStatement{}
+	stmt.Table, err = p.expectIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isSymbol("(") {
+		stmt.Columns, err = p.parseIdentList("column name")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.expectKeyword("VALUES")
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		row, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		if len(stmt.Rows) > 0 && len(row) != len(stmt.Rows[0]) {
+			return nil, syntaxError(tok.Pos, "all VALUES rows must have the same number of values")
+		}
+		stmt.Rows = append(stmt.Rows, row)
+
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+func (p *parser) parseSelect() (Statement, error) {
+	p.next()
+
+	stmt := &SelectStatement{}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Items = append(stmt.Items, item)
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if p.acceptKeyword("FROM") {
+		from, err := p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+		stmt.From = &from
+
+		stmt.Joins, err = p.parseJoins()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	stmt.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("ORDER") {
+		stmt.OrderBy, err = p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		limit, err := p.parseCount("limit")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Limit = &limit
+
+		if p.acceptKeyword("OFFSET") {
+			offset, err := p.parseCount("offset")
+			if err != nil {
+				return nil, err
+			}
+			stmt.Offset = &offset
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseSelectItem() (SelectItem, error) {
+	if p.acceptSymbol("*") {
+		return SelectItem{Expr: &Star{}}, nil
+	}
+
+	if tok := p.peek(); tok.Type == TokenIdent && isSymbolToken(p.peekAt(1), ".") && isSymbolToken(p.peekAt(2), "*") {
+		p.next()
+		p.next()
+		p.next()
+		return SelectItem{Expr: &Star{Table: tok.Text}}, nil
+	}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return SelectItem{}, err
+	}
+
+	alias, err := p.parseAlias()
+	if err != nil {
+		return SelectItem{}, err
+	}
+
+	return SelectItem{Expr: expr, Alias: alias}, nil
+}
+
+func (p *parser) parseTableRef() (TableRef, error) {
+	name, err := p.expectIdent("table name")
+	if err != nil {
+		return TableRef{}, err
+	}
+
+	alias, err := p.parseAlias()
+	if err != nil {
+		return TableRef{}, err
+	}
+
+	return TableRef{Name: name, Alias: alias}, nil
+}
+
+func (p *parser) parseAlias() (string, error) {
+	if p.acceptKeyword("AS") {
+		return p.expectIdent("alias")
+	}
+
+	if p.peek().Type == TokenIdent {
+		return p.next().Text, nil
+	}
+
+	return "", nil
+}
+
+func (p *parser) parseJoins() ([]Join, error) {
+	joins := []Join{}
+
+	for {
+		var join Join
+		switch {
+		case p.isKeyword("JOIN"):
+			join.Type = JoinInner
+		case p.acceptKeyword("INNER"):
+			join.Type = JoinInner
+		case p.acceptKeyword("LEFT"):
+			join.Type = JoinLeft
+			p.acceptKeyword("OUTER")
+		case p.acceptKeyword("CROSS"):
+			join.Type = JoinCross
+		default:
+			return joins, nil
+		}
+
+		err := p.expectKeyword("JOIN")
+		if err != nil {
+			return nil, err
+		}
+
+		join.Table, err = p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+
+		if join.Type != JoinCross {
+			err = p.expectKeyword("ON")
+			if err != nil {
+				return nil, err
+			}
+
+			join.On, err = p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		joins = append(joins, join)
+	}
+}
+
+func (p *parser) parseOrderBy() ([]OrderItem, error) {
+	err := p.expectKeyword("BY")
+	if err != nil {
+		return nil, err
+	}
+
+	items := []OrderItem{}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		item := OrderItem{Expr: expr}
+		if !p.acceptKeyword("ASC") {
+			item.Desc = p.acceptKeyword("DESC")
+		}
+		items = append(items, item)
+
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+func (p *parser) parseUpdate() (Statement, error) {
+	p.next()
+
+	stmt := &UpdateStatement{}
+
+	var err error
+	stmt.Table, err = p.expectIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.expectKeyword("SET")
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		column, err := p.expectIdent("column name")
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expectSymbol("=")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Assignments = append(stmt.Assignments, Assignment{Column: column, Value: value})
+
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	stmt.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseDelete() (Statement, error) {
+	p.next()
+
+	err := p.expectKeyword("FROM")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &DeleteStatement{}
+	stmt.Table, err = p.expectIdent("table name")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseWhere() (Expr, error) {
+	if !p.acceptKeyword("WHERE") {
+		return nil, nil
+	}
+
+	return p.parseExpr()
+}
+
+func findColumn(columns []catalog.Column, name string) int {
+	for i, col := range columns {
+		if col.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
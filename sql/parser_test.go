@@ -0,0 +1,241 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"gobase/catalog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(val int) *int {
+	return &val
+}
+
+func TestParse_CreateTable(t *testing.T) {
+	stmt, err := Parse(`CREATE TABLE users (
+		id INT,
+		name VARCHAR(50) NOT NULL,
+		email TEXT UNIQUE,
+		balance DECIMAL(10, 2),
+		score DOUBLE PRECISION NULL,
+		avatar BLOB(1024),
+		created_at TIMESTAMP,
+		PRIMARY KEY (id)
+	)`)
+	require.NoError(t, err)
+
+	expected := &CreateTableStatement{
+		Table: "users",
+		Columns: []catalog.Column{
+			{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+			{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+			{Name: "email", Type: catalog.TypeVarchar, Nullable: true, Unique: true},
+			{Name: "balance", Type: catalog.TypeDecimal, Precision: 10, Scale: 2, Nullable: true},
+			{Name: "score", Type: catalog.TypeDouble, Nullable: true},
+			{Name: "avatar", Type: catalog.TypeBlob, Size: 1024, Nullable: true},
+			{Name: "created_at", Type: catalog.TypeTimestamp, Nullable: true},
+		},
+	}
+	assert.Equal(t, expected, stmt)
+}
+
+func TestParse_CreateIndexAndDropTable(t *testing.T) {
+	statements, err := ParseAll("CREATE UNIQUE INDEX users_email ON users (email, name);; DROP TABLE users;")
+	require.NoError(t, err)
+
+	expected := []Statement{
+		&CreateIndexStatement{Name: "users_email", Table: "users", Unique: true, Columns: []string{"email", "name"}},
+		&DropTableStatement{Table: "users"},
+	}
+	assert.Equal(t, expected, statements)
+}
+
+func TestParse_Insert(t *testing.T) {
+	stmt, err := Parse("INSERT INTO users (id, name, joined) VALUES (1, 'Alice', DATE '2024-03-01'), (-2, NULL, NULL)")
+	require.NoError(t, err)
+
+	expected := &InsertStatement{
+		Table:   "users",
+		Columns: []string{"id", "name", "joined"},
+		Rows: [][]Expr{
+			{&Literal{Value: 1}, &Literal{Value: "Alice"}, &Literal{Value: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+			{&Literal{Value: -2}, &Literal{Value: nil}, &Literal{Value: nil}},
+		},
+	}
+	assert.Equal(t, expected, stmt)
+}
+
+func TestParse_Select(t *testing.T) {
+	stmt, err := Parse(`SELECT u.*, o.total * 2 AS doubled, name
+		FROM users u
+		JOIN orders AS o ON o.user_id = u.id
+		LEFT OUTER JOIN refunds r ON r.order_id = o.id
+		CROSS JOIN regions
+		WHERE u.age >= 18 AND NOT r.id IS NOT NULL
+		ORDER BY name, doubled DESC
+		LIMIT 10 OFFSET 20`)
+	require.NoError(t, err)
+
+	expected := &SelectStatement{
+		Items: []SelectItem{
+			{Expr: &Star{Table: "u"}},
+			{Expr: &BinaryExpr{Op: OpMul, Left: &ColumnRef{Table: "o", Column: "total"}, Right: &Literal{Value: 2}}, Alias: "doubled"},
+			{Expr: &ColumnRef{Column: "name"}},
+		},
+		From: &TableRef{Name: "users", Alias: "u"},
+		Joins: []Join{
+			{Type: JoinInner, Table: TableRef{Name: "orders", Alias: "o"}, On: &BinaryExpr{Op: OpEq, Left: &ColumnRef{Table: "o", Column: "user_id"}, Right: &ColumnRef{Table: "u", Column: "id"}}},
+			{Type: JoinLeft, Table: TableRef{Name: "refunds", Alias: "r"}, On: &BinaryExpr{Op: OpEq, Left: &ColumnRef{Table: "r", Column: "order_id"}, Right: &ColumnRef{Table: "o", Column: "id"}}},
+			{Type: JoinCross, Table: TableRef{Name: "regions"}},
+		},
+		Where: &BinaryExpr{
+			Op:    OpAnd,
+			Left:  &BinaryExpr{Op: OpGtEq, Left: &ColumnRef{Table: "u", Column: "age"}, Right: &Literal{Value: 18}},
+			Right: &UnaryExpr{Op: OpNot, Operand: &IsNullExpr{Expr: &ColumnRef{Table: "r", Column: "id"}, Not: true}},
+		},
+		OrderBy: []OrderItem{
+			{Expr: &ColumnRef{Column: "name"}},
+			{Expr: &ColumnRef{Column: "doubled"}, Desc: true},
+		},
+		Limit:  intPtr(10),
+		Offset: intPtr(20),
+	}
+	assert.Equal(t, expected, stmt)
+}
+
+func TestParse_UpdateAndDelete(t *testing.T) {
+	stmt, err := Parse("UPDATE users SET age = age + 1, name = 'Bob' WHERE id IN (1, 2)")
+	require.NoError(t, err)
+
+	expected := &UpdateStatement{
+		Table: "users",
+		Assignments: []Assignment{
+			{Column: "age", Value: &BinaryExpr{Op: OpAdd, Left: &ColumnRef{Column: "age"}, Right: &Literal{Value: 1}}},
+			{Column: "name", Value: &Literal{Value: "Bob"}},
+		},
+		Where: &InExpr{Expr: &ColumnRef{Column: "id"}, List: []Expr{&Literal{Value: 1}, &Literal{Value: 2}}},
+	}
+	assert.Equal(t, expected, stmt)
+
+	stmt, err = Parse("DELETE FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteStatement{Table: "users"}, stmt)
+}
+
+func TestParse_ExpressionPrecedence(t *testing.T) {
+	stmt, err := Parse("SELECT 1 + 2 * -x, a OR b AND c, (1 + 2) * 3 FROM t")
+	require.NoError(t, err)
+
+	items := stmt.(*SelectStatement).Items
+	assert.Equal(t, &BinaryExpr{
+		Op:    OpAdd,
+		Left:  &Literal{Value: 1},
+		Right: &BinaryExpr{Op: OpMul, Left: &Literal{Value: 2}, Right: &UnaryExpr{Op: OpNeg, Operand: &ColumnRef{Column: "x"}}},
+	}, items[0].Expr)
+	assert.Equal(t, &BinaryExpr{
+		Op:    OpOr,
+		Left:  &ColumnRef{Column: "a"},
+		Right: &BinaryExpr{Op: OpAnd, Left: &ColumnRef{Column: "b"}, Right: &ColumnRef{Column: "c"}},
+	}, items[1].Expr)
+	assert.Equal(t, &BinaryExpr{
+		Op:    OpMul,
+		Left:  &BinaryExpr{Op: OpAdd, Left: &Literal{Value: 1}, Right: &Literal{Value: 2}},
+		Right: &Literal{Value: 3},
+	}, items[2].Expr)
+}
+
+func TestParse_Literals(t *testing.T) {
+	stmt, err := Parse("SELECT 12.50, 1e3, TRUE, X'CAFE', UUID '0123456789abcdef0123456789abcdef', TIMESTAMP '2024-03-01 10:30:00.5'")
+	require.NoError(t, err)
+
+	values := []any{}
+	for _, item := range stmt.(*SelectStatement).Items {
+		values = append(values, item.Expr.(*Literal).Value)
+	}
+
+	uuid, err := catalog.ParseUUID("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+
+	expected := []any{
+		catalog.NewDecimal(1250, 2),
+		float64(1000),
+		true,
+		[]byte{0xCA, 0xFE},
+		uuid,
+		time.Date(2024, 3, 1, 10, 30, 0, 500000000, time.UTC),
+	}
+	assert.Equal(t, expected, values)
+}
+
+func TestParse_Case(t *testing.T) {
+	stmt, err := Parse("SELECT CASE WHEN name NOT LIKE 'A%' THEN 1 ELSE 0 END, CASE kind WHEN 1 THEN 'one' END FROM t")
+	require.NoError(t, err)
+
+	items := stmt.(*SelectStatement).Items
+	assert.Equal(t, &CaseExpr{
+		Whens: []WhenClause{{Cond: &LikeExpr{Expr: &ColumnRef{Column: "name"}, Pattern: &Literal{Value: "A%"}, Not: true}, Result: &Literal{Value: 1}}},
+		Else:  &Literal{Value: 0},
+	}, items[0].Expr)
+	assert.Equal(t, &CaseExpr{
+		Operand: &ColumnRef{Column: "kind"},
+		Whens:   []WhenClause{{Cond: &Literal{Value: 1}, Result: &Literal{Value: "one"}}},
+	}, items[1].Expr)
+}
+
+func TestParseExpr(t *testing.T) {
+	expr, err := ParseExpr("age > 25 AND name LIKE 'A%'")
+	require.NoError(t, err)
+	assert.Equal(t, &BinaryExpr{
+		Op:    OpAnd,
+		Left:  &BinaryExpr{Op: OpGt, Left: &ColumnRef{Column: "age"}, Right: &Literal{Value: 25}},
+		Right: &LikeExpr{Expr: &ColumnRef{Column: "name"}, Pattern: &Literal{Value: "A%"}},
+	}, expr)
+
+	_, err = ParseExpr("age > 25 FROM t")
+	require.ErrorIs(t, err, ErrSyntax)
+	assert.Equal(t, `syntax error at line 1, column 10: expected end of expression, got "FROM"`, err.Error())
+}
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		message string
+		pos     Position
+	}{
+		{"SELEC * FROM t", `expected statement, got "SELEC"`, Position{Offset: 0, Line: 1, Column: 1}},
+		{"SELECT * FROM", "expected table name, got end of input", Position{Offset: 13, Line: 1, Column: 14}},
+		{"SELECT a FROM t WHERE", "expected expression, got end of input", Position{Offset: 21, Line: 1, Column: 22}},
+		{"SELECT a FROM t\nLIMIT x", "expected limit, got \"x\"", Position{Offset: 22, Line: 2, Column: 7}},
+		{"SELECT a b c", `expected end of statement, got "c"`, Position{Offset: 11, Line: 1, Column: 12}},
+		{"CREATE TABLE t (id STRING)", `unknown column type "STRING"`, Position{Offset: 19, Line: 1, Column: 20}},
+		{"CREATE TABLE t (id INT, id INT)", `duplicate column "id"`, Position{Offset: 24, Line: 1, Column: 25}},
+		{"CREATE TABLE t (id INT, PRIMARY KEY (key))", `expected column name, got "KEY"`, Position{Offset: 37, Line: 1, Column: 38}},
+		{"CREATE TABLE t (id INT, PRIMARY KEY (other))", `unknown column "other" in primary key`, Position{Offset: 36, Line: 1, Column: 37}},
+		{"CREATE TABLE t (price DECIMAL(4, 6))", "scale cannot exceed precision", Position{Offset: 33, Line: 1, Column: 34}},
+		{"CREATE TABLE t (name VARCHAR(70000))", "size must be between 1 and 65535", Position{Offset: 29, Line: 1, Column: 30}},
+		{"INSERT INTO t VALUES (1, 2), (3)", "all VALUES rows must have the same number of values", Position{Offset: 29, Line: 1, Column: 30}},
+		{"SELECT 99999999999999999999", "integer 99999999999999999999 out of range", Position{Offset: 7, Line: 1, Column: 8}},
+		{"SELECT DATE '2024-13-01'", `invalid date "2024-13-01"`, Position{Offset: 12, Line: 1, Column: 13}},
+		{"SELECT CASE END", "expected WHEN, got \"END\"", Position{Offset: 12, Line: 1, Column: 13}},
+		{"SELECT 1; SELECT 2", `expected end of statement, got "SELECT"`, Position{Offset: 10, Line: 1, Column: 11}},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		require.ErrorIs(t, err, ErrSyntax, tt.query)
+
+		var syntaxErr *SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		assert.Equal(t, tt.message, syntaxErr.Message, tt.query)
+		assert.Equal(t, tt.pos, syntaxErr.Pos, tt.query)
+	}
+}
+
+func TestParseAll_RequiresSeparator(t *testing.T) {
+	_, err := ParseAll("DROP TABLE a DROP TABLE b")
+	require.ErrorIs(t, err, ErrSyntax)
+	assert.Equal(t, `syntax error at line 1, column 14: expected ";", got "DROP"`, err.Error())
+}
@@ -0,0 +1,40 @@
+package sql
+
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+type Token struct {
+	Type TokenType
+	Text string
+	Pos  Position
+}
+
+type SyntaxError struct {
+	Pos     Position
+	Message string
+}
+
+type lexer struct {
+	input  string
+	pos    Position
+	tokens []Token
+}
+
+type parser struct {
+	tokens []Token
+	index  int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{
+		input: input,
+		pos:   Position{Line: 1, Column: 1},
+	}
+}
+
+func newParser(tokens []Token) *parser {
+	return &parser{tokens: tokens}
+}
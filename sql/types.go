@@ -0,0 +1,115 @@
+package sql
+
+import "fmt"
+
+type TokenType uint8
+
+const (
+	TokenEOF TokenType = iota
+	TokenIdent
+	TokenKeyword
+	TokenInteger
+	TokenNumber
+	TokenString
+	TokenBlob
+	TokenSymbol
+)
+
+func (t TokenType) String() string {
+	switch t {
+	case TokenEOF:
+		return "EOF"
+	case TokenIdent:
+		return "IDENT"
+	case TokenKeyword:
+		return "KEYWORD"
+	case TokenInteger:
+		return "INTEGER"
+	case TokenNumber:
+		return "NUMBER"
+	case TokenString:
+		return "STRING"
+	case TokenBlob:
+		return "BLOB"
+	case TokenSymbol:
+		return "SYMBOL"
+	}
+
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(t))
+}
+
+type Operator uint8
+
+const (
+	OpAdd Operator = iota
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpEq
+	OpNotEq
+	OpLt
+	OpLtEq
+	OpGt
+	OpGtEq
+	OpAnd
+	OpOr
+	OpNot
+	OpNeg
+)
+
+func (op Operator) String() string {
+	switch op {
+	case OpAdd:
+		return "+"
+	case OpSub, OpNeg:
+		return "-"
+	case OpMul:
+		return "*"
+	case OpDiv:
+		return "/"
+	case OpMod:
+		return "%"
+	case OpEq:
+		return "="
+	case OpNotEq:
+		return "<>"
+	case OpLt:
+		return "<"
+	case OpLtEq:
+		return "<="
+	case OpGt:
+		return ">"
+	case OpGtEq:
+		return ">="
+	case OpAnd:
+		return "AND"
+	case OpOr:
+		return "OR"
+	case OpNot:
+		return "NOT"
+	}
+
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(op))
+}
+
+type JoinType uint8
+
+const (
+	JoinInner JoinType = iota
+	JoinLeft
+	JoinCross
+)
+
+func (j JoinType) String() string {
+	switch j {
+	case JoinInner:
+		return "INNER"
+	case JoinLeft:
+		return "LEFT"
+	case JoinCross:
+		return "CROSS"
+	}
+
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(j))
+}
@@ -0,0 +1,26 @@
+package system_catalog
+
+const (
+	TABLES_PAGE_ID_OFFSET  uint32 = 12
+	COLUMNS_PAGE_ID_OFFSET uint32 = 16
+	INDEXES_PAGE_ID_OFFSET uint32 = 20
+
+	MAX_NAME_LENGTH          uint16 = 64
+	MAX_COLUMNS_LENGTH       uint16 = 1024
+	INDEX_COLUMN_LENGTH_SIZE        = 2
+	INDEX_COLUMNS_SEPARATOR         = ","
+)
+
+// Baseline files had no header page and 4 KiB slotted pages linked by 16-bit
+// page IDs.
+const (
+	BASELINE_PAGE_SIZE                  = 4096
+	BASELINE_NUM_SLOTS_OFFSET           = 0
+	BASELINE_NEXT_PAGE_ID_OFFSET        = 4
+	BASELINE_HEADER_SIZE                = 8
+	BASELINE_SLOT_SIZE                  = 4
+	BASELINE_NULL_PAGE_ID        uint16 = 0xFFFF
+
+	UPGRADE_POOL_SIZE   = 64
+	UPGRADE_FILE_SUFFIX = ".upgrade"
+)
@@ -0,0 +1,13 @@
+package system_catalog
+
+import "errors"
+
+var (
+	ErrTableAlreadyExists = errors.New("table already exists")
+	ErrTableNotFound      = errors.New("table not found")
+	ErrNameTooLong        = errors.New("name too long")
+
+	ErrNotBaselineDatabase     = errors.New("file is not a baseline database")
+	ErrBaselineInvalidPage     = errors.New("baseline page has an unexpected layout")
+	ErrBaselineUnsupportedType = errors.New("baseline databases only store INT, SMALLINT, BOOLEAN and VARCHAR columns")
+)
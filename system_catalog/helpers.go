@@ -0,0 +1,268 @@
+package system_catalog
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"gobase/bplus_tree_index"
+	"gobase/catalog"
+	"gobase/log_manager"
+	"gobase/shared"
+	"gobase/table"
+	"gobase/table_heap"
+)
+
+func (c *Catalog) GetRootPageID() uint32 {
+	return c.rootPageID
+}
+
+func (c *Catalog) writeRoot() error {
+	frame, err := c.bpm.FetchPage(c.rootPageID)
+	if err != nil {
+		return err
+	}
+
+	frame.WLatch()
+	binary.LittleEndian.PutUint32(frame.Data[TABLES_PAGE_ID_OFFSET:], c.tables.GetHeaderPageID())
+	binary.LittleEndian.PutUint32(frame.Data[COLUMNS_PAGE_ID_OFFSET:], c.columns.GetHeaderPageID())
+	binary.LittleEndian.PutUint32(frame.Data[INDEXES_PAGE_ID_OFFSET:], c.indexes.GetHeaderPageID())
+
+	logManager := c.bpm.GetLogManager()
+	if logManager != nil {
+		var lsn uint64
+		lsn, err = logManager.AppendRecord(&log_manager.LogRecord{
+			Type:   log_manager.RecordPageImage,
+			PageID: c.rootPageID,
+			Data:   frame.Data,
+		})
+		if err == nil {
+			shared.SetPageLSN(frame.Data, lsn)
+		}
+	}
+
+	frame.WUnlatch()
+	c.bpm.UnpinPage(c.rootPageID, true)
+
+	return err
+}
+
+func (c *Catalog) loadTable(name string) (*table.Table, error) {
+	var heapPageID uint32
+	found := false
+
+	err := scanRows(c.tables, tablesSchema, func(_ *table_heap.RID, values []any) error {
+		if values[0] == name {
+			heapPageID = uint32(values[1].(int64))
+			found = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTableNotFound
+	}
+
+	schema, err := c.loadSchema(name)
+	if err != nil {
+		return nil, err
+	}
+
+	heap, err := table_heap.OpenTableHeap(c.bpm, heapPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := []*table.Index{}
+	err = scanRows(c.indexes, indexesSchema, func(_ *table_heap.RID, values []any) error {
+		if values[0] != name {
+			return nil
+		}
+
+		tree, err := bplus_tree_index.OpenBPlusTree(c.bpm, uint32(values[4].(int64)))
+		if err != nil {
+			return err
+		}
+
+		columns := decodeIndexColumns(values[3].([]byte))
+		index, err := table.OpenIndex(schema, values[1].(string), values[2].(bool), tree, columns...)
+		if err != nil {
+			return err
+		}
+
+		indexes = append(indexes, index)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return table.OpenTable(name, schema, heap, indexes...), nil
+}
+
+func (c *Catalog) loadSchema(tableName string) (*catalog.Schema, error) {
+	columnsByPosition := make(map[int]catalog.Column)
+
+	err := scanRows(c.columns, columnsSchema, func(_ *table_heap.RID, values []any) error {
+		if values[0] != tableName {
+			return nil
+		}
+
+		columnsByPosition[values[1].(int)] = catalog.Column{
+			Name:       values[2].(string),
+			Type:       catalog.ColumnType(values[3].(int)),
+			Size:       uint16(values[4].(int)),
+			Precision:  uint8(values[5].(int)),
+			Scale:      uint8(values[6].(int)),
+			Nullable:   values[7].(bool),
+			PrimaryKey: values[8].(bool),
+			Unique:     values[9].(bool),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]catalog.Column, len(columnsByPosition))
+	for position, col := range columnsByPosition {
+		columns[position] = col
+	}
+
+	return catalog.NewSchema(columns), nil
+}
+
+func (c *Catalog) saveTable(t *table.Table) error {
+	err := insertRow(c.tables, tablesSchema, t.Name, int64(t.Heap.GetHeaderPageID()))
+	if err != nil {
+		return err
+	}
+
+	for i, col := range t.Schema.Columns {
+		err = insertRow(c.columns, columnsSchema, t.Name, i, col.Name, int(col.Type), int(col.Size),
+			int(col.Precision), int(col.Scale), col.Nullable, col.PrimaryKey, col.Unique)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, index := range t.Indexes {
+		err = c.saveIndex(t.Name, index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) saveIndex(tableName string, index *table.Index) error {
+	columns := encodeIndexColumns(index.Columns)
+	return insertRow(c.indexes, indexesSchema, tableName, index.Name, index.Unique, columns, int64(index.Tree.GetHeaderPageID()))
+}
+
+func (c *Catalog) deleteRows(heap *table_heap.TableHeap, schema *catalog.Schema, tableName string) error {
+	rids := []table_heap.RID{}
+
+	err := scanRows(heap, schema, func(rid *table_heap.RID, values []any) error {
+		if values[0] == tableName {
+			rids = append(rids, *rid)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rid := range rids {
+		err = heap.Delete(nil, rid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func dropStorage(t *table.Table) error {
+	for _, index := range t.Indexes {
+		err := index.Tree.Drop()
+		if err != nil {
+			return err
+		}
+	}
+
+	return t.Heap.Drop()
+}
+
+func validateName(name string) error {
+	if len(name) > int(MAX_NAME_LENGTH) {
+		return ErrNameTooLong
+	}
+
+	return nil
+}
+
+func encodeIndexColumns(columns []string) []byte {
+	data := []byte{}
+	for _, column := range columns {
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(column)))
+		data = append(data, column...)
+	}
+
+	return data
+}
+
+// Catalogs written before the list was length-prefixed joined the names with
+// commas. Such a list never parses as length-prefixed: the first two bytes of
+// a printable name read as a length above MAX_COLUMNS_LENGTH.
+func decodeIndexColumns(data []byte) []string {
+	columns := []string{}
+
+	for offset := 0; offset < len(data); {
+		if offset+INDEX_COLUMN_LENGTH_SIZE > len(data) {
+			return strings.Split(string(data), INDEX_COLUMNS_SEPARATOR)
+		}
+
+		length := int(binary.LittleEndian.Uint16(data[offset:]))
+		offset += INDEX_COLUMN_LENGTH_SIZE
+		if length == 0 || offset+length > len(data) {
+			return strings.Split(string(data), INDEX_COLUMNS_SEPARATOR)
+		}
+
+		columns = append(columns, string(data[offset:offset+length]))
+		offset += length
+	}
+
+	if len(columns) == 0 {
+		return strings.Split(string(data), INDEX_COLUMNS_SEPARATOR)
+	}
+
+	return columns
+}
+
+func insertRow(heap *table_heap.TableHeap, schema *catalog.Schema, values ...any) error {
+	tuple, err := catalog.EncodeTuple(schema, values)
+	if err != nil {
+		return err
+	}
+
+	_, err = heap.Insert(nil, tuple)
+	return err
+}
+
+func scanRows(heap *table_heap.TableHeap, schema *catalog.Schema, fn func(rid *table_heap.RID, values []any) error) error {
+	iter := heap.Scan()
+	for {
+		rid, data, ok := iter.Next()
+		if !ok {
+			return iter.Err()
+		}
+
+		err := fn(rid, catalog.DecodeTuple(schema, data))
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package system_catalog
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"gobase/buffer_pool_manager"
+	"gobase/catalog"
+	"gobase/disk_manager"
+	"gobase/table"
+	"gobase/table_heap"
+)
+
+type Catalog struct {
+	mu         sync.Mutex
+	bpm        *buffer_pool_manager.BufferPoolManager
+	rootPageID uint32
+	tables     *table_heap.TableHeap
+	columns    *table_heap.TableHeap
+	indexes    *table_heap.TableHeap
+	cache      map[string]*table.Table
+}
+
+// The baseline code kept neither schemas nor the first page of a heap in the
+// file, so the caller supplies both for every table to upgrade.
+type BaselineTable struct {
+	Name        string
+	FirstPageID uint16
+	Schema      *catalog.Schema
+}
+
+// VARCHAR values are sized by their length prefix.
+var baselineValueSizes = map[catalog.ColumnType]int{
+	catalog.TypeInt:      4,
+	catalog.TypeSmallInt: 2,
+	catalog.TypeBoolean:  1,
+	catalog.TypeVarchar:  2,
+}
+
+var (
+	tablesSchema = catalog.NewSchema([]catalog.Column{
+		{Name: "name", Type: catalog.TypeVarchar, Size: MAX_NAME_LENGTH},
+		{Name: "heap_page_id", Type: catalog.TypeBigInt},
+	})
+
+	columnsSchema = catalog.NewSchema([]catalog.Column{
+		{Name: "table_name", Type: catalog.TypeVarchar, Size: MAX_NAME_LENGTH},
+		{Name: "position", Type: catalog.TypeSmallInt},
+		{Name: "name", Type: catalog.TypeVarchar, Size: MAX_NAME_LENGTH},
+		{Name: "type", Type: catalog.TypeSmallInt},
+		{Name: "size", Type: catalog.TypeInt},
+		{Name: "precision", Type: catalog.TypeSmallInt},
+		{Name: "scale", Type: catalog.TypeSmallInt},
+		{Name: "nullable", Type: catalog.TypeBoolean},
+		{Name: "primary_key", Type: catalog.TypeBoolean},
+		{Name: "unique", Type: catalog.TypeBoolean},
+	})
+
+	indexesSchema = catalog.NewSchema([]catalog.Column{
+		{Name: "table_name", Type: catalog.TypeVarchar, Size: MAX_NAME_LENGTH},
+		{Name: "name", Type: catalog.TypeVarchar, Size: MAX_NAME_LENGTH},
+		{Name: "unique", Type: catalog.TypeBoolean},
+		{Name: "columns", Type: catalog.TypeBlob, Size: MAX_COLUMNS_LENGTH},
+		{Name: "tree_page_id", Type: catalog.TypeBigInt},
+	})
+)
+
+func NewCatalog(bpm *buffer_pool_manager.BufferPoolManager) (*Catalog, error) {
+	rootPageID, _, err := bpm.NewPage()
+	if err != nil {
+		return nil, err
+	}
+	bpm.UnpinPage(rootPageID, true)
+
+	c := &Catalog{
+		bpm:        bpm,
+		rootPageID: rootPageID,
+		cache:      make(map[string]*table.Table),
+	}
+
+	for _, heap := range []**table_heap.TableHeap{&c.tables, &c.columns, &c.indexes} {
+		*heap, err = table_heap.NewTableHeap(bpm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.writeRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	err = bpm.GetDiskManager().SetCatalogRootPageID(rootPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func LoadCatalog(bpm *buffer_pool_manager.BufferPoolManager) (*Catalog, error) {
+	rootPageID := bpm.GetDiskManager().GetCatalogRootPageID()
+	if rootPageID == disk_manager.INVALID_PAGE_ID {
+		return NewCatalog(bpm)
+	}
+
+	return OpenCatalog(bpm, rootPageID)
+}
+
+func OpenCatalog(bpm *buffer_pool_manager.BufferPoolManager, rootPageID uint32) (*Catalog, error) {
+	frame, err := bpm.FetchPage(rootPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	frame.RLatch()
+	tablesPageID := binary.LittleEndian.Uint32(frame.Data[TABLES_PAGE_ID_OFFSET:])
+	columnsPageID := binary.LittleEndian.Uint32(frame.Data[COLUMNS_PAGE_ID_OFFSET:])
+	indexesPageID := binary.LittleEndian.Uint32(frame.Data[INDEXES_PAGE_ID_OFFSET:])
+	frame.RUnlatch()
+	bpm.UnpinPage(rootPageID, false)
+
+	c := &Catalog{
+		bpm:        bpm,
+		rootPageID: rootPageID,
+		cache:      make(map[string]*table.Table),
+	}
+
+	c.tables, err = table_heap.OpenTableHeap(bpm, tablesPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.columns, err = table_heap.OpenTableHeap(bpm, columnsPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.indexes, err = table_heap.OpenTableHeap(bpm, indexesPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,140 @@
+package system_catalog
+
+import (
+	"errors"
+
+	"gobase/catalog"
+	"gobase/table"
+	"gobase/table_heap"
+)
+
+func (c *Catalog) CreateTable(name string, schema *catalog.Schema) (*table.Table, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, col := range schema.Columns {
+		err = validateName(col.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = c.getTable(name)
+	if err == nil {
+		return nil, ErrTableAlreadyExists
+	}
+	if !errors.Is(err, ErrTableNotFound) {
+		return nil, err
+	}
+
+	heap, err := table_heap.NewTableHeap(c.bpm)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := table.NewTable(name, schema, heap)
+	if err != nil {
+		return nil, errors.Join(err, heap.Drop())
+	}
+
+	// The generated index names grow with the table and column names.
+	for _, index := range t.Indexes {
+		err = validateName(index.Name)
+		if err != nil {
+			return nil, errors.Join(err, dropStorage(t))
+		}
+	}
+
+	err = c.saveTable(t)
+	if err != nil {
+		return nil, errors.Join(err, c.deleteTableRows(name), dropStorage(t))
+	}
+
+	c.cache[name] = t
+	return t, nil
+}
+
+func (c *Catalog) GetTable(name string) (*table.Table, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.getTable(name)
+}
+
+func (c *Catalog) getTable(name string) (*table.Table, error) {
+	if t, exists := c.cache[name]; exists {
+		return t, nil
+	}
+
+	t, err := c.loadTable(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache[name] = t
+	return t, nil
+}
+
+func (c *Catalog) CreateIndex(tableName string, name string, unique bool, columns ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := validateName(name)
+	if err != nil {
+		return err
+	}
+
+	t, err := c.getTable(tableName)
+	if err != nil {
+		return err
+	}
+
+	if unique {
+		err = t.CreateUniqueIndex(name, columns...)
+	} else {
+		err = t.CreateIndex(name, columns...)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.saveIndex(tableName, t.Indexes[name])
+}
+
+func (c *Catalog) DropTable(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(name)
+	if err != nil {
+		return err
+	}
+
+	err = c.deleteTableRows(name)
+	if err != nil {
+		return err
+	}
+
+	delete(c.cache, name)
+
+	return dropStorage(t)
+}
+
+func (c *Catalog) deleteTableRows(name string) error {
+	err := c.deleteRows(c.tables, tablesSchema, name)
+	if err != nil {
+		return err
+	}
+
+	err = c.deleteRows(c.columns, columnsSchema, name)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteRows(c.indexes, indexesSchema, name)
+}
@@ -0,0 +1,232 @@
+package system_catalog
+
+import (
+	"strings"
+	"testing"
+
+	"gobase/catalog"
+	"gobase/disk_manager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchema() *catalog.Schema {
+	return catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+		{Name: "email", Type: catalog.TypeVarchar, Size: 100, Nullable: true, Unique: true},
+		{Name: "balance", Type: catalog.TypeDecimal, Precision: 10, Scale: 2},
+	})
+}
+
+func TestCreateTable_GetTable(t *testing.T) {
+	c, _, cleanup := newTestCatalog(t, 32)
+	defer cleanup()
+
+	created, err := c.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+
+	got, err := c.GetTable("users")
+	require.NoError(t, err)
+	assert.Same(t, created, got)
+
+	_, err = c.CreateTable("users", newTestSchema())
+	require.ErrorIs(t, err, ErrTableAlreadyExists)
+
+	_, err = c.GetTable("orders")
+	require.ErrorIs(t, err, ErrTableNotFound)
+}
+
+func TestCatalog_Reopen(t *testing.T) {
+	c, filePath, cleanup := newTestCatalog(t, 32)
+	defer cleanup()
+
+	users, err := c.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+	require.NoError(t, c.CreateIndex("users", "users_name", false, "name"))
+
+	for i := 0; i < 200; i++ {
+		_, err = users.Insert(nil, i, "user", nil, catalog.NewDecimal(int64(i), 2))
+		require.NoError(t, err)
+	}
+
+	reopened, closeReopened := reopenTestCatalog(t, c, filePath, 32)
+	defer closeReopened()
+
+	users, err = reopened.GetTable("users")
+	require.NoError(t, err)
+	assert.Equal(t, newTestSchema(), users.Schema)
+	assert.Len(t, users.Indexes, 3)
+
+	count := 0
+	scanner := users.Scan()
+	for {
+		_, ok := scanner.Next()
+		if !ok {
+			break
+		}
+		count++
+	}
+	assert.Equal(t, 200, count)
+
+	rows, err := users.LookupByIndex("users_pkey", 42)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{42, "user", nil, catalog.NewDecimal(42, 2)}}, rows)
+
+	rows, err = users.LookupByIndex("users_name", "user")
+	require.NoError(t, err)
+	assert.Len(t, rows, 200)
+
+	_, err = users.Insert(nil, 42, "duplicate", nil, catalog.NewDecimal(0, 2))
+	require.Error(t, err)
+}
+
+func TestDropTable(t *testing.T) {
+	c, filePath, cleanup := newTestCatalog(t, 32)
+	defer cleanup()
+
+	_, err := c.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+	_, err = c.CreateTable("orders", catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeBigInt, PrimaryKey: true},
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, c.DropTable("users"))
+	require.ErrorIs(t, c.DropTable("users"), ErrTableNotFound)
+
+	_, err = c.GetTable("users")
+	require.ErrorIs(t, err, ErrTableNotFound)
+
+	reopened, closeReopened := reopenTestCatalog(t, c, filePath, 32)
+	defer closeReopened()
+
+	_, err = reopened.GetTable("users")
+	require.ErrorIs(t, err, ErrTableNotFound)
+
+	_, err = reopened.GetTable("orders")
+	require.NoError(t, err)
+
+	_, err = reopened.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+}
+
+func TestDropTable_ReusesPages(t *testing.T) {
+	c, _, cleanup := newTestCatalog(t, 32)
+	defer cleanup()
+
+	_, err := c.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+	require.NoError(t, c.CreateIndex("users", "users_name_idx", false, "name"))
+
+	dm := c.bpm.GetDiskManager()
+	numPages := dm.NumPages
+
+	require.NoError(t, c.DropTable("users"))
+	assert.NotEqual(t, disk_manager.INVALID_PAGE_ID, dm.GetFreeListHead())
+
+	_, err = c.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+	require.NoError(t, c.CreateIndex("users", "users_name_idx", false, "name"))
+	assert.Equal(t, numPages, dm.NumPages)
+}
+
+func TestCreateTable_ErrNameTooLong(t *testing.T) {
+	long := strings.Repeat("a", int(MAX_NAME_LENGTH)+1)
+
+	tests := []struct {
+		name      string
+		tableName string
+		column    string
+	}{
+		{name: "table name", tableName: long, column: "id"},
+		{name: "column name", tableName: "users", column: long},
+		{name: "generated index name", tableName: strings.Repeat("t", 30), column: strings.Repeat("c", 30)},
+	}
+
+	newSchema := func(column string) *catalog.Schema {
+		return catalog.NewSchema([]catalog.Column{
+			{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+			{Name: column, Type: catalog.TypeInt, Unique: true},
+		})
+	}
+
+	expected, _, closeExpected := newTestCatalog(t, 32)
+	defer closeExpected()
+	_, err := expected.CreateTable("users", newSchema("email"))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, cleanup := newTestCatalog(t, 32)
+			defer cleanup()
+
+			_, err := c.CreateTable(tt.tableName, newSchema(tt.column))
+			require.ErrorIs(t, err, ErrNameTooLong)
+
+			_, err = c.GetTable(tt.tableName)
+			require.ErrorIs(t, err, ErrTableNotFound)
+
+			_, err = c.CreateTable("users", newSchema("email"))
+			require.NoError(t, err)
+			assert.Equal(t, expected.bpm.GetDiskManager().NumPages, c.bpm.GetDiskManager().NumPages)
+		})
+	}
+}
+
+func TestCreateIndex_ErrNameTooLong(t *testing.T) {
+	c, _, cleanup := newTestCatalog(t, 32)
+	defer cleanup()
+
+	_, err := c.CreateTable("users", newTestSchema())
+	require.NoError(t, err)
+
+	err = c.CreateIndex("users", strings.Repeat("a", int(MAX_NAME_LENGTH)+1), false, "name")
+	require.ErrorIs(t, err, ErrNameTooLong)
+}
+
+func TestCatalog_ReopenIndexColumnsWithSeparator(t *testing.T) {
+	c, filePath, cleanup := newTestCatalog(t, 32)
+	defer cleanup()
+
+	_, err := c.CreateTable("pairs", catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "a,b", Type: catalog.TypeInt},
+		{Name: "c", Type: catalog.TypeInt},
+	}))
+	require.NoError(t, err)
+	require.NoError(t, c.CreateIndex("pairs", "pairs_ab_c", false, "a,b", "c"))
+
+	reopened, closeReopened := reopenTestCatalog(t, c, filePath, 32)
+	defer closeReopened()
+
+	pairs, err := reopened.GetTable("pairs")
+	require.NoError(t, err)
+
+	for _, index := range pairs.Indexes {
+		if index.Name == "pairs_ab_c" {
+			assert.Equal(t, []string{"a,b", "c"}, index.Columns)
+			return
+		}
+	}
+	t.Fatal("index pairs_ab_c not loaded")
+}
+
+func TestDecodeIndexColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		columns []string
+	}{
+		{name: "length-prefixed", data: encodeIndexColumns([]string{"a,b", "c"}), columns: []string{"a,b", "c"}},
+		{name: "legacy single column", data: []byte("a"), columns: []string{"a"}},
+		{name: "legacy several columns", data: []byte("name,email"), columns: []string{"name", "email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.columns, decodeIndexColumns(tt.data))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package system_catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gobase/buffer_pool_manager"
+	"gobase/disk_manager"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatalog(t *testing.T, poolSize int) (*Catalog, string, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "system_catalog_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	c, err := NewCatalog(buffer_pool_manager.NewBufferPoolManager(dm, poolSize))
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return c, tmpFile.Name(), cleanup
+}
+
+func reopenTestCatalog(t *testing.T, c *Catalog, filePath string, poolSize int) (*Catalog, func()) {
+	t.Helper()
+
+	require.NoError(t, c.bpm.FlushAllPages())
+
+	dm, err := disk_manager.NewDiskManager(filePath)
+	require.NoError(t, err)
+
+	reopened, err := LoadCatalog(buffer_pool_manager.NewBufferPoolManager(dm, poolSize))
+	require.NoError(t, err)
+	require.Equal(t, c.GetRootPageID(), reopened.GetRootPageID())
+
+	return reopened, func() { dm.Close() }
+}
+
+func copyTestDatabase(t *testing.T, name string) (string, func()) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	tmpFile, err := os.CreateTemp("", "system_catalog_test")
+	require.NoError(t, err)
+	_, err = tmpFile.Write(data)
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	return tmpFile.Name(), func() {
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+}
@@ -0,0 +1,214 @@
+package system_catalog
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+
+	"gobase/buffer_pool_manager"
+	"gobase/catalog"
+	"gobase/disk_manager"
+	"gobase/table"
+)
+
+// UpgradeBaselineDatabase rewrites a file of the headerless baseline format
+// as a database with a catalog holding tables. The rows are copied into a new
+// file that replaces the old one only once it is complete, so an interrupted
+// upgrade leaves the baseline file untouched.
+func UpgradeBaselineDatabase(filePath string, tables ...BaselineTable) error {
+	for _, t := range tables {
+		for _, col := range t.Schema.Columns {
+			_, ok := baselineValueSizes[col.Type]
+			if !ok {
+				return ErrBaselineUnsupportedType
+			}
+		}
+	}
+
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	numPages, err := baselinePageCount(src)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := filePath + UPGRADE_FILE_SUFFIX
+	removeDatabase(tmpPath)
+
+	err = copyBaselineTables(src, numPages, tmpPath, tables)
+	if err != nil {
+		removeDatabase(tmpPath)
+		return err
+	}
+
+	err = os.Rename(disk_manager.LogFilePath(tmpPath), disk_manager.LogFilePath(filePath))
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, filePath)
+}
+
+func copyBaselineTables(src *os.File, numPages uint32, dstPath string, tables []BaselineTable) error {
+	dm, err := disk_manager.NewDiskManager(dstPath)
+	if err != nil {
+		return err
+	}
+
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, UPGRADE_POOL_SIZE)
+	c, err := NewCatalog(bpm)
+	if err != nil {
+		return errors.Join(err, dm.Close())
+	}
+
+	for _, baseline := range tables {
+		t, err := c.CreateTable(baseline.Name, baseline.Schema)
+		if err != nil {
+			return errors.Join(err, dm.Close())
+		}
+
+		err = copyBaselineHeap(src, numPages, baseline.FirstPageID, t)
+		if err != nil {
+			return errors.Join(err, dm.Close())
+		}
+	}
+
+	err = bpm.FlushAllPages()
+	if err != nil {
+		return errors.Join(err, dm.Close())
+	}
+
+	return dm.Close()
+}
+
+func copyBaselineHeap(src *os.File, numPages uint32, firstPageID uint16, t *table.Table) error {
+	visited := make(map[uint16]bool)
+	data := make([]byte, BASELINE_PAGE_SIZE)
+
+	for pageID := firstPageID; pageID != BASELINE_NULL_PAGE_ID; {
+		if uint32(pageID) >= numPages || visited[pageID] {
+			return ErrBaselineInvalidPage
+		}
+		visited[pageID] = true
+
+		_, err := src.ReadAt(data, int64(pageID)*BASELINE_PAGE_SIZE)
+		if err != nil {
+			return err
+		}
+
+		tuples, err := baselineTuples(data)
+		if err != nil {
+			return err
+		}
+
+		for _, tuple := range tuples {
+			values, err := decodeBaselineTuple(t.Schema, tuple)
+			if err != nil {
+				return err
+			}
+
+			_, err = t.Insert(nil, values...)
+			if err != nil {
+				return err
+			}
+		}
+
+		pageID = binary.LittleEndian.Uint16(data[BASELINE_NEXT_PAGE_ID_OFFSET:])
+	}
+
+	return nil
+}
+
+// Deleted tuples kept their slot with a zero length.
+func baselineTuples(data []byte) ([][]byte, error) {
+	numSlots := int(binary.LittleEndian.Uint16(data[BASELINE_NUM_SLOTS_OFFSET:]))
+	if BASELINE_HEADER_SIZE+numSlots*BASELINE_SLOT_SIZE > len(data) {
+		return nil, ErrBaselineInvalidPage
+	}
+
+	tuples := [][]byte{}
+	for i := 0; i < numSlots; i++ {
+		slotPos := BASELINE_HEADER_SIZE + i*BASELINE_SLOT_SIZE
+		offset := int(binary.LittleEndian.Uint16(data[slotPos:]))
+		length := int(binary.LittleEndian.Uint16(data[slotPos+2:]))
+		if length == 0 {
+			continue
+		}
+		if offset+length > len(data) {
+			return nil, ErrBaselineInvalidPage
+		}
+
+		tuples = append(tuples, data[offset:offset+length])
+	}
+
+	return tuples, nil
+}
+
+// Baseline tuples had no null bitmap: every column was stored back to back,
+// VARCHAR values behind their length.
+func decodeBaselineTuple(schema *catalog.Schema, tuple []byte) ([]any, error) {
+	values := make([]any, len(schema.Columns))
+	offset := 0
+
+	for i, col := range schema.Columns {
+		size := baselineValueSizes[col.Type]
+		if offset+size > len(tuple) {
+			return nil, ErrBaselineInvalidPage
+		}
+		value := tuple[offset : offset+size]
+		offset += size
+
+		switch col.Type {
+		case catalog.TypeInt:
+			values[i] = int(int32(binary.LittleEndian.Uint32(value)))
+		case catalog.TypeSmallInt:
+			values[i] = int(int16(binary.LittleEndian.Uint16(value)))
+		case catalog.TypeBoolean:
+			values[i] = value[0] != 0
+		case catalog.TypeVarchar:
+			length := int(binary.LittleEndian.Uint16(value))
+			if offset+length > len(tuple) {
+				return nil, ErrBaselineInvalidPage
+			}
+			values[i] = string(tuple[offset : offset+length])
+			offset += length
+		}
+	}
+
+	if offset != len(tuple) {
+		return nil, ErrBaselineInvalidPage
+	}
+
+	return values, nil
+}
+
+// A file that already starts with the database magic has been upgraded.
+func baselinePageCount(src *os.File) (uint32, error) {
+	stats, err := src.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if stats.Size() == 0 || stats.Size()%BASELINE_PAGE_SIZE != 0 {
+		return 0, ErrNotBaselineDatabase
+	}
+
+	magic := make([]byte, len(disk_manager.MAGIC))
+	_, err = src.ReadAt(magic, int64(disk_manager.MAGIC_OFFSET))
+	if err != nil {
+		return 0, err
+	}
+	if string(magic) == disk_manager.MAGIC {
+		return 0, ErrNotBaselineDatabase
+	}
+
+	return uint32(stats.Size() / BASELINE_PAGE_SIZE), nil
+}
+
+func removeDatabase(filePath string) {
+	os.Remove(filePath)
+	os.Remove(disk_manager.LogFilePath(filePath))
+}
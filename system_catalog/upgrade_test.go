@@ -0,0 +1,138 @@
+package system_catalog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"gobase/buffer_pool_manager"
+	"gobase/catalog"
+	"gobase/disk_manager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/baseline.db was written by the baseline code: users on pages 0, 2,
+// 3, 4 and 5 with ids -50 to 49 and every 7th row deleted, tags on page 1
+// with every 4th id from 0 to 96.
+func newBaselineTables() []BaselineTable {
+	return []BaselineTable{
+		{Name: "users", FirstPageID: 0, Schema: catalog.NewSchema([]catalog.Column{
+			{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+			{Name: "name", Type: catalog.TypeVarchar, Size: 200},
+			{Name: "age", Type: catalog.TypeSmallInt},
+			{Name: "active", Type: catalog.TypeBoolean},
+		})},
+		{Name: "tags", FirstPageID: 1, Schema: catalog.NewSchema([]catalog.Column{
+			{Name: "id", Type: catalog.TypeInt},
+			{Name: "label", Type: catalog.TypeVarchar, Size: 50},
+		})},
+	}
+}
+
+func TestUpgradeBaselineDatabase(t *testing.T) {
+	filePath, cleanup := copyTestDatabase(t, "baseline.db")
+	defer cleanup()
+
+	_, err := disk_manager.NewDiskManager(filePath)
+	require.ErrorIs(t, err, disk_manager.ErrNotADatabase)
+
+	require.NoError(t, UpgradeBaselineDatabase(filePath, newBaselineTables()...))
+
+	_, err = os.Stat(filePath + UPGRADE_FILE_SUFFIX)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	dm, err := disk_manager.NewDiskManager(filePath)
+	require.NoError(t, err)
+	defer dm.Close()
+
+	c, err := LoadCatalog(buffer_pool_manager.NewBufferPoolManager(dm, 32))
+	require.NoError(t, err)
+
+	users, err := c.GetTable("users")
+	require.NoError(t, err)
+
+	count := 0
+	scanner := users.Scan()
+	for {
+		values, ok := scanner.Next()
+		if !ok {
+			break
+		}
+
+		i := values[0].(int) + 50
+		assert.NotZero(t, i%7)
+		assert.Equal(t, []any{i - 50, fmt.Sprintf("user-%d-%s", i, strings.Repeat("x", 150)), 20 + i%50, i%2 == 0}, values)
+		count++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, 85, count)
+
+	rows, err := users.LookupByIndex("users_pkey", -49)
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+
+	tags, err := c.GetTable("tags")
+	require.NoError(t, err)
+
+	ids := []int{}
+	scanner = tags.Scan()
+	for {
+		values, ok := scanner.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, fmt.Sprintf("tag-%d", values[0]), values[1])
+		ids = append(ids, values[0].(int))
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, ids, 25)
+	assert.Equal(t, 96, ids[24])
+
+	assert.ErrorIs(t, UpgradeBaselineDatabase(filePath, newBaselineTables()...), ErrNotBaselineDatabase)
+}
+
+func TestUpgradeBaselineDatabase_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables func() []BaselineTable
+		err    error
+	}{
+		{name: "unsupported type", err: ErrBaselineUnsupportedType, tables: func() []BaselineTable {
+			tables := newBaselineTables()
+			tables[1].Schema.Columns[1].Type = catalog.TypeBlob
+			return tables
+		}},
+		{name: "page out of range", err: ErrBaselineInvalidPage, tables: func() []BaselineTable {
+			tables := newBaselineTables()
+			tables[1].FirstPageID = 6
+			return tables
+		}},
+		{name: "schema does not match", err: ErrBaselineInvalidPage, tables: func() []BaselineTable {
+			tables := newBaselineTables()
+			tables[1].Schema.Columns[0].Type = catalog.TypeSmallInt
+			return tables
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath, cleanup := copyTestDatabase(t, "baseline.db")
+			defer cleanup()
+
+			original, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+
+			require.ErrorIs(t, UpgradeBaselineDatabase(filePath, tt.tables()...), tt.err)
+
+			data, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, original, data)
+
+			_, err = os.Stat(filePath + UPGRADE_FILE_SUFFIX)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
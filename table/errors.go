@@ -0,0 +1,26 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrIndexAlreadyExists = errors.New("index already exists")
+	ErrIndexNotFound      = errors.New("index not found")
+	ErrIndexWithoutColumn = errors.New("index must have at least one column")
+	ErrInvalidIndexKey    = errors.New("invalid index key")
+
+	ErrPrimaryKeyViolation = errors.New("primary key violation")
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrNotNullViolation    = errors.New("not-null constraint violation")
+)
+
+func (e *ConstraintViolationError) Error() string {
+	return fmt.Sprintf("%v: %s on table %s (%s)", e.Err, e.Constraint, e.Table, strings.Join(e.Columns, ", "))
+}
+
+func (e *ConstraintViolationError) Unwrap() error {
+	return e.Err
+}
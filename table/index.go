@@ -0,0 +1,494 @@
+package table
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"gobase/bplus_tree_index"
+	"gobase/catalog"
+	"gobase/slotted_page"
+	"gobase/table_heap"
+)
+
+func (t *Table) CreateIndex(name string, columns ...string) error {
+	return t.createIndex(name, false, columns)
+}
+
+func (t *Table) CreateUniqueIndex(name string, columns ...string) error {
+	return t.createIndex(name, true, columns)
+}
+
+func (t *Table) createIndex(name string, unique bool, columns []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.Indexes[name]; exists {
+		return ErrIndexAlreadyExists
+	}
+
+	columnIndexes, err := resolveColumns(t.Schema, columns)
+	if err != nil {
+		return err
+	}
+
+	tree, err := bplus_tree_index.NewBPlusTree(t.Heap.GetBufferPoolManager())
+	if err != nil {
+		return err
+	}
+
+	index := &Index{
+		Name:          name,
+		Columns:       columns,
+		Unique:        unique,
+		columnIndexes: columnIndexes,
+		Tree:          tree,
+	}
+
+	iter := t.Heap.Scan()
+	for {
+		rid, data, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		values := catalog.DecodeTuple(t.Schema, data)
+
+		err = index.insert(t.Schema, values, *rid)
+		if errors.Is(err, bplus_tree_index.ErrDuplicateKey) {
+			return errors.Join(t.constraintViolation(index), tree.Drop())
+		}
+		if err != nil {
+			return errors.Join(err, tree.Drop())
+		}
+	}
+
+	if iter.Err() != nil {
+		return errors.Join(iter.Err(), tree.Drop())
+	}
+
+	t.Indexes[name] = index
+	return nil
+}
+
+func (t *Table) LookupByIndex(name string, key ...any) ([][]any, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	index, exists := t.Indexes[name]
+	if !exists {
+		return nil, ErrIndexNotFound
+	}
+
+	prefix, err := t.encodeIndexKey(index, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]any{}
+	iter := index.Tree.Scan(prefix, prefixEnd(prefix))
+	for {
+		key, rid, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		row, live, err := t.resolveIndexEntry(index, key, *rid)
+		if err != nil {
+			return nil, err
+		}
+
+		if live {
+			rows = append(rows, row)
+		}
+	}
+
+	if iter.Err() != nil {
+		return nil, iter.Err()
+	}
+
+	return rows, nil
+}
+
+// Both bounds are inclusive key prefixes on the leading index columns; a nil
+// bound leaves that side of the range open.
+func (t *Table) ScanIndex(name string, low []any, high []any) (*IndexScanner, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	index, exists := t.Indexes[name]
+	if !exists {
+		return nil, ErrIndexNotFound
+	}
+
+	var start, end []byte
+	var err error
+
+	if low != nil {
+		start, err = t.encodeIndexKey(index, low)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if high != nil {
+		end, err = t.encodeIndexKey(index, high)
+		if err != nil {
+			return nil, err
+		}
+		end = prefixEnd(end)
+	}
+
+	return &IndexScanner{
+		table: t,
+		index: index,
+		iter:  index.Tree.Scan(start, end),
+	}, nil
+}
+
+func (t *Table) encodeIndexKey(index *Index, key []any) ([]byte, error) {
+	if len(key) == 0 || len(key) > len(index.columnIndexes) {
+		return nil, ErrInvalidIndexKey
+	}
+
+	for i, value := range key {
+		if value == nil {
+			continue
+		}
+
+		err := t.Schema.Columns[index.columnIndexes[i]].Validate(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return catalog.EncodeKey(t.Schema, index.columnIndexes[:len(key)], key)
+}
+
+func (t *Table) resolveIndexEntry(index *Index, key []byte, rid table_heap.RID) ([]any, bool, error) {
+	row, err := t.GetByRID(rid)
+	if errors.Is(err, slotted_page.ErrTupleHasBeenDeleted) || errors.Is(err, slotted_page.ErrorSlotDidntExists) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	rowKey, err := index.entryKey(t.Schema, row, rid)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return row, bytes.Equal(rowKey, key), nil
+}
+
+func (t *Table) deleteIndexEntries(values []any, rid table_heap.RID) error {
+	for _, index := range t.Indexes {
+		err := index.delete(t.Schema, values, rid)
+		if err != nil && !errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) removeIndexEntries(indexes []*Index, values []any, rid table_heap.RID) error {
+	for _, index := range indexes {
+		err := index.delete(t.Schema, values, rid)
+		if err != nil && !errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) deleteStaleIndexEntries(values []any, rid table_heap.RID) error {
+	for _, index := range t.Indexes {
+		key, err := index.entryKey(t.Schema, values, rid)
+		if err != nil {
+			return err
+		}
+
+		indexedRID, err := index.Tree.Get(key)
+		if errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if *indexedRID != rid {
+			continue
+		}
+
+		state, err := t.indexEntryState(index, key, rid)
+		if err != nil {
+			return err
+		}
+
+		if state == entryDead || state == entryMoved {
+			err = index.Tree.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) indexEntryState(index *Index, key []byte, rid table_heap.RID) (entryState, error) {
+	for {
+		row, live, err := t.resolveIndexEntry(index, key, rid)
+		if err != nil {
+			return entryDead, err
+		}
+
+		if live {
+			return entryLive, nil
+		}
+		if row != nil {
+			return entryMoved, nil
+		}
+
+		data, err := t.Heap.GetMarked(rid)
+		if errors.Is(err, slotted_page.ErrTupleNotMarked) {
+			continue
+		}
+		if errors.Is(err, slotted_page.ErrTupleHasBeenDeleted) || errors.Is(err, slotted_page.ErrorSlotDidntExists) {
+			return entryDead, nil
+		}
+		if err != nil {
+			return entryDead, err
+		}
+
+		// A pending delete can still be rolled back, and so can a pending
+		// update of a row that was deleted afterwards.
+		markedKey, err := index.entryKey(t.Schema, catalog.DecodeTuple(t.Schema, data), rid)
+		if err != nil {
+			return entryDead, err
+		}
+
+		if bytes.Equal(markedKey, key) {
+			return entryDeletePending, nil
+		}
+
+		return entryMoved, nil
+	}
+}
+
+func (t *Table) checkUniqueIndexes(values []any) error {
+	for _, index := range t.Indexes {
+		err := t.checkUniqueIndex(index, values, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) checkUniqueIndex(index *Index, values []any, self *table_heap.RID) error {
+	if !index.Unique {
+		return nil
+	}
+
+	if index.hasNullKey(values) {
+		return nil
+	}
+
+	key, err := index.entryKey(t.Schema, values, table_heap.RID{})
+	if err != nil {
+		return err
+	}
+
+	rid, err := index.Tree.Get(key)
+	if errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if self != nil && *rid == *self {
+		return nil
+	}
+
+	state, err := t.indexEntryState(index, key, *rid)
+	if err != nil {
+		return err
+	}
+
+	if state != entryDead {
+		return t.constraintViolation(index)
+	}
+
+	return nil
+}
+
+// insertIndexEntry reports whether it added an entry: an entry that already
+// points at rid is left alone, and a dead one is taken over.
+func (t *Table) insertIndexEntry(index *Index, values []any, rid table_heap.RID) (bool, error) {
+	key, err := index.entryKey(t.Schema, values, rid)
+	if err != nil {
+		return false, err
+	}
+
+	// A concurrent delete can remove the entry between the failed insert and
+	// the lookup or update that follows it; the insert is then retried.
+	for {
+		err = index.Tree.Insert(key, rid)
+		if !errors.Is(err, bplus_tree_index.ErrDuplicateKey) {
+			return err == nil, err
+		}
+
+		indexedRID, err := index.Tree.Get(key)
+		if errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if *indexedRID == rid {
+			return false, nil
+		}
+
+		state, err := t.indexEntryState(index, key, *indexedRID)
+		if err != nil {
+			return false, err
+		}
+
+		if state != entryDead {
+			return false, t.constraintViolation(index)
+		}
+
+		err = index.Tree.Update(key, rid)
+		if errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+			continue
+		}
+
+		return err == nil, err
+	}
+}
+
+func (t *Table) changedIndexes(oldValues []any, newValues []any, rid table_heap.RID) ([]*Index, error) {
+	changed := make([]*Index, 0, len(t.Indexes))
+	for _, index := range t.Indexes {
+		oldKey, err := index.entryKey(t.Schema, oldValues, rid)
+		if err != nil {
+			return nil, err
+		}
+
+		newKey, err := index.entryKey(t.Schema, newValues, rid)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(oldKey, newKey) {
+			changed = append(changed, index)
+		}
+	}
+
+	return changed, nil
+}
+
+func (t *Table) constraintViolation(index *Index) error {
+	err := ErrUniqueViolation
+	if index.Name == primaryKeyIndexName(t.Name) {
+		err = ErrPrimaryKeyViolation
+	}
+
+	return &ConstraintViolationError{
+		Table:      t.Name,
+		Constraint: index.Name,
+		Columns:    index.Columns,
+		Err:        err,
+	}
+}
+
+func (i *Index) insert(schema *catalog.Schema, values []any, rid table_heap.RID) error {
+	key, err := i.entryKey(schema, values, rid)
+	if err != nil {
+		return err
+	}
+
+	err = i.Tree.Insert(key, rid)
+	if errors.Is(err, bplus_tree_index.ErrDuplicateKey) && !i.Unique {
+		return nil
+	}
+
+	return err
+}
+
+func (i *Index) delete(schema *catalog.Schema, values []any, rid table_heap.RID) error {
+	key, err := i.entryKey(schema, values, rid)
+	if err != nil {
+		return err
+	}
+
+	return i.Tree.Delete(key)
+}
+
+func (i *Index) entryKey(schema *catalog.Schema, values []any, rid table_heap.RID) ([]byte, error) {
+	keyValues := make([]any, len(i.columnIndexes))
+	for j, columnIndex := range i.columnIndexes {
+		keyValues[j] = values[columnIndex]
+	}
+
+	key, err := catalog.EncodeKey(schema, i.columnIndexes, keyValues)
+	if err != nil || i.Unique && !i.hasNullKey(values) {
+		return key, err
+	}
+
+	key = binary.BigEndian.AppendUint32(key, rid.GetPageID())
+	key = binary.BigEndian.AppendUint16(key, rid.GetSlotID())
+
+	return key, nil
+}
+
+func (i *Index) hasNullKey(values []any) bool {
+	for _, columnIndex := range i.columnIndexes {
+		if values[columnIndex] == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resolveColumns(schema *catalog.Schema, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		return nil, ErrIndexWithoutColumn
+	}
+
+	return schema.GetColumnIndexes(columns)
+}
+
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xFF {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
+
+func primaryKeyIndexName(tableName string) string {
+	return tableName + "_pkey"
+}
+
+func notNullConstraintName(tableName string, column string) string {
+	return tableName + "_" + column + "_not_null"
+}
+
+func uniqueIndexName(tableName string, column string) string {
+	return tableName + "_" + column + "_key"
+}
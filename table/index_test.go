@@ -0,0 +1,163 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	"gobase/catalog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIndex_MaintainedOnInsertUpdateDelete(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	alice, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+	_, err = tbl.Insert(nil, 2, "Bob")
+	require.NoError(t, err)
+
+	require.NoError(t, tbl.CreateIndex("users_name", "name"))
+
+	carol, err := tbl.Insert(nil, 3, "Alice")
+	require.NoError(t, err)
+	require.NoError(t, tbl.Update(nil, *alice, 1, "Alicia"))
+	require.NoError(t, tbl.Delete(nil, *carol))
+	_, err = tbl.Insert(nil, 4, "Bob")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		expected [][]any
+	}{
+		{name: "Alice", expected: [][]any{}},
+		{name: "Alicia", expected: [][]any{{1, "Alicia"}}},
+		{name: "Bob", expected: [][]any{{2, "Bob"}, {4, "Bob"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tbl.LookupByIndex("users_name", tt.name)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, rows)
+		})
+	}
+}
+
+func TestCreateIndex_Errors(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	_, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+	_, err = tbl.Insert(nil, 2, "Alice")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		index   string
+		unique  bool
+		columns []string
+		err     error
+	}{
+		{name: "existing name", index: "users_pkey", columns: []string{"name"}, err: ErrIndexAlreadyExists},
+		{name: "no column", index: "users_none", err: ErrIndexWithoutColumn},
+		{name: "duplicate values", index: "users_name_key", unique: true, columns: []string{"name"}, err: ErrUniqueViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tbl.createIndex(tt.index, tt.unique, tt.columns)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	_, exists := tbl.Indexes["users_name_key"]
+	assert.False(t, exists)
+
+	_, err = tbl.LookupByIndex("users_missing", 1)
+	require.ErrorIs(t, err, ErrIndexNotFound)
+
+	_, err = tbl.LookupByIndex("users_pkey", 1, "Alice")
+	require.ErrorIs(t, err, ErrInvalidIndexKey)
+
+	_, err = tbl.LookupByIndex("users_pkey", "1")
+	require.ErrorIs(t, err, catalog.ErrTypeMismatch)
+}
+
+func TestCreateIndex_FailedBackfillFreesTree(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	_, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+	_, err = tbl.Insert(nil, 2, "Alice")
+	require.NoError(t, err)
+
+	dm := tbl.Heap.GetBufferPoolManager().GetDiskManager()
+	require.ErrorIs(t, tbl.CreateUniqueIndex("users_name_key", "name"), ErrUniqueViolation)
+	numPages := dm.NumPages
+
+	for i := 0; i < 50; i++ {
+		require.ErrorIs(t, tbl.CreateUniqueIndex("users_name_key", "name"), ErrUniqueViolation)
+	}
+	assert.Equal(t, numPages, dm.NumPages)
+}
+
+func TestScanIndex_ColumnTypes(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	day := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		column catalog.Column
+		values []any
+	}{
+		{name: "bigint", column: catalog.Column{Type: catalog.TypeBigInt}, values: []any{int64(-5000000000), int64(-1), int64(7), int64(5000000000)}},
+		{name: "double", column: catalog.Column{Type: catalog.TypeDouble}, values: []any{-2.5, -0.25, 0.0, 1e10}},
+		{name: "decimal", column: catalog.Column{Type: catalog.TypeDecimal, Precision: 8, Scale: 2}, values: []any{catalog.NewDecimal(-1050, 2), catalog.NewDecimal(0, 2), catalog.NewDecimal(199, 2), catalog.NewDecimal(100000, 2)}},
+		{name: "timestamp", column: catalog.Column{Type: catalog.TypeTimestamp}, values: []any{day.Add(-time.Hour), day, day.Add(time.Microsecond), day.Add(time.Hour)}},
+		{name: "date", column: catalog.Column{Type: catalog.TypeDate}, values: []any{day.AddDate(-60, 0, 0), day.AddDate(0, 0, -1), day, day.AddDate(1, 0, 0)}},
+		{name: "uuid", column: catalog.Column{Type: catalog.TypeUUID}, values: []any{catalog.UUID{0x00, 0x01}, catalog.UUID{0x10}, catalog.UUID{0x7F}, catalog.UUID{0xFF}}},
+		{name: "blob", column: catalog.Column{Type: catalog.TypeBlob, Size: 16}, values: []any{[]byte{}, []byte{0x00}, []byte{0x00, 0x01}, []byte{0xFF}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := tt.column
+			column.Name = "value"
+			items := addTestTable(t, tbl, "items_"+tt.name, []catalog.Column{
+				{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+				column,
+			})
+			require.NoError(t, items.CreateIndex("items_value", "value"))
+
+			for i := len(tt.values) - 1; i >= 0; i-- {
+				_, err := items.Insert(nil, i, tt.values[i])
+				require.NoError(t, err)
+			}
+
+			rows, err := items.LookupByIndex("items_value", tt.values[2])
+			require.NoError(t, err)
+			assert.Equal(t, [][]any{{2, tt.values[2]}}, rows)
+
+			scanner, err := items.ScanIndex("items_value", []any{tt.values[1]}, []any{tt.values[2]})
+			require.NoError(t, err)
+
+			ids := []any{}
+			for {
+				_, row, ok, err := scanner.Next()
+				require.NoError(t, err)
+				if !ok {
+					break
+				}
+				ids = append(ids, row[0])
+			}
+			assert.Equal(t, []any{1, 2}, ids)
+		})
+	}
+}
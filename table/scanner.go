@@ -1,6 +1,9 @@
 package table
 
-import "gobase/catalog"
+import (
+	"gobase/catalog"
+	"gobase/table_heap"
+)
 
 func (ts *TableScanner) Next() ([]any, bool) {
 	_, encodedData, ok := ts.iter.Next()
@@ -11,3 +14,28 @@ func (ts *TableScanner) Next() ([]any, bool) {
 	decodedData := catalog.DecodeTuple(ts.schema, encodedData)
 	return decodedData, true
 }
+
+// Err returns the error that ended the scan, if any.
+func (ts *TableScanner) Err() error {
+	return ts.iter.Err()
+}
+
+func (is *IndexScanner) Next() (*table_heap.RID, []any, bool, error) {
+	for {
+		key, rid, ok := is.iter.Next()
+		if !ok {
+			return nil, nil, false, is.iter.Err()
+		}
+
+		is.table.mu.RLock()
+		row, live, err := is.table.resolveIndexEntry(is.index, key, *rid)
+		is.table.mu.RUnlock()
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		if live {
+			return rid, row, true, nil
+		}
+	}
+}
@@ -1,14 +1,27 @@
 package table
 
 import (
+	"sync"
+
+	"gobase/bplus_tree_index"
 	"gobase/catalog"
 	"gobase/table_heap"
 )
 
 type Table struct {
-	Name   string
-	Schema *catalog.Schema
-	Heap   *table_heap.TableHeap
+	mu      sync.RWMutex
+	Name    string
+	Schema  *catalog.Schema
+	Heap    *table_heap.TableHeap
+	Indexes map[string]*Index
+}
+
+type Index struct {
+	Name          string
+	Columns       []string
+	Unique        bool
+	columnIndexes []int
+	Tree          *bplus_tree_index.BPlusTree
 }
 
 type TableScanner struct {
@@ -16,10 +29,71 @@ type TableScanner struct {
 	iter   *table_heap.TableIterator
 }
 
-func NewTable(name string, schema *catalog.Schema, heap *table_heap.TableHeap) *Table {
-	return &Table{
-		Name:   name,
-		Schema: schema,
-		Heap:   heap,
+type IndexScanner struct {
+	table *Table
+	index *Index
+	iter  *bplus_tree_index.IndexIterator
+}
+
+type ConstraintViolationError struct {
+	Table      string
+	Constraint string
+	Columns    []string
+	Err        error
+}
+
+func NewTable(name string, schema *catalog.Schema, heap *table_heap.TableHeap) (*Table, error) {
+	t := &Table{
+		Name:    name,
+		Schema:  schema,
+		Heap:    heap,
+		Indexes: make(map[string]*Index),
+	}
+
+	primaryKey := schema.GetPrimaryKey()
+	if len(primaryKey) > 0 {
+		err := t.CreateUniqueIndex(primaryKeyIndexName(name), primaryKey...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, column := range schema.GetUniqueColumns() {
+		err := t.CreateUniqueIndex(uniqueIndexName(name, column), column)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func OpenTable(name string, schema *catalog.Schema, heap *table_heap.TableHeap, indexes ...*Index) *Table {
+	t := &Table{
+		Name:    name,
+		Schema:  schema,
+		Heap:    heap,
+		Indexes: make(map[string]*Index),
+	}
+
+	for _, index := range indexes {
+		t.Indexes[index.Name] = index
 	}
+
+	return t
+}
+
+func OpenIndex(schema *catalog.Schema, name string, unique bool, tree *bplus_tree_index.BPlusTree, columns ...string) (*Index, error) {
+	columnIndexes, err := resolveColumns(schema, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Index{
+		Name:          name,
+		Columns:       columns,
+		Unique:        unique,
+		columnIndexes: columnIndexes,
+		Tree:          tree,
+	}, nil
 }
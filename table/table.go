@@ -1,18 +1,60 @@
 package table
 
 import (
+	"errors"
+
+	"gobase/bplus_tree_index"
 	"gobase/catalog"
 	"gobase/table_heap"
+	"gobase/transaction_manager"
 )
 
-func (t *Table) Insert(values ...any) (*table_heap.RID, error) {
-	encodedData := catalog.EncodeTuple(t.Schema, values)
+func (t *Table) Insert(txn *transaction_manager.Transaction, values ...any) (*table_heap.RID, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	err := t.checkNotNull(values)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedData, err := catalog.EncodeTuple(t.Schema, values)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.checkUniqueIndexes(values)
+	if err != nil {
+		return nil, err
+	}
 
-	rid, err := t.Heap.Insert(encodedData)
+	rid, err := t.Heap.Insert(txn, encodedData)
 	if err != nil {
 		return nil, err
 	}
 
+	inserted := make([]*Index, 0, len(t.Indexes))
+	for _, index := range t.Indexes {
+		added, err := t.insertIndexEntry(index, values, *rid)
+		if err != nil {
+			return nil, errors.Join(err, t.removeIndexEntries(inserted, values, *rid), t.Heap.Delete(txn, *rid))
+		}
+
+		if added {
+			inserted = append(inserted, index)
+		}
+	}
+
+	if txn != nil {
+		undoRID := *rid
+		txn.AddUndoAction(func() error {
+			t.mu.RLock()
+			defer t.mu.RUnlock()
+
+			return t.deleteIndexEntries(values, undoRID)
+		})
+	}
+
 	return rid, nil
 }
 
@@ -27,8 +69,109 @@ func (t *Table) GetByRID(rid table_heap.RID) ([]any, error) {
 	return dataDecoded, nil
 }
 
-func (t *Table) Delete(rid table_heap.RID) error {
-	return t.Heap.Delete(rid)
+func (t *Table) Delete(txn *transaction_manager.Transaction, rid table_heap.RID) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	values, err := t.GetByRID(rid)
+	if err != nil {
+		return err
+	}
+
+	err = t.Heap.Delete(txn, rid)
+	if err != nil {
+		return err
+	}
+
+	if txn == nil {
+		return t.deleteIndexEntries(values, rid)
+	}
+
+	txn.AddCommitAction(func() error {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+
+		return t.deleteStaleIndexEntries(values, rid)
+	})
+
+	return nil
+}
+
+func (t *Table) Update(txn *transaction_manager.Transaction, rid table_heap.RID, values ...any) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	err := t.checkNotNull(values)
+	if err != nil {
+		return err
+	}
+
+	encodedData, err := catalog.EncodeTuple(t.Schema, values)
+	if err != nil {
+		return err
+	}
+
+	oldData, err := t.Heap.Get(rid)
+	if err != nil {
+		return err
+	}
+
+	oldValues := catalog.DecodeTuple(t.Schema, oldData)
+
+	changed, err := t.changedIndexes(oldValues, values, rid)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range changed {
+		err = t.checkUniqueIndex(index, values, &rid)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = t.Heap.Update(txn, rid, encodedData)
+	if err != nil {
+		return err
+	}
+
+	inserted := make([]*Index, 0, len(changed))
+	for _, index := range changed {
+		added, err := t.insertIndexEntry(index, values, rid)
+		if err != nil {
+			return errors.Join(err, t.removeIndexEntries(inserted, values, rid), t.Heap.Update(txn, rid, oldData))
+		}
+
+		if added {
+			inserted = append(inserted, index)
+		}
+	}
+
+	if txn == nil {
+		for _, index := range changed {
+			err = index.delete(t.Schema, oldValues, rid)
+			if err != nil && !errors.Is(err, bplus_tree_index.ErrKeyNotFound) {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	txn.AddUndoAction(func() error {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+
+		return t.removeIndexEntries(inserted, values, rid)
+	})
+	txn.AddCommitAction(func() error {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+
+		return t.deleteStaleIndexEntries(oldValues, rid)
+	})
+
+	return nil
 }
 
 func (t *Table) Scan() *TableScanner {
@@ -37,3 +180,19 @@ func (t *Table) Scan() *TableScanner {
 		iter:   t.Heap.Scan(),
 	}
 }
+
+// A value count mismatch is left to EncodeTuple to report.
+func (t *Table) checkNotNull(values []any) error {
+	for i, col := range t.Schema.Columns {
+		if i < len(values) && values[i] == nil && !col.AcceptsNull() {
+			return &ConstraintViolationError{
+				Table:      t.Name,
+				Constraint: notNullConstraintName(t.Name, col.Name),
+				Columns:    []string{col.Name},
+				Err:        ErrNotNullViolation,
+			}
+		}
+	}
+
+	return nil
+}
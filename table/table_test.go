@@ -0,0 +1,364 @@
+package table
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"gobase/bplus_tree_index"
+	"gobase/catalog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsert_PendingDeleteKeepsKey(t *testing.T) {
+	tbl, tm, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	rid, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tbl.Delete(txn, *rid))
+
+	_, err = tbl.Insert(nil, 1, "Mallory")
+	require.ErrorIs(t, err, ErrPrimaryKeyViolation)
+
+	require.NoError(t, tm.Abort(txn))
+
+	assert.Equal(t, [][]any{{1, "Alice"}}, scanValues(t, tbl))
+
+	rows, err := tbl.LookupByIndex("users_pkey", 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Alice"}}, rows)
+}
+
+func TestInsert_ReusesKeyAfterCommittedDelete(t *testing.T) {
+	tbl, tm, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	rid, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tbl.Delete(txn, *rid))
+	require.NoError(t, tm.Commit(txn))
+
+	_, err = tbl.Insert(nil, 1, "Mallory")
+	require.NoError(t, err)
+
+	rows, err := tbl.LookupByIndex("users_pkey", 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Mallory"}}, rows)
+}
+
+func TestUpdate_PendingKeyChangeKeepsOldKey(t *testing.T) {
+	tbl, tm, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	rid, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tbl.Update(txn, *rid, 2, "Alice"))
+
+	_, err = tbl.Insert(nil, 1, "Mallory")
+	require.ErrorIs(t, err, ErrPrimaryKeyViolation)
+
+	require.NoError(t, tbl.Update(txn, *rid, 1, "Alice"))
+	require.NoError(t, tm.Abort(txn))
+
+	rows, err := tbl.LookupByIndex("users_pkey", 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Alice"}}, rows)
+
+	rows, err = tbl.LookupByIndex("users_pkey", 2)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestAbort_Insert(t *testing.T) {
+	tbl, tm, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	_, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	_, err = tbl.Insert(txn, 2, "Bob")
+	require.NoError(t, err)
+	require.NoError(t, tm.Abort(txn))
+
+	assert.Equal(t, [][]any{{1, "Alice"}}, scanValues(t, tbl))
+
+	rows, err := tbl.LookupByIndex("users_pkey", 2)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	_, err = tbl.Insert(nil, 2, "Bob")
+	require.NoError(t, err)
+}
+
+func TestAbort_Delete(t *testing.T) {
+	tbl, tm, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	rid, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tbl.Delete(txn, *rid))
+	assert.Empty(t, scanValues(t, tbl))
+	require.NoError(t, tm.Abort(txn))
+
+	assert.Equal(t, [][]any{{1, "Alice"}}, scanValues(t, tbl))
+
+	rows, err := tbl.LookupByIndex("users_pkey", 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Alice"}}, rows)
+
+	_, err = tbl.Insert(nil, 1, "Alice")
+	require.ErrorIs(t, err, ErrPrimaryKeyViolation)
+}
+
+func TestAbort_Update(t *testing.T) {
+	tbl, tm, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	rid, err := tbl.Insert(nil, 1, "Alice")
+	require.NoError(t, err)
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tbl.Update(txn, *rid, 3, "Alicia"))
+	require.NoError(t, tm.Abort(txn))
+
+	assert.Equal(t, [][]any{{1, "Alice"}}, scanValues(t, tbl))
+
+	rows, err := tbl.LookupByIndex("users_pkey", 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "Alice"}}, rows)
+
+	rows, err = tbl.LookupByIndex("users_pkey", 3)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	_, err = tbl.Insert(nil, 3, "Carol")
+	require.NoError(t, err)
+}
+
+func TestCommit_SurvivesRestart(t *testing.T) {
+	tbl, tm, filePath, cleanup := newTestTable(t)
+	defer cleanup()
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	_, err = tbl.Insert(txn, 1, "Alice")
+	require.NoError(t, err)
+	bob, err := tbl.Insert(txn, 2, "Bob")
+	require.NoError(t, err)
+	carol, err := tbl.Insert(txn, 3, "Carol")
+	require.NoError(t, err)
+	require.NoError(t, tm.Commit(txn))
+
+	txn, err = tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tbl.Update(txn, *bob, 2, "Robert"))
+	require.NoError(t, tbl.Delete(txn, *carol))
+	require.NoError(t, tm.Commit(txn))
+
+	txn, err = tm.Begin()
+	require.NoError(t, err)
+	_, err = tbl.Insert(txn, 4, "Dave")
+	require.NoError(t, err)
+
+	reopened, closeReopened := reopenTestTable(t, tbl, filePath)
+	defer closeReopened()
+
+	assert.Equal(t, [][]any{{1, "Alice"}, {2, "Robert"}}, scanValues(t, reopened))
+
+	rows, err := reopened.LookupByIndex("users_pkey", 2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{2, "Robert"}}, rows)
+
+	for _, id := range []int{3, 4} {
+		rows, err = reopened.LookupByIndex("users_pkey", id)
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	}
+}
+
+func TestUpdate_IndexFailureRestoresRow(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	notes := addTestTable(t, tbl, "notes", []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "body", Type: catalog.TypeVarchar, Size: 1000},
+	})
+	require.NoError(t, notes.CreateIndex("notes_body", "body"))
+
+	rid, err := notes.Insert(nil, 1, "short")
+	require.NoError(t, err)
+
+	err = notes.Update(nil, *rid, 1, strings.Repeat("x", 600))
+	require.ErrorIs(t, err, bplus_tree_index.ErrKeyTooLarge)
+
+	assert.Equal(t, [][]any{{1, "short"}}, scanValues(t, notes))
+
+	rows, err := notes.LookupByIndex("notes_body", "short")
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, "short"}}, rows)
+}
+
+func TestInsert_Errors(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	accounts := addTestTable(t, tbl, "accounts", []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "email", Type: catalog.TypeVarchar, Size: 20, Unique: true, Nullable: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 10},
+	})
+
+	_, err := accounts.Insert(nil, 1, "alice@example.com", "Alice")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		values     []any
+		err        error
+		constraint string
+	}{
+		{name: "duplicate primary key", values: []any{1, "bob@example.com", "Bob"}, err: ErrPrimaryKeyViolation, constraint: "accounts_pkey"},
+		{name: "duplicate unique column", values: []any{2, "alice@example.com", "Bob"}, err: ErrUniqueViolation, constraint: "accounts_email_key"},
+		{name: "null primary key", values: []any{nil, "bob@example.com", "Bob"}, err: ErrNotNullViolation, constraint: "accounts_id_not_null"},
+		{name: "null not nullable column", values: []any{2, "bob@example.com", nil}, err: ErrNotNullViolation, constraint: "accounts_name_not_null"},
+		{name: "type mismatch", values: []any{"2", "bob@example.com", "Bob"}, err: catalog.ErrTypeMismatch},
+		{name: "value too long", values: []any{2, "bob@example.com", "Bartholomew"}, err: catalog.ErrValueTooLong},
+		{name: "value count mismatch", values: []any{2, "bob@example.com"}, err: catalog.ErrValueCountMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := accounts.Insert(nil, tt.values...)
+			require.ErrorIs(t, err, tt.err)
+
+			if tt.constraint != "" {
+				var violation *ConstraintViolationError
+				require.ErrorAs(t, err, &violation)
+				assert.Equal(t, "accounts", violation.Table)
+				assert.Equal(t, tt.constraint, violation.Constraint)
+			}
+		})
+	}
+
+	assert.Equal(t, [][]any{{1, "alice@example.com", "Alice"}}, scanValues(t, accounts))
+}
+
+func TestInsert_UniqueColumnAllowsSeveralNulls(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	accounts := addTestTable(t, tbl, "accounts", []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "email", Type: catalog.TypeVarchar, Size: 20, Unique: true, Nullable: true},
+	})
+
+	_, err := accounts.Insert(nil, 1, nil)
+	require.NoError(t, err)
+	second, err := accounts.Insert(nil, 2, nil)
+	require.NoError(t, err)
+
+	rows, err := accounts.LookupByIndex("accounts_email_key", nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, nil}, {2, nil}}, rows)
+
+	require.NoError(t, accounts.Update(nil, *second, 2, "bob@example.com"))
+
+	rows, err = accounts.LookupByIndex("accounts_email_key", nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1, nil}}, rows)
+}
+
+func TestUpdate_KeepsRID(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	notes := addTestTable(t, tbl, "notes", []catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "body", Type: catalog.TypeVarchar, Size: 1000},
+	})
+
+	rid, err := notes.Insert(nil, 1, "short")
+	require.NoError(t, err)
+	for id := 2; id <= 20; id++ {
+		_, err = notes.Insert(nil, id, strings.Repeat("n", 200))
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "same size", body: "SHORT"},
+		{name: "smaller", body: "s"},
+		{name: "larger than the free space on the page", body: strings.Repeat("x", 900)},
+		{name: "back to small", body: "tiny"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, notes.Update(nil, *rid, 1, tt.body))
+
+			values, err := notes.GetByRID(*rid)
+			require.NoError(t, err)
+			assert.Equal(t, []any{1, tt.body}, values)
+
+			rows, err := notes.LookupByIndex("notes_pkey", 1)
+			require.NoError(t, err)
+			assert.Equal(t, [][]any{{1, tt.body}}, rows)
+		})
+	}
+
+	assert.Len(t, scanValues(t, notes), 20)
+}
+
+func TestInsert_ConcurrentDeletesOfSameKey(t *testing.T) {
+	tbl, _, _, cleanup := newTestTable(t)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*200)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				rid, err := tbl.Insert(nil, i%4, "user")
+				if err != nil {
+					errs <- err
+					continue
+				}
+
+				err = tbl.Delete(nil, *rid)
+				if err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.ErrorIs(t, err, ErrPrimaryKeyViolation)
+	}
+	assert.Empty(t, scanValues(t, tbl))
+}
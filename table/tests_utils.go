@@ -0,0 +1,102 @@
+package table
+
+import (
+	"os"
+	"testing"
+
+	"gobase/bplus_tree_index"
+	"gobase/buffer_pool_manager"
+	"gobase/catalog"
+	"gobase/disk_manager"
+	"gobase/table_heap"
+	"gobase/transaction_manager"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTable(t *testing.T) (*Table, *transaction_manager.TransactionManager, string, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "table_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	heap, err := table_heap.NewTableHeap(buffer_pool_manager.NewBufferPoolManager(dm, 32))
+	require.NoError(t, err)
+
+	schema := catalog.NewSchema([]catalog.Column{
+		{Name: "id", Type: catalog.TypeInt, PrimaryKey: true},
+		{Name: "name", Type: catalog.TypeVarchar, Size: 50},
+	})
+
+	tbl, err := NewTable("users", schema, heap)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return tbl, transaction_manager.NewTransactionManager(dm.Log), tmpFile.Name(), cleanup
+}
+
+// addTestTable creates another table in the same database as tbl.
+func addTestTable(t *testing.T, tbl *Table, name string, columns []catalog.Column) *Table {
+	t.Helper()
+
+	heap, err := table_heap.NewTableHeap(tbl.Heap.GetBufferPoolManager())
+	require.NoError(t, err)
+
+	added, err := NewTable(name, catalog.NewSchema(columns), heap)
+	require.NoError(t, err)
+
+	return added
+}
+
+// reopenTestTable simulates a crash: dirty pages still in the buffer pool are
+// lost and the table is rebuilt from what recovery brings back.
+func reopenTestTable(t *testing.T, tbl *Table, filePath string) (*Table, func()) {
+	t.Helper()
+
+	tbl.Heap.GetBufferPoolManager().GetDiskManager().Close()
+
+	dm, err := disk_manager.NewDiskManager(filePath)
+	require.NoError(t, err)
+
+	bpm := buffer_pool_manager.NewBufferPoolManager(dm, 32)
+
+	heap, err := table_heap.OpenTableHeap(bpm, tbl.Heap.GetHeaderPageID())
+	require.NoError(t, err)
+
+	indexes := make([]*Index, 0, len(tbl.Indexes))
+	for _, index := range tbl.Indexes {
+		tree, err := bplus_tree_index.OpenBPlusTree(bpm, index.Tree.GetHeaderPageID())
+		require.NoError(t, err)
+
+		reopened, err := OpenIndex(tbl.Schema, index.Name, index.Unique, tree, index.Columns...)
+		require.NoError(t, err)
+		indexes = append(indexes, reopened)
+	}
+
+	return OpenTable(tbl.Name, tbl.Schema, heap, indexes...), func() { dm.Close() }
+}
+
+func scanValues(t *testing.T, tbl *Table) [][]any {
+	t.Helper()
+
+	rows := [][]any{}
+	scanner := tbl.Scan()
+	for {
+		values, ok := scanner.Next()
+		if !ok {
+			break
+		}
+		rows = append(rows, values)
+	}
+
+	return rows
+}
@@ -0,0 +1,12 @@
+package table
+
+// entryState describes what an index entry points at from the point of view
+// of a writer: only a dead entry can be taken over by another row.
+type entryState uint8
+
+const (
+	entryLive entryState = iota
+	entryMoved
+	entryDeletePending
+	entryDead
+)
@@ -0,0 +1,26 @@
+package table_heap
+
+const (
+	FIRST_PAGE_ID_OFFSET uint32 = 12
+	LAST_PAGE_ID_OFFSET  uint32 = 16
+	FSM_PAGE_ID_OFFSET   uint32 = 20
+	VERSION_OFFSET       uint32 = 24
+
+	FORMAT_VERSION uint32 = 2
+
+	TUPLE_HEADER_SIZE     = 1
+	FORWARD_POINTER_SIZE  = 6
+	OVERFLOW_POINTER_SIZE = 8
+	OVERFLOW_FRACTION     = 4
+
+	OVERFLOW_NEXT_PAGE_ID_OFFSET uint32 = 12
+	OVERFLOW_DATA_SIZE_OFFSET    uint32 = 16
+	OVERFLOW_HEADER_SIZE         uint32 = 20
+
+	TUPLE_NORMAL  uint8 = 0
+	TUPLE_FORWARD uint8 = 1
+	TUPLE_MOVED   uint8 = 2
+	TUPLE_INVALID uint8 = 0xFF
+
+	TUPLE_OVERFLOW uint8 = 0x80
+)
@@ -0,0 +1,8 @@
+package table_heap
+
+import "errors"
+
+var (
+	ErrOverflowChainCorrupted = errors.New("overflow chain does not match tuple size")
+	ErrUnsupportedVersion     = errors.New("unsupported table heap format version")
+)
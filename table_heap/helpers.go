@@ -1,6 +1,17 @@
 package table_heap
 
-func (r *RID) GetPageID() uint16 {
+import (
+	"encoding/binary"
+	"errors"
+
+	"gobase/buffer_pool_manager"
+	"gobase/log_manager"
+	"gobase/shared"
+	"gobase/slotted_page"
+	"gobase/transaction_manager"
+)
+
+func (r *RID) GetPageID() uint32 {
 	return r.pageID
 }
 
@@ -8,3 +19,297 @@ func (r *RID) GetSlotID() uint16 {
 	return r.slotID
 }
 
+func (th *TableHeap) GetHeaderPageID() uint32 {
+	return th.headerPageID
+}
+
+func (th *TableHeap) GetBufferPoolManager() *buffer_pool_manager.BufferPoolManager {
+	return th.bpm
+}
+
+func (th *TableHeap) appendLog(txn *transaction_manager.Transaction, rec *log_manager.LogRecord) (uint64, error) {
+	logManager := th.bpm.GetLogManager()
+	if logManager == nil {
+		return log_manager.INVALID_LSN, nil
+	}
+
+	if txn != nil {
+		rec.TxnID = txn.GetID()
+		rec.PrevLSN = txn.GetPrevLSN()
+	}
+
+	lsn, err := logManager.AppendRecord(rec)
+	if err != nil {
+		return log_manager.INVALID_LSN, err
+	}
+
+	if txn != nil {
+		txn.SetPrevLSN(lsn)
+	}
+
+	return lsn, nil
+}
+
+func (th *TableHeap) withPage(pageID uint32, write bool, apply func(sp *slotted_page.SlottedPage) error) error {
+	frame, err := th.bpm.FetchPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	if write {
+		frame.WLatch()
+	} else {
+		frame.RLatch()
+	}
+
+	err = apply(slotted_page.FromData(frame.Data))
+
+	if write {
+		frame.WUnlatch()
+	} else {
+		frame.RUnlatch()
+	}
+
+	th.bpm.UnpinPage(pageID, write)
+	return err
+}
+
+func (th *TableHeap) writeHeader() error {
+	return updatePage(th.bpm, th.headerPageID, func(data []byte) error {
+		binary.LittleEndian.PutUint32(data[FIRST_PAGE_ID_OFFSET:], th.firstPageID)
+		binary.LittleEndian.PutUint32(data[LAST_PAGE_ID_OFFSET:], th.lastPageID)
+		binary.LittleEndian.PutUint32(data[FSM_PAGE_ID_OFFSET:], th.fsm.GetRootPageID())
+		binary.LittleEndian.PutUint32(data[VERSION_OFFSET:], FORMAT_VERSION)
+		return nil
+	})
+}
+
+// A new page is linked into the chain before the header and the free space map
+// learn about it, so after a crash lastPageID may lag behind the real end. Only
+// the pages after it are walked, unless it was unlinked from the chain itself.
+func (th *TableHeap) repairLastPage() error {
+	pageID := th.lastPageID
+
+	linked, err := th.isLinked(pageID)
+	if err != nil {
+		return err
+	}
+
+	if !linked {
+		err = th.fsm.Remove(pageID)
+		if err != nil {
+			return err
+		}
+		pageID = th.firstPageID
+	}
+
+	for {
+		var nextPageID uint32
+		err = th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+			nextPageID = sp.GetNextPageID()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if nextPageID == slotted_page.NULL_PAGE_ID {
+			break
+		}
+		pageID = nextPageID
+	}
+
+	if pageID == th.lastPageID {
+		return nil
+	}
+
+	th.lastPageID = pageID
+
+	err = th.updateFreeSpace(pageID)
+	if err != nil {
+		return err
+	}
+
+	return th.writeHeader()
+}
+
+// isLinked reports whether the page before pageID still points at it.
+func (th *TableHeap) isLinked(pageID uint32) (bool, error) {
+	if pageID == th.firstPageID {
+		return true, nil
+	}
+
+	var prevPageID uint32
+	err := th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+		prevPageID = sp.GetPrevPageID()
+		return nil
+	})
+	if err != nil || prevPageID == slotted_page.NULL_PAGE_ID {
+		return false, err
+	}
+
+	var nextPageID uint32
+	err = th.withPage(prevPageID, false, func(sp *slotted_page.SlottedPage) error {
+		nextPageID = sp.GetNextPageID()
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return nextPageID == pageID, nil
+}
+
+func updatePage(bpm *buffer_pool_manager.BufferPoolManager, pageID uint32, apply func(data []byte) error) error {
+	frame, err := bpm.FetchPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	frame.WLatch()
+	err = apply(frame.Data)
+
+	logManager := bpm.GetLogManager()
+	if err == nil && logManager != nil {
+		var lsn uint64
+		lsn, err = logManager.AppendRecord(&log_manager.LogRecord{
+			Type:   log_manager.RecordPageImage,
+			PageID: pageID,
+			Data:   frame.Data,
+		})
+		if err == nil {
+			shared.SetPageLSN(frame.Data, lsn)
+		}
+	}
+
+	frame.WUnlatch()
+	bpm.UnpinPage(pageID, true)
+
+	return err
+}
+
+func (th *TableHeap) updateFreeSpace(pageID uint32) error {
+	var freeSpace uint16
+
+	err := th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+		freeSpace = sp.GetFreeSpace()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return th.fsm.Update(pageID, freeSpace)
+}
+
+func (th *TableHeap) modifyPage(pageID uint32, apply func(sp *slotted_page.SlottedPage) error) error {
+	var freeSpace uint16
+
+	err := th.withPage(pageID, true, func(sp *slotted_page.SlottedPage) error {
+		err := apply(sp)
+		freeSpace = sp.GetFreeSpace()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return th.fsm.Update(pageID, freeSpace)
+}
+
+// deletePage frees a page that is no longer reachable from the heap. A page
+// that a reader still has pinned is kept aside and freed by a later call to
+// reclaimPinnedPages.
+func (th *TableHeap) deletePage(pageID uint32) error {
+	th.pinnedMu.Lock()
+	defer th.pinnedMu.Unlock()
+
+	err := th.bpm.DeletePage(pageID)
+	if errors.Is(err, buffer_pool_manager.ErrPagePinned) {
+		th.pinnedPages = append(th.pinnedPages, pageID)
+		return nil
+	}
+
+	return err
+}
+
+func (th *TableHeap) reclaimPinnedPages() error {
+	th.pinnedMu.Lock()
+	defer th.pinnedMu.Unlock()
+
+	stillPinned := []uint32{}
+	for i, pageID := range th.pinnedPages {
+		err := th.bpm.DeletePage(pageID)
+		if errors.Is(err, buffer_pool_manager.ErrPagePinned) {
+			stillPinned = append(stillPinned, pageID)
+			continue
+		}
+		if err != nil {
+			th.pinnedPages = append(stillPinned, th.pinnedPages[i:]...)
+			return err
+		}
+	}
+
+	th.pinnedPages = stillPinned
+	return nil
+}
+
+func isRunning(txn *transaction_manager.Transaction) bool {
+	return txn != nil && txn.GetState() == transaction_manager.TransactionRunning
+}
+
+func isGone(err error) bool {
+	return errors.Is(err, slotted_page.ErrTupleHasBeenDeleted) || errors.Is(err, slotted_page.ErrorSlotDidntExists)
+}
+
+func wrapTuple(kind uint8, payload []byte) shared.Tuple {
+	tuple := make(shared.Tuple, TUPLE_HEADER_SIZE+len(payload))
+	tuple[0] = kind
+	copy(tuple[TUPLE_HEADER_SIZE:], payload)
+	return tuple
+}
+
+func unwrapTuple(tuple shared.Tuple) (uint8, shared.Tuple) {
+	if len(tuple) < TUPLE_HEADER_SIZE {
+		return TUPLE_INVALID, nil
+	}
+
+	return tuple[0] &^ TUPLE_OVERFLOW, tuple[TUPLE_HEADER_SIZE:]
+}
+
+func isOverflow(tuple shared.Tuple) bool {
+	return len(tuple) >= TUPLE_HEADER_SIZE && tuple[0]&TUPLE_OVERFLOW != 0
+}
+
+func encodeForward(rid RID) []byte {
+	data := make([]byte, FORWARD_POINTER_SIZE)
+	binary.LittleEndian.PutUint32(data, rid.pageID)
+	binary.LittleEndian.PutUint16(data[4:], rid.slotID)
+	return data
+}
+
+func decodeForward(data []byte) RID {
+	if len(data) < FORWARD_POINTER_SIZE {
+		return RID{pageID: slotted_page.NULL_PAGE_ID}
+	}
+
+	return RID{
+		pageID: binary.LittleEndian.Uint32(data),
+		slotID: binary.LittleEndian.Uint16(data[4:]),
+	}
+}
+
+func encodeOverflow(size uint32, pageID uint32) []byte {
+	data := make([]byte, OVERFLOW_POINTER_SIZE)
+	binary.LittleEndian.PutUint32(data, size)
+	binary.LittleEndian.PutUint32(data[4:], pageID)
+	return data
+}
+
+func decodeOverflow(data []byte) (uint32, uint32) {
+	if len(data) < OVERFLOW_POINTER_SIZE {
+		return 0, slotted_page.NULL_PAGE_ID
+	}
+
+	return binary.LittleEndian.Uint32(data), binary.LittleEndian.Uint32(data[4:])
+}
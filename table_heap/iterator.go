@@ -5,39 +5,56 @@ import (
 	"gobase/slotted_page"
 )
 
+// Next reads a whole page at a time, so rows can be deleted while scanning
+// even when that frees the page the scan is on.
 func (ti *TableIterator) Next() (*RID, shared.Tuple, bool) {
-	for {
-		if ti.currentPageID == slotted_page.NULL_PAGE_ID {
-			return nil, nil, false
+	for ti.err == nil {
+		for len(ti.pending) > 0 {
+			entry := ti.pending[0]
+			ti.pending = ti.pending[1:]
+
+			payload, err := ti.th.resolve(entry.tuple)
+			if err == nil {
+				return &entry.rid, payload, true
+			}
+			if !isGone(err) {
+				ti.err = err
+				return nil, nil, false
+			}
 		}
 
-		frame, err := ti.th.bpm.FetchPage(uint32(ti.currentPageID))
-		if err != nil {
+		if ti.nextPageID == slotted_page.NULL_PAGE_ID {
 			return nil, nil, false
 		}
 
-		sp := slotted_page.FromData(frame.Data)
-		numSlots := sp.GetNumSlots()
+		ti.err = ti.loadPage()
+	}
 
-		if ti.currentSlotID >= numSlots {
-			nextPageID := sp.GetNextPageID()
-			ti.th.bpm.UnpinPage(uint32(ti.currentPageID), false)
-			ti.currentPageID = nextPageID
-			ti.currentSlotID = 0
-			continue
-		}
+	return nil, nil, false
+}
 
-		tuple, err := sp.GetTuple(ti.currentSlotID)
-		if err != nil {
-			ti.currentSlotID++
-			ti.th.bpm.UnpinPage(uint32(ti.currentPageID), false)
-			continue
-		}
+// Err returns the error that ended the scan, if any.
+func (ti *TableIterator) Err() error {
+	return ti.err
+}
 
-		rid := NewRID(ti.currentPageID, ti.currentSlotID)
-		ti.currentSlotID++
-		ti.th.bpm.UnpinPage(uint32(ti.currentPageID), false)
+func (ti *TableIterator) loadPage() error {
+	pageID := ti.nextPageID
 
-		return rid, tuple, true
-	}
+	return ti.th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+		for slotID := uint16(0); slotID < sp.GetNumSlots(); slotID++ {
+			tuple, err := sp.GetTuple(slotID)
+			if isGone(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			ti.pending = append(ti.pending, iteratorEntry{rid: *NewRID(pageID, slotID), tuple: tuple})
+		}
+
+		ti.nextPageID = sp.GetNextPageID()
+		return nil
+	})
 }
@@ -0,0 +1,138 @@
+package table_heap
+
+import (
+	"encoding/binary"
+
+	"gobase/shared"
+	"gobase/slotted_page"
+	"gobase/transaction_manager"
+)
+
+// Payloads above a quarter of the page size are written to a chain of overflow
+// pages and the slot only keeps their length and first page.
+func (th *TableHeap) storePayload(payload shared.Tuple) (uint8, []byte, error) {
+	if len(payload) <= int(th.bpm.GetPageSize())/OVERFLOW_FRACTION {
+		return 0, payload, nil
+	}
+
+	firstPageID, err := th.writeOverflow(payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return TUPLE_OVERFLOW, encodeOverflow(uint32(len(payload)), firstPageID), nil
+}
+
+func (th *TableHeap) loadPayload(tuple shared.Tuple) (shared.Tuple, error) {
+	_, payload := unwrapTuple(tuple)
+	if !isOverflow(tuple) {
+		return payload, nil
+	}
+
+	size, pageID := decodeOverflow(payload)
+	return th.readOverflow(size, pageID)
+}
+
+func (th *TableHeap) writeOverflow(payload []byte) (uint32, error) {
+	err := th.reclaimPinnedPages()
+	if err != nil {
+		return 0, err
+	}
+
+	chunkSize := int(th.bpm.GetPageSize() - OVERFLOW_HEADER_SIZE)
+	nextPageID := slotted_page.NULL_PAGE_ID
+
+	for end := len(payload); end > 0; end -= chunkSize {
+		chunk := payload[max(end-chunkSize, 0):end]
+
+		pageID, _, err := th.bpm.NewPage()
+		if err != nil {
+			th.freeOverflow(nextPageID)
+			return 0, err
+		}
+		th.bpm.UnpinPage(pageID, true)
+
+		next := nextPageID
+		err = updatePage(th.bpm, pageID, func(data []byte) error {
+			binary.LittleEndian.PutUint32(data[OVERFLOW_NEXT_PAGE_ID_OFFSET:], next)
+			binary.LittleEndian.PutUint32(data[OVERFLOW_DATA_SIZE_OFFSET:], uint32(len(chunk)))
+			copy(data[OVERFLOW_HEADER_SIZE:], chunk)
+			return nil
+		})
+		if err != nil {
+			th.freeOverflow(pageID)
+			return 0, err
+		}
+
+		nextPageID = pageID
+	}
+
+	return nextPageID, nil
+}
+
+func (th *TableHeap) readOverflow(size uint32, pageID uint32) (shared.Tuple, error) {
+	payload := make(shared.Tuple, 0, size)
+
+	for pageID != slotted_page.NULL_PAGE_ID && uint32(len(payload)) < size {
+		frame, err := th.bpm.FetchPage(pageID)
+		if err != nil {
+			return nil, err
+		}
+
+		frame.RLatch()
+		pageID = binary.LittleEndian.Uint32(frame.Data[OVERFLOW_NEXT_PAGE_ID_OFFSET:])
+		chunkSize := binary.LittleEndian.Uint32(frame.Data[OVERFLOW_DATA_SIZE_OFFSET:])
+		if chunkSize <= uint32(len(frame.Data))-OVERFLOW_HEADER_SIZE {
+			payload = append(payload, frame.Data[OVERFLOW_HEADER_SIZE:OVERFLOW_HEADER_SIZE+chunkSize]...)
+		}
+		frame.RUnlatch()
+		th.bpm.UnpinPage(frame.PageID, false)
+	}
+
+	if uint32(len(payload)) != size {
+		return nil, ErrOverflowChainCorrupted
+	}
+
+	return payload, nil
+}
+
+func (th *TableHeap) freeOverflow(pageID uint32) error {
+	for pageID != slotted_page.NULL_PAGE_ID {
+		frame, err := th.bpm.FetchPage(pageID)
+		if err != nil {
+			return err
+		}
+
+		frame.RLatch()
+		nextPageID := binary.LittleEndian.Uint32(frame.Data[OVERFLOW_NEXT_PAGE_ID_OFFSET:])
+		frame.RUnlatch()
+		th.bpm.UnpinPage(pageID, false)
+
+		err = th.deletePage(pageID)
+		if err != nil {
+			return err
+		}
+
+		pageID = nextPageID
+	}
+
+	return nil
+}
+
+// Inside a transaction the chain is only freed on commit, since an undo may
+// still put the tuple that points at it back.
+func (th *TableHeap) releaseOverflow(txn *transaction_manager.Transaction, tuple shared.Tuple) error {
+	if !isOverflow(tuple) {
+		return nil
+	}
+
+	_, pageID := decodeOverflow(tuple[TUPLE_HEADER_SIZE:])
+	if isRunning(txn) {
+		txn.AddCommitAction(func() error {
+			return th.freeOverflow(pageID)
+		})
+		return nil
+	}
+
+	return th.freeOverflow(pageID)
+}
@@ -0,0 +1,146 @@
+package table_heap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsert_LargeTuple(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "just below the overflow threshold", size: 1024},
+		{name: "just above the overflow threshold", size: 1025},
+		{name: "several pages", size: 20000},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rid, err := th.Insert(nil, newTestTuple(byte(i+1), tt.size))
+			require.NoError(t, err)
+
+			raw, err := th.getRaw(*rid)
+			require.NoError(t, err)
+			assert.Equal(t, tt.size > 1024, isOverflow(raw))
+
+			tuple, err := th.Get(*rid)
+			require.NoError(t, err)
+			assert.Equal(t, newTestTuple(byte(i+1), tt.size), tuple)
+			assert.Equal(t, tuple, scanTuples(t, th)[*rid])
+		})
+	}
+}
+
+func TestUpdate_LargeToSmallFreesOverflowPages(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rid, err := th.Insert(nil, newTestTuple(1, 20000))
+	require.NoError(t, err)
+	numPages := th.bpm.GetDiskManager().NumPages
+
+	require.NoError(t, th.Update(nil, *rid, newTestTuple(2, 10)))
+
+	tuple, err := th.Get(*rid)
+	require.NoError(t, err)
+	assert.Equal(t, newTestTuple(2, 10), tuple)
+
+	require.NoError(t, th.Update(nil, *rid, newTestTuple(3, 20000)))
+	assert.Equal(t, numPages, th.bpm.GetDiskManager().NumPages)
+
+	tuple, err = th.Get(*rid)
+	require.NoError(t, err)
+	assert.Equal(t, newTestTuple(3, 20000), tuple)
+}
+
+func TestDelete_LargeTupleFreesOverflowPages(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rid, err := th.Insert(nil, newTestTuple(1, 20000))
+	require.NoError(t, err)
+	numPages := th.bpm.GetDiskManager().NumPages
+
+	require.NoError(t, th.Delete(nil, *rid))
+
+	_, err = th.Insert(nil, newTestTuple(2, 20000))
+	require.NoError(t, err)
+	assert.Equal(t, numPages, th.bpm.GetDiskManager().NumPages)
+}
+
+func TestDelete_PinnedOverflowPageIsReclaimed(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rid, err := th.Insert(nil, newTestTuple(1, 20000))
+	require.NoError(t, err)
+	numPages := th.bpm.GetDiskManager().NumPages
+
+	raw, err := th.getRaw(*rid)
+	require.NoError(t, err)
+	_, overflowPageID := decodeOverflow(raw[TUPLE_HEADER_SIZE:])
+
+	_, err = th.bpm.FetchPage(overflowPageID)
+	require.NoError(t, err)
+
+	require.NoError(t, th.Delete(nil, *rid))
+	assert.Equal(t, []uint32{overflowPageID}, th.pinnedPages)
+
+	rid, err = th.Insert(nil, newTestTuple(2, 20000))
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{overflowPageID}, th.pinnedPages)
+	assert.Equal(t, numPages+1, th.bpm.GetDiskManager().NumPages)
+
+	require.NoError(t, th.bpm.UnpinPage(overflowPageID, false))
+	require.NoError(t, th.Delete(nil, *rid))
+
+	rid, err = th.Insert(nil, newTestTuple(3, 24000))
+	require.NoError(t, err)
+	assert.Empty(t, th.pinnedPages)
+	assert.Equal(t, numPages+1, th.bpm.GetDiskManager().NumPages)
+
+	tuple, err := th.Get(*rid)
+	require.NoError(t, err)
+	assert.Equal(t, newTestTuple(3, 24000), tuple)
+}
+
+func TestDelete_PinnedEmptyPageIsReclaimed(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rids := []*RID{}
+	for i := 0; i < 80; i++ {
+		rid, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+		rids = append(rids, rid)
+	}
+
+	lastPageID := th.lastPageID
+	numPages := th.bpm.GetDiskManager().NumPages
+
+	_, err := th.bpm.FetchPage(lastPageID)
+	require.NoError(t, err)
+
+	for _, rid := range rids {
+		if rid.GetPageID() == lastPageID {
+			require.NoError(t, th.Delete(nil, *rid))
+		}
+	}
+	assert.Equal(t, []uint32{lastPageID}, th.pinnedPages)
+	assert.NotEqual(t, lastPageID, th.lastPageID)
+
+	require.NoError(t, th.bpm.UnpinPage(lastPageID, false))
+
+	for i := 0; i < 20; i++ {
+		_, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+	}
+	assert.Empty(t, th.pinnedPages)
+	assert.Equal(t, numPages, th.bpm.GetDiskManager().NumPages)
+}
@@ -1,28 +1,45 @@
 package table_heap
 
 import (
+	"encoding/binary"
+	"sync"
+
 	"gobase/buffer_pool_manager"
+	"gobase/free_space_map"
+	"gobase/log_manager"
+	"gobase/shared"
 	"gobase/slotted_page"
 )
 
 type RID struct {
-	pageID uint16
+	pageID uint32
 	slotID uint16
 }
 
 type TableHeap struct {
-	bpm         *buffer_pool_manager.BufferPoolManager
-	firstPageID uint16
-	lastPageID  uint16
+	mu           sync.Mutex
+	bpm          *buffer_pool_manager.BufferPoolManager
+	fsm          *free_space_map.FreeSpaceMap
+	headerPageID uint32
+	firstPageID  uint32
+	lastPageID   uint32
+	pinnedMu     sync.Mutex
+	pinnedPages  []uint32
 }
 
 type TableIterator struct {
-	th            *TableHeap
-	currentPageID uint16
-	currentSlotID uint16
+	th         *TableHeap
+	nextPageID uint32
+	pending    []iteratorEntry
+	err        error
+}
+
+type iteratorEntry struct {
+	rid   RID
+	tuple shared.Tuple
 }
 
-func NewRID(pageID, slotID uint16) *RID {
+func NewRID(pageID uint32, slotID uint16) *RID {
 	return &RID{
 		pageID: pageID,
 		slotID: slotID,
@@ -30,18 +47,93 @@ func NewRID(pageID, slotID uint16) *RID {
 }
 
 func NewTableHeap(bpm *buffer_pool_manager.BufferPoolManager) (*TableHeap, error) {
+	headerPageID, _, err := bpm.NewPage()
+	if err != nil {
+		return nil, err
+	}
+	bpm.UnpinPage(headerPageID, true)
+
 	pageID, frame, err := bpm.NewPage()
 	if err != nil {
 		return nil, err
 	}
 
+	th := &TableHeap{
+		bpm:          bpm,
+		headerPageID: headerPageID,
+		firstPageID:  pageID,
+		lastPageID:   pageID,
+	}
+
+	frame.WLatch()
+	sp := slotted_page.FromData(frame.Data)
 	slotted_page.InitSlottedPage(frame.Data)
 
+	lsn, err := th.appendLog(nil, &log_manager.LogRecord{
+		Type:       log_manager.RecordNewPage,
+		PageID:     pageID,
+		PrevPageID: slotted_page.NULL_PAGE_ID,
+	})
+	if err != nil {
+		frame.WUnlatch()
+		bpm.UnpinPage(pageID, true)
+		return nil, err
+	}
+
+	sp.SetLSN(lsn)
+	freeSpace := sp.GetFreeSpace()
+	frame.WUnlatch()
 	bpm.UnpinPage(pageID, true)
 
-	return &TableHeap{
-		bpm:         bpm,
-		firstPageID: uint16(pageID),
-		lastPageID:  uint16(pageID),
-	}, nil
+	th.fsm, err = free_space_map.NewFreeSpaceMap(bpm)
+	if err != nil {
+		return nil, err
+	}
+
+	err = th.fsm.Update(pageID, freeSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = th.writeHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	return th, nil
+}
+
+func OpenTableHeap(bpm *buffer_pool_manager.BufferPoolManager, headerPageID uint32) (*TableHeap, error) {
+	frame, err := bpm.FetchPage(headerPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	frame.RLatch()
+	th := &TableHeap{
+		bpm:          bpm,
+		headerPageID: headerPageID,
+		firstPageID:  binary.LittleEndian.Uint32(frame.Data[FIRST_PAGE_ID_OFFSET:]),
+		lastPageID:   binary.LittleEndian.Uint32(frame.Data[LAST_PAGE_ID_OFFSET:]),
+	}
+	fsmPageID := binary.LittleEndian.Uint32(frame.Data[FSM_PAGE_ID_OFFSET:])
+	version := binary.LittleEndian.Uint32(frame.Data[VERSION_OFFSET:])
+	frame.RUnlatch()
+	bpm.UnpinPage(headerPageID, false)
+
+	if version != FORMAT_VERSION {
+		return nil, ErrUnsupportedVersion
+	}
+
+	th.fsm, err = free_space_map.OpenFreeSpaceMap(bpm, fsmPageID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = th.repairLastPage()
+	if err != nil {
+		return nil, err
+	}
+
+	return th, nil
 }
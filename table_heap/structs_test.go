@@ -0,0 +1,122 @@
+package table_heap
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"gobase/slotted_page"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenTableHeap(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	for i := 0; i < 100; i++ {
+		_, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+	}
+
+	reopened, err := reopenTestTableHeap(t, th)
+	require.NoError(t, err)
+
+	assert.Equal(t, th.firstPageID, reopened.firstPageID)
+	assert.Equal(t, th.lastPageID, reopened.lastPageID)
+	assert.Equal(t, scanTuples(t, th), scanTuples(t, reopened))
+
+	rid, err := reopened.Insert(nil, newTestTuple(0xFF, 100))
+	require.NoError(t, err)
+
+	tuple, err := reopened.Get(*rid)
+	require.NoError(t, err)
+	assert.Equal(t, newTestTuple(0xFF, 100), tuple)
+}
+
+func TestOpenTableHeap_ErrUnsupportedVersion(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	err := updatePage(th.bpm, th.GetHeaderPageID(), func(data []byte) error {
+		binary.LittleEndian.PutUint32(data[VERSION_OFFSET:], FORMAT_VERSION+1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = OpenTableHeap(th.bpm, th.GetHeaderPageID())
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestOpenTableHeap_HeaderLagsBehindChain(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	for i := 0; i < 150; i++ {
+		_, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+	}
+
+	pageIDs := chainPageIDs(t, th)
+	require.Len(t, pageIDs, 4)
+
+	setTestLastPageID(t, th, pageIDs[2])
+	require.NoError(t, th.fsm.Remove(pageIDs[3]))
+
+	// The pages in front of the lagging last page are not walked again.
+	require.NoError(t, th.bpm.FlushAllPages())
+	dm := th.bpm.GetDiskManager()
+	_, err := dm.File.WriteAt([]byte("X"), int64(pageIDs[0])*int64(dm.PageSize)+int64(slotted_page.HEADER_SIZE))
+	require.NoError(t, err)
+
+	reopened, err := reopenTestTableHeap(t, th)
+	require.NoError(t, err)
+	assert.Equal(t, pageIDs[3], reopened.lastPageID)
+
+	freeSpace, err := reopened.fsm.GetFreeSpace(pageIDs[3])
+	require.NoError(t, err)
+	assert.Positive(t, freeSpace)
+
+	rid, err := reopened.Insert(nil, newTestTuple(0xFF, 1000))
+	require.NoError(t, err)
+	assert.Equal(t, reopened.lastPageID, rid.GetPageID())
+
+	var prevPageID uint32
+	require.NoError(t, reopened.withPage(rid.GetPageID(), false, func(sp *slotted_page.SlottedPage) error {
+		prevPageID = sp.GetPrevPageID()
+		return nil
+	}))
+	assert.Equal(t, pageIDs[3], prevPageID)
+}
+
+func TestOpenTableHeap_LastPageUnlinked(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	for i := 0; i < 100; i++ {
+		_, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+	}
+
+	pageIDs := chainPageIDs(t, th)
+	require.Len(t, pageIDs, 3)
+
+	// The crash hit after the emptied last page was unlinked but before the
+	// header moved back to the page in front of it.
+	require.NoError(t, updatePage(th.bpm, pageIDs[1], func(data []byte) error {
+		slotted_page.FromData(data).SetNextPageID(slotted_page.NULL_PAGE_ID)
+		return nil
+	}))
+
+	reopened, err := reopenTestTableHeap(t, th)
+	require.NoError(t, err)
+	assert.Equal(t, pageIDs[1], reopened.lastPageID)
+
+	freeSpace, err := reopened.fsm.GetFreeSpace(pageIDs[2])
+	require.NoError(t, err)
+	assert.Zero(t, freeSpace)
+
+	rid, err := reopened.Insert(nil, newTestTuple(0xFF, 2000))
+	require.NoError(t, err)
+	assert.NotEqual(t, pageIDs[2], rid.GetPageID())
+}
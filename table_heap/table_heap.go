@@ -1,96 +1,636 @@
 package table_heap
 
 import (
+	"errors"
+	"math"
+
+	"gobase/log_manager"
 	"gobase/shared"
 	"gobase/slotted_page"
+	"gobase/transaction_manager"
 )
 
-func (th *TableHeap) Insert(tuple shared.Tuple) (*RID, error) {
-	lastFrame, err := th.bpm.FetchPage(uint32(th.lastPageID))
+func (th *TableHeap) Insert(txn *transaction_manager.Transaction, tuple shared.Tuple) (*RID, error) {
+	overflow, payload, err := th.storePayload(tuple)
 	if err != nil {
 		return nil, err
 	}
 
-	sp := slotted_page.FromData(lastFrame.Data)
+	stored := wrapTuple(TUPLE_NORMAL|overflow, payload)
+
+	rid, err := th.insertRaw(txn, stored)
+	if err != nil {
+		th.releaseOverflow(nil, stored)
+		return nil, err
+	}
+
+	return rid, nil
+}
+
+func (th *TableHeap) insertRaw(txn *transaction_manager.Transaction, tuple shared.Tuple) (*RID, error) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
 
+	// Free space is tracked as a uint16; a larger size would wrap around in
+	// FindPage and match pages that cannot hold the tuple.
 	spaceNeeded := len(tuple) + int(slotted_page.SLOT_SIZE)
-	if spaceNeeded <= int(sp.GetFreeSpace()) {
-		slotID, err := sp.InsertTuple(tuple)
+	if spaceNeeded > math.MaxUint16 {
+		return nil, slotted_page.ErrTupleTooLarge
+	}
+
+	for spaceNeeded <= int(th.bpm.GetPageSize()) {
+		pageID, found, err := th.fsm.FindPage(uint16(spaceNeeded))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+
+		rid, err := th.insertIntoExistingPage(txn, pageID, tuple)
 		if err != nil {
-			th.bpm.UnpinPage(uint32(th.lastPageID), false)
 			return nil, err
 		}
+		if rid != nil {
+			return rid, nil
+		}
+	}
+
+	return th.insertIntoNewPage(txn, tuple)
+}
 
-		th.bpm.UnpinPage(uint32(th.lastPageID), true)
-		return NewRID(th.lastPageID, slotID), nil
+func (th *TableHeap) insertIntoExistingPage(txn *transaction_manager.Transaction, pageID uint32, tuple shared.Tuple) (*RID, error) {
+	var rid *RID
+	var freeSpace uint16
+
+	err := th.withPage(pageID, true, func(sp *slotted_page.SlottedPage) error {
+		slotID, err := th.insertIntoPage(txn, sp, pageID, tuple)
+		if err != nil && !errors.Is(err, slotted_page.ErrNotEnoughSpace) {
+			return err
+		}
+		if err == nil {
+			rid = NewRID(pageID, slotID)
+		}
+
+		freeSpace = sp.GetFreeSpace()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = th.fsm.Update(pageID, freeSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	if rid == nil {
+		return nil, nil
+	}
+
+	return th.inserted(txn, rid), nil
+}
+
+func (th *TableHeap) insertIntoNewPage(txn *transaction_manager.Transaction, tuple shared.Tuple) (*RID, error) {
+	err := th.reclaimPinnedPages()
+	if err != nil {
+		return nil, err
+	}
+
+	lastPageID := th.lastPageID
+
+	lastFrame, err := th.bpm.FetchPage(lastPageID)
+	if err != nil {
+		return nil, err
 	}
 
 	newPageID, newFrame, err := th.bpm.NewPage()
 	if err != nil {
-		th.bpm.UnpinPage(uint32(th.lastPageID), false)
+		th.bpm.UnpinPage(lastPageID, false)
 		return nil, err
 	}
 
-	slotted_page.InitSlottedPage(newFrame.Data)
+	lastFrame.WLatch()
+	newFrame.WLatch()
+	sp := slotted_page.FromData(lastFrame.Data)
 	newSp := slotted_page.FromData(newFrame.Data)
+	slotted_page.InitSlottedPage(newFrame.Data)
+
+	sp.SetNextPageID(newPageID)
+	newSp.SetPrevPageID(lastPageID)
 
-	sp.SetNextPageID(uint16(newPageID))
-	newSp.SetPrevPageID(th.lastPageID)
+	lsn, err := th.appendLog(nil, &log_manager.LogRecord{
+		Type:       log_manager.RecordNewPage,
+		PageID:     newPageID,
+		PrevPageID: lastPageID,
+	})
+	if err == nil {
+		sp.SetLSN(lsn)
+		newSp.SetLSN(lsn)
+		th.lastPageID = newPageID
+	}
+
+	lastFrame.WUnlatch()
+	th.bpm.UnpinPage(lastPageID, true)
 
-	slotID, err := newSp.InsertTuple(tuple)
 	if err != nil {
-		th.bpm.UnpinPage(uint32(th.lastPageID), false)
-		th.bpm.UnpinPage(newPageID, false)
+		newFrame.WUnlatch()
+		th.bpm.UnpinPage(newPageID, true)
 		return nil, err
 	}
 
-	oldLastPageID := th.lastPageID
-	th.lastPageID = uint16(newPageID)
-
-	th.bpm.UnpinPage(uint32(oldLastPageID), true)
+	slotID, err := th.insertIntoPage(txn, newSp, newPageID, tuple)
+	freeSpace := newSp.GetFreeSpace()
+	newFrame.WUnlatch()
 	th.bpm.UnpinPage(newPageID, true)
-	return NewRID(uint16(newPageID), slotID), nil
+
+	updateErr := th.fsm.Update(newPageID, freeSpace)
+	if updateErr == nil {
+		updateErr = th.writeHeader()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if updateErr != nil {
+		return nil, updateErr
+	}
+
+	return th.inserted(txn, NewRID(newPageID, slotID)), nil
+}
+
+func (th *TableHeap) insertIntoPage(txn *transaction_manager.Transaction, sp *slotted_page.SlottedPage, pageID uint32, tuple shared.Tuple) (uint16, error) {
+	slotID, err := sp.InsertTuple(tuple)
+	if err != nil {
+		return 0, err
+	}
+
+	lsn, err := th.appendLog(txn, &log_manager.LogRecord{
+		Type:   log_manager.RecordInsertTuple,
+		PageID: pageID,
+		SlotID: slotID,
+		Data:   tuple,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sp.SetLSN(lsn)
+	return slotID, nil
+}
+
+func (th *TableHeap) inserted(txn *transaction_manager.Transaction, rid *RID) *RID {
+	if isRunning(txn) {
+		undoRID := *rid
+		txn.AddUndoAction(func() error {
+			return th.deleteRaw(txn, undoRID)
+		})
+	}
+
+	return rid
 }
 
 func (th *TableHeap) Get(rid RID) (shared.Tuple, error) {
-	frame, err := th.bpm.FetchPage(uint32(rid.pageID))
+	tuple, err := th.getRaw(rid)
+	if err != nil {
+		return nil, err
+	}
+
+	return th.resolve(tuple)
+}
+
+func (th *TableHeap) resolve(tuple shared.Tuple) (shared.Tuple, error) {
+	kind, payload := unwrapTuple(tuple)
+	switch kind {
+	case TUPLE_NORMAL:
+		return th.loadPayload(tuple)
+	case TUPLE_FORWARD:
+		moved, err := th.getRaw(decodeForward(payload))
+		if err != nil {
+			return nil, err
+		}
+
+		kind, _ = unwrapTuple(moved)
+		if kind == TUPLE_MOVED {
+			return th.loadPayload(moved)
+		}
+	}
+
+	return nil, slotted_page.ErrTupleHasBeenDeleted
+}
+
+// GetMarked returns a tuple deleted by a transaction that has not committed
+// yet, so callers can tell a pending delete from a row that is really gone.
+func (th *TableHeap) GetMarked(rid RID) (shared.Tuple, error) {
+	tuple, err := th.getMarkedRaw(rid)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, payload := unwrapTuple(tuple)
+	switch kind {
+	case TUPLE_NORMAL:
+		return th.loadPayload(tuple)
+	case TUPLE_FORWARD:
+		moved, err := th.getMarkedRaw(decodeForward(payload))
+		if err != nil {
+			return nil, err
+		}
+
+		kind, _ = unwrapTuple(moved)
+		if kind == TUPLE_MOVED {
+			return th.loadPayload(moved)
+		}
+	}
+
+	return nil, slotted_page.ErrTupleHasBeenDeleted
+}
+
+func (th *TableHeap) getMarkedRaw(rid RID) (shared.Tuple, error) {
+	var tuple shared.Tuple
+
+	err := th.withPage(rid.pageID, false, func(sp *slotted_page.SlottedPage) error {
+		var err error
+		tuple, err = sp.GetMarkedTuple(rid.slotID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	sp := slotted_page.FromData(frame.Data)
+	return tuple, nil
+}
+
+func (th *TableHeap) getRaw(rid RID) (shared.Tuple, error) {
+	var tuple shared.Tuple
 
-	tuple, err := sp.GetTuple(rid.slotID)
+	err := th.withPage(rid.pageID, false, func(sp *slotted_page.SlottedPage) error {
+		var err error
+		tuple, err = sp.GetTuple(rid.slotID)
+		return err
+	})
 	if err != nil {
-		th.bpm.UnpinPage(uint32(rid.pageID), false)
 		return nil, err
 	}
 
-	th.bpm.UnpinPage(uint32(rid.pageID), false)
 	return tuple, nil
 }
 
-func (th *TableHeap) Delete(rid RID) error {
-	frame, err := th.bpm.FetchPage(uint32(rid.pageID))
+func (th *TableHeap) Update(txn *transaction_manager.Transaction, rid RID, tuple shared.Tuple) error {
+	current, err := th.getRaw(rid)
+	if err != nil {
+		return err
+	}
+
+	kind, payload := unwrapTuple(current)
+	if kind != TUPLE_NORMAL && kind != TUPLE_FORWARD {
+		return slotted_page.ErrTupleHasBeenDeleted
+	}
+
+	overflow, stored, err := th.storePayload(tuple)
+	if err != nil {
+		return err
+	}
+
+	if kind == TUPLE_NORMAL {
+		err = th.updateRaw(txn, rid, wrapTuple(TUPLE_NORMAL|overflow, stored))
+		if !errors.Is(err, slotted_page.ErrNotEnoughSpace) {
+			return err
+		}
+
+		return th.forward(txn, rid, overflow, stored)
+	}
+
+	movedRID := decodeForward(payload)
+
+	err = th.updateRaw(txn, movedRID, wrapTuple(TUPLE_MOVED|overflow, stored))
+	if !errors.Is(err, slotted_page.ErrNotEnoughSpace) {
+		return err
+	}
+
+	err = th.forward(txn, rid, overflow, stored)
+	if err != nil {
+		return err
+	}
+
+	return th.deleteRaw(txn, movedRID)
+}
+
+func (th *TableHeap) forward(txn *transaction_manager.Transaction, rid RID, overflow uint8, payload []byte) error {
+	movedRID, err := th.insertRaw(txn, wrapTuple(TUPLE_MOVED|overflow, payload))
+	if err != nil {
+		return err
+	}
+
+	err = th.updateRaw(txn, rid, wrapTuple(TUPLE_FORWARD, encodeForward(*movedRID)))
+	if err != nil {
+		th.deleteRaw(txn, *movedRID)
+		return err
+	}
+
+	return nil
+}
+
+func (th *TableHeap) updateRaw(txn *transaction_manager.Transaction, rid RID, tuple shared.Tuple) error {
+	var oldTuple shared.Tuple
+
+	err := th.modifyPage(rid.pageID, func(sp *slotted_page.SlottedPage) error {
+		var err error
+		oldTuple, err = sp.GetTuple(rid.slotID)
+		if err != nil {
+			return err
+		}
+
+		err = sp.UpdateTuple(rid.slotID, tuple)
+		if err != nil {
+			return err
+		}
+
+		lsn, err := th.appendLog(txn, &log_manager.LogRecord{
+			Type:   log_manager.RecordUpdateTuple,
+			PageID: rid.pageID,
+			SlotID: rid.slotID,
+			Data:   log_manager.EncodeUpdate(oldTuple, tuple),
+		})
+		if err != nil {
+			return err
+		}
+
+		sp.SetLSN(lsn)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	sp := slotted_page.FromData(frame.Data)
-	err = sp.DeleteTuple(rid.slotID)
+	if isRunning(txn) {
+		txn.AddUndoAction(func() error {
+			return th.revertUpdate(txn, rid, oldTuple)
+		})
+	}
+
+	return th.releaseOverflow(txn, oldTuple)
+}
+
+func (th *TableHeap) revertUpdate(txn *transaction_manager.Transaction, rid RID, oldTuple shared.Tuple) error {
+	err := th.updateRaw(txn, rid, oldTuple)
+	if !errors.Is(err, slotted_page.ErrNotEnoughSpace) {
+		return err
+	}
+
+	kind, payload := unwrapTuple(oldTuple)
+	if kind != TUPLE_NORMAL {
+		return err
+	}
+
+	return th.forward(txn, rid, oldTuple[0]&TUPLE_OVERFLOW, payload)
+}
+
+func (th *TableHeap) Delete(txn *transaction_manager.Transaction, rid RID) error {
+	tuple, err := th.getRaw(rid)
 	if err != nil {
-		th.bpm.UnpinPage(uint32(rid.pageID), false)
 		return err
 	}
 
-	th.bpm.UnpinPage(uint32(rid.pageID), true)
+	kind, payload := unwrapTuple(tuple)
+	switch kind {
+	case TUPLE_NORMAL:
+		return th.deleteRaw(txn, rid)
+	case TUPLE_FORWARD:
+		err = th.deleteRaw(txn, decodeForward(payload))
+		if err != nil {
+			return err
+		}
+
+		return th.deleteRaw(txn, rid)
+	}
+
+	return slotted_page.ErrTupleHasBeenDeleted
+}
+
+func (th *TableHeap) deleteRaw(txn *transaction_manager.Transaction, rid RID) error {
+	var tuple shared.Tuple
+	deferred := isRunning(txn)
+
+	err := th.withPage(rid.pageID, true, func(sp *slotted_page.SlottedPage) error {
+		var err error
+		tuple, err = sp.GetTuple(rid.slotID)
+		if err != nil {
+			return err
+		}
+
+		if deferred {
+			err = sp.MarkDeleteTuple(rid.slotID)
+		} else {
+			err = sp.DeleteTuple(rid.slotID)
+		}
+		if err != nil {
+			return err
+		}
+
+		lsn, err := th.appendLog(txn, &log_manager.LogRecord{
+			Type:   log_manager.RecordDeleteTuple,
+			PageID: rid.pageID,
+			SlotID: rid.slotID,
+			Data:   tuple,
+		})
+		if err != nil {
+			return err
+		}
+
+		sp.SetLSN(lsn)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !deferred {
+		err = th.updateFreeSpace(rid.pageID)
+		if err != nil {
+			return err
+		}
+
+		err = th.releaseOverflow(nil, tuple)
+		if err != nil {
+			return err
+		}
+
+		return th.freePageIfEmpty(rid.pageID)
+	}
+
+	txn.AddUndoAction(func() error {
+		return th.restoreRaw(txn, rid, tuple)
+	})
+	txn.AddCommitAction(func() error {
+		err := th.applyDelete(rid)
+		if err != nil {
+			return err
+		}
+
+		return th.releaseOverflow(nil, tuple)
+	})
+
+	return nil
+}
+
+func (th *TableHeap) applyDelete(rid RID) error {
+	err := th.modifyPage(rid.pageID, func(sp *slotted_page.SlottedPage) error {
+		return sp.DeleteTuple(rid.slotID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return th.freePageIfEmpty(rid.pageID)
+}
+
+// The first page is never freed so the header always points at a live page.
+func (th *TableHeap) freePageIfEmpty(pageID uint32) error {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	if pageID == th.firstPageID {
+		return nil
+	}
+
+	var empty bool
+	var prevPageID, nextPageID uint32
+
+	err := th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+		empty = sp.IsEmpty()
+		prevPageID = sp.GetPrevPageID()
+		nextPageID = sp.GetNextPageID()
+		return nil
+	})
+	if err != nil || !empty {
+		return err
+	}
+
+	err = updatePage(th.bpm, prevPageID, func(data []byte) error {
+		slotted_page.FromData(data).SetNextPageID(nextPageID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if nextPageID == slotted_page.NULL_PAGE_ID {
+		th.lastPageID = prevPageID
+		err = th.writeHeader()
+	} else {
+		err = updatePage(th.bpm, nextPageID, func(data []byte) error {
+			slotted_page.FromData(data).SetPrevPageID(prevPageID)
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	err = th.fsm.Remove(pageID)
+	if err != nil {
+		return err
+	}
+
+	return th.deletePage(pageID)
+}
+
+func (th *TableHeap) Restore(txn *transaction_manager.Transaction, rid RID, tuple shared.Tuple) error {
+	overflow, payload, err := th.storePayload(tuple)
+	if err != nil {
+		return err
+	}
+
+	return th.restoreRaw(txn, rid, wrapTuple(TUPLE_NORMAL|overflow, payload))
+}
+
+func (th *TableHeap) restoreRaw(txn *transaction_manager.Transaction, rid RID, tuple shared.Tuple) error {
+	err := th.modifyPage(rid.pageID, func(sp *slotted_page.SlottedPage) error {
+		err := sp.RestoreTuple(rid.slotID, tuple)
+		if err != nil {
+			return err
+		}
+
+		lsn, err := th.appendLog(txn, &log_manager.LogRecord{
+			Type:   log_manager.RecordInsertTuple,
+			PageID: rid.pageID,
+			SlotID: rid.slotID,
+			Data:   tuple,
+		})
+		if err != nil {
+			return err
+		}
+
+		sp.SetLSN(lsn)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	th.inserted(txn, &rid)
 	return nil
 }
 
 func (th *TableHeap) Scan() *TableIterator {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
 	return &TableIterator{
-		th:            th,
-		currentPageID: th.firstPageID,
-		currentSlotID: 0,
+		th:         th,
+		nextPageID: th.firstPageID,
 	}
 }
+
+func (th *TableHeap) Drop() error {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	pageID := th.firstPageID
+	for pageID != slotted_page.NULL_PAGE_ID {
+		var nextPageID uint32
+		var overflowed []shared.Tuple
+
+		err := th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+			for slotID := uint16(0); slotID < sp.GetNumSlots(); slotID++ {
+				tuple, err := sp.GetTuple(slotID)
+				if err == nil && isOverflow(tuple) {
+					overflowed = append(overflowed, tuple)
+				}
+			}
+
+			nextPageID = sp.GetNextPageID()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, tuple := range overflowed {
+			err = th.releaseOverflow(nil, tuple)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = th.bpm.DeletePage(pageID)
+		if err != nil {
+			return err
+		}
+
+		pageID = nextPageID
+	}
+
+	err := th.reclaimPinnedPages()
+	if err != nil {
+		return err
+	}
+
+	err = th.fsm.Drop()
+	if err != nil {
+		return err
+	}
+
+	return th.bpm.DeletePage(th.headerPageID)
+}
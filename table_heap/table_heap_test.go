@@ -0,0 +1,174 @@
+package table_heap
+
+import (
+	"math"
+	"testing"
+
+	"gobase/slotted_page"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertAndGet(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rids := []*RID{}
+	for i := 0; i < 100; i++ {
+		rid, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+		rids = append(rids, rid)
+	}
+
+	assert.NotEqual(t, th.firstPageID, th.lastPageID)
+
+	for i, rid := range rids {
+		tuple, err := th.Get(*rid)
+		require.NoError(t, err)
+		assert.Equal(t, newTestTuple(byte(i), 100), tuple)
+	}
+
+	assert.Len(t, scanTuples(t, th), 100)
+}
+
+func TestInsert_ReusesSpaceOnEarlierPages(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rids := []*RID{}
+	for i := 0; i < 100; i++ {
+		rid, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+		rids = append(rids, rid)
+	}
+
+	for _, rid := range rids[:10] {
+		require.NoError(t, th.Delete(nil, *rid))
+	}
+
+	numPages := th.bpm.GetDiskManager().NumPages
+	lastPageID := th.lastPageID
+
+	for i := 0; i < 10; i++ {
+		rid, err := th.Insert(nil, newTestTuple(0xAA, 100))
+		require.NoError(t, err)
+		assert.Equal(t, th.firstPageID, rid.GetPageID())
+	}
+
+	assert.Equal(t, numPages, th.bpm.GetDiskManager().NumPages)
+	assert.Equal(t, lastPageID, th.lastPageID)
+}
+
+func TestInsert_ReusesDeletedSlot(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	first, err := th.Insert(nil, newTestTuple(1, 10))
+	require.NoError(t, err)
+	second, err := th.Insert(nil, newTestTuple(2, 10))
+	require.NoError(t, err)
+
+	require.NoError(t, th.Delete(nil, *first))
+
+	_, err = th.Get(*first)
+	require.ErrorIs(t, err, slotted_page.ErrTupleHasBeenDeleted)
+
+	third, err := th.Insert(nil, newTestTuple(3, 10))
+	require.NoError(t, err)
+	assert.Equal(t, *first, *third)
+
+	tuple, err := th.Get(*second)
+	require.NoError(t, err)
+	assert.Equal(t, newTestTuple(2, 10), tuple)
+}
+
+func TestUpdate(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rids := []*RID{}
+	for i := 0; i < 40; i++ {
+		rid, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+		rids = append(rids, rid)
+	}
+	rid := *rids[0]
+
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "same size", size: 100},
+		{name: "smaller", size: 10},
+		{name: "moved to another page", size: 900},
+		{name: "moved again", size: 1000},
+		{name: "back to small", size: 20},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, th.Update(nil, rid, newTestTuple(byte(0xA0+i), tt.size)))
+
+			tuple, err := th.Get(rid)
+			require.NoError(t, err)
+			assert.Equal(t, newTestTuple(byte(0xA0+i), tt.size), tuple)
+
+			tuples := scanTuples(t, th)
+			assert.Len(t, tuples, 40)
+			assert.Equal(t, tuple, tuples[rid])
+		})
+	}
+}
+
+func TestUpdate_DeletedTuple(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rid, err := th.Insert(nil, newTestTuple(1, 10))
+	require.NoError(t, err)
+	require.NoError(t, th.Delete(nil, *rid))
+
+	err = th.Update(nil, *rid, newTestTuple(2, 10))
+	require.ErrorIs(t, err, slotted_page.ErrTupleHasBeenDeleted)
+
+	err = th.Delete(nil, *rid)
+	require.ErrorIs(t, err, slotted_page.ErrTupleHasBeenDeleted)
+}
+
+func TestDelete_FreesEmptyPages(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	rids := []*RID{}
+	for i := 0; i < 100; i++ {
+		rid, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+		rids = append(rids, rid)
+	}
+
+	numPages := th.bpm.GetDiskManager().NumPages
+	for _, rid := range rids {
+		require.NoError(t, th.Delete(nil, *rid))
+	}
+
+	assert.Equal(t, th.firstPageID, th.lastPageID)
+	assert.Empty(t, scanTuples(t, th))
+
+	for i := 0; i < 100; i++ {
+		_, err := th.Insert(nil, newTestTuple(byte(i), 100))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, numPages, th.bpm.GetDiskManager().NumPages)
+}
+
+func TestInsertRaw_ErrTupleTooLarge(t *testing.T) {
+	th, cleanup := newTestTableHeap(t, 16)
+	defer cleanup()
+
+	_, err := th.insertRaw(nil, newTestTuple(1, math.MaxUint16))
+	require.ErrorIs(t, err, slotted_page.ErrTupleTooLarge)
+
+	assert.Equal(t, th.firstPageID, th.lastPageID)
+}
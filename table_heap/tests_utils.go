@@ -0,0 +1,97 @@
+package table_heap
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"gobase/buffer_pool_manager"
+	"gobase/disk_manager"
+	"gobase/shared"
+	"gobase/slotted_page"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTableHeap(t *testing.T, poolSize int) (*TableHeap, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "table_heap_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	dm, err := disk_manager.NewDiskManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	th, err := NewTableHeap(buffer_pool_manager.NewBufferPoolManager(dm, poolSize))
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dm.Close()
+		os.Remove(tmpFile.Name())
+		os.Remove(disk_manager.LogFilePath(tmpFile.Name()))
+	}
+
+	return th, cleanup
+}
+
+func newTestTuple(fill byte, size int) shared.Tuple {
+	tuple := make(shared.Tuple, size)
+	for i := range tuple {
+		tuple[i] = fill
+	}
+
+	return tuple
+}
+
+func scanTuples(t *testing.T, th *TableHeap) map[RID]shared.Tuple {
+	t.Helper()
+
+	tuples := make(map[RID]shared.Tuple)
+	iter := th.Scan()
+	for {
+		rid, tuple, ok := iter.Next()
+		if !ok {
+			break
+		}
+		tuples[*rid] = tuple
+	}
+	require.NoError(t, iter.Err())
+
+	return tuples
+}
+
+// reopenTestTableHeap flushes th and opens it again through a fresh buffer
+// pool, so only what reached the file is seen.
+func reopenTestTableHeap(t *testing.T, th *TableHeap) (*TableHeap, error) {
+	t.Helper()
+
+	require.NoError(t, th.bpm.FlushAllPages())
+	bpm := buffer_pool_manager.NewBufferPoolManager(th.bpm.GetDiskManager(), 16)
+
+	return OpenTableHeap(bpm, th.GetHeaderPageID())
+}
+
+func chainPageIDs(t *testing.T, th *TableHeap) []uint32 {
+	t.Helper()
+
+	pageIDs := []uint32{}
+	for pageID := th.firstPageID; pageID != slotted_page.NULL_PAGE_ID; {
+		pageIDs = append(pageIDs, pageID)
+		require.NoError(t, th.withPage(pageID, false, func(sp *slotted_page.SlottedPage) error {
+			pageID = sp.GetNextPageID()
+			return nil
+		}))
+	}
+
+	return pageIDs
+}
+
+func setTestLastPageID(t *testing.T, th *TableHeap, pageID uint32) {
+	t.Helper()
+
+	require.NoError(t, updatePage(th.bpm, th.GetHeaderPageID(), func(data []byte) error {
+		binary.LittleEndian.PutUint32(data[LAST_PAGE_ID_OFFSET:], pageID)
+		return nil
+	}))
+}
@@ -0,0 +1,9 @@
+package transaction_manager
+
+type TransactionState uint8
+
+const (
+	TransactionRunning TransactionState = iota
+	TransactionCommitted
+	TransactionAborted
+)
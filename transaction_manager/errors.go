@@ -0,0 +1,7 @@
+package transaction_manager
+
+import "errors"
+
+var (
+	ErrTransactionNotRunning = errors.New("transaction is not running")
+)
@@ -0,0 +1,33 @@
+package transaction_manager
+
+func (txn *Transaction) GetID() uint64 {
+	return txn.id
+}
+
+func (txn *Transaction) GetState() TransactionState {
+	return txn.state
+}
+
+func (txn *Transaction) GetPrevLSN() uint64 {
+	return txn.prevLSN
+}
+
+func (txn *Transaction) SetPrevLSN(lsn uint64) {
+	txn.prevLSN = lsn
+}
+
+func (txn *Transaction) AddUndoAction(action func() error) {
+	if txn.state != TransactionRunning {
+		return
+	}
+
+	txn.undoActions = append(txn.undoActions, action)
+}
+
+func (txn *Transaction) AddCommitAction(action func() error) {
+	if txn.state != TransactionRunning {
+		return
+	}
+
+	txn.commitActions = append(txn.commitActions, action)
+}
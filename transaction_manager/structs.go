@@ -0,0 +1,36 @@
+package transaction_manager
+
+import (
+	"sync"
+
+	"gobase/log_manager"
+)
+
+type Transaction struct {
+	id            uint64
+	prevLSN       uint64
+	state         TransactionState
+	undoActions   []func() error
+	commitActions []func() error
+}
+
+type TransactionManager struct {
+	mu        sync.Mutex
+	lm        *log_manager.LogManager
+	nextTxnID uint64
+}
+
+func NewTransaction(id uint64) *Transaction {
+	return &Transaction{
+		id:      id,
+		prevLSN: log_manager.INVALID_LSN,
+		state:   TransactionRunning,
+	}
+}
+
+func NewTransactionManager(lm *log_manager.LogManager) *TransactionManager {
+	return &TransactionManager{
+		lm:        lm,
+		nextTxnID: log_manager.INVALID_TXN_ID + 1,
+	}
+}
@@ -0,0 +1,80 @@
+package transaction_manager
+
+import "gobase/log_manager"
+
+func (tm *TransactionManager) Begin() (*Transaction, error) {
+	tm.mu.Lock()
+	txn := NewTransaction(tm.nextTxnID)
+	tm.nextTxnID++
+	tm.mu.Unlock()
+
+	err := tm.appendLog(txn, log_manager.RecordBegin)
+	if err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+func (tm *TransactionManager) Commit(txn *Transaction) error {
+	if txn.state != TransactionRunning {
+		return ErrTransactionNotRunning
+	}
+
+	err := tm.appendLog(txn, log_manager.RecordCommit)
+	if err != nil {
+		return err
+	}
+
+	err = tm.lm.Flush(txn.prevLSN)
+	if err != nil {
+		return err
+	}
+
+	txn.state = TransactionCommitted
+	txn.undoActions = nil
+
+	for _, action := range txn.commitActions {
+		err = action()
+		if err != nil {
+			return err
+		}
+	}
+
+	txn.commitActions = nil
+	return nil
+}
+
+func (tm *TransactionManager) Abort(txn *Transaction) error {
+	if txn.state != TransactionRunning {
+		return ErrTransactionNotRunning
+	}
+
+	txn.state = TransactionAborted
+	txn.commitActions = nil
+
+	for i := len(txn.undoActions) - 1; i >= 0; i-- {
+		err := txn.undoActions[i]()
+		if err != nil {
+			return err
+		}
+	}
+
+	txn.undoActions = nil
+
+	return tm.appendLog(txn, log_manager.RecordAbort)
+}
+
+func (tm *TransactionManager) appendLog(txn *Transaction, recordType log_manager.RecordType) error {
+	lsn, err := tm.lm.AppendRecord(&log_manager.LogRecord{
+		TxnID:   txn.id,
+		PrevLSN: txn.prevLSN,
+		Type:    recordType,
+	})
+	if err != nil {
+		return err
+	}
+
+	txn.prevLSN = lsn
+	return nil
+}
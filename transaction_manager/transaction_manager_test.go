@@ -0,0 +1,152 @@
+package transaction_manager
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"gobase/log_manager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransactionManager(t *testing.T) (*TransactionManager, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "transaction_manager_test")
+	require.NoError(t, err)
+	tmpFile.Close()
+
+	lm, err := log_manager.NewLogManager(tmpFile.Name())
+	require.NoError(t, err)
+
+	cleanup := func() {
+		lm.Close()
+		os.Remove(tmpFile.Name())
+	}
+
+	return NewTransactionManager(lm), cleanup
+}
+
+func TestBegin(t *testing.T) {
+	tm, cleanup := newTestTransactionManager(t)
+	defer cleanup()
+
+	txn1, err := tm.Begin()
+	require.NoError(t, err)
+	txn2, err := tm.Begin()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, txn1.GetID(), txn2.GetID())
+	assert.Equal(t, TransactionRunning, txn1.GetState())
+	assert.NotEqual(t, log_manager.INVALID_LSN, txn1.GetPrevLSN())
+}
+
+func TestCommit(t *testing.T) {
+	tm, cleanup := newTestTransactionManager(t)
+	defer cleanup()
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+
+	committed := false
+	txn.AddUndoAction(func() error {
+		t.Fatal("undo action must not run on commit")
+		return nil
+	})
+	txn.AddCommitAction(func() error {
+		committed = true
+		return nil
+	})
+
+	require.NoError(t, tm.Commit(txn))
+	assert.True(t, committed)
+	assert.Equal(t, TransactionCommitted, txn.GetState())
+	assert.Equal(t, txn.GetPrevLSN(), tm.lm.GetFlushedLSN())
+
+	records, err := tm.lm.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, log_manager.RecordBegin, records[0].Type)
+	assert.Equal(t, log_manager.RecordCommit, records[1].Type)
+	assert.Equal(t, records[0].LSN, records[1].PrevLSN)
+}
+
+func TestAbort_RunsUndoActionsInReverse(t *testing.T) {
+	tm, cleanup := newTestTransactionManager(t)
+	defer cleanup()
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+
+	order := []int{}
+	for i := 0; i < 3; i++ {
+		i := i
+		txn.AddUndoAction(func() error {
+			order = append(order, i)
+			return nil
+		})
+	}
+	txn.AddCommitAction(func() error {
+		t.Fatal("commit action must not run on abort")
+		return nil
+	})
+
+	require.NoError(t, tm.Abort(txn))
+	assert.Equal(t, []int{2, 1, 0}, order)
+	assert.Equal(t, TransactionAborted, txn.GetState())
+
+	records, err := tm.lm.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, log_manager.RecordAbort, records[1].Type)
+}
+
+func TestAbort_IgnoresActionsAddedWhileAborting(t *testing.T) {
+	tm, cleanup := newTestTransactionManager(t)
+	defer cleanup()
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+
+	calls := 0
+	txn.AddUndoAction(func() error {
+		calls++
+		txn.AddUndoAction(func() error {
+			calls++
+			return nil
+		})
+		return nil
+	})
+
+	require.NoError(t, tm.Abort(txn))
+	assert.Equal(t, 1, calls)
+}
+
+func TestAbort_UndoActionFails(t *testing.T) {
+	tm, cleanup := newTestTransactionManager(t)
+	defer cleanup()
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+
+	undoErr := errors.New("undo failed")
+	txn.AddUndoAction(func() error {
+		return undoErr
+	})
+
+	require.ErrorIs(t, tm.Abort(txn), undoErr)
+}
+
+func TestCommit_NotRunning(t *testing.T) {
+	tm, cleanup := newTestTransactionManager(t)
+	defer cleanup()
+
+	txn, err := tm.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tm.Commit(txn))
+
+	require.ErrorIs(t, tm.Commit(txn), ErrTransactionNotRunning)
+	require.ErrorIs(t, tm.Abort(txn), ErrTransactionNotRunning)
+}